@@ -23,6 +23,7 @@ package gosuki
 
 // Bookmark type
 type Bookmark struct {
+	ID       uint64   `json:"id"`
 	URL      string   `json:"url"`
 	Title    string   `json:"metadata"`
 	Tags     []string `json:"tags"`
@@ -30,5 +31,31 @@ type Bookmark struct {
 	Module   string   `json:"module"`
 	Version  uint64   `json:"version"`
 	Modified uint64   `json:"modified"`
-	//flags int
+
+	// bitmask of user flags, see Flags
+	Flags int `json:"flags,omitempty"`
+
+	// time the bookmark was first saved, taken from the browser when
+	// available
+	Created uint64 `json:"created_at,omitempty"`
+
+	// read later queue, only set by the read later queries. ReadAt is 0 for
+	// unread bookmarks.
+	QueuedAt uint64 `json:"queued_at,omitempty"`
+	ReadAt   uint64 `json:"read_at,omitempty"`
+
+	// modules and browser profiles that saved the bookmark, only set when
+	// requested
+	Sources []Source `json:"sources,omitempty"`
+}
+
+// Source is a module or browser profile that saved a bookmark
+type Source struct {
+	Module string `json:"module"`
+
+	// time the bookmark was added in the source, 0 when unknown
+	Created uint64 `json:"created_at,omitempty"`
+
+	// time the bookmark was first synced from the source
+	Seen uint64 `json:"seen_at"`
 }
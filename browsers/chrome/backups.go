@@ -0,0 +1,122 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package chrome
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/browsers"
+	"github.com/blob42/gosuki/pkg/modules"
+	"github.com/blob42/gosuki/pkg/profiles"
+)
+
+// BackupFile is the copy of the Bookmarks file made by Chromium browsers
+// when they start
+const BackupFile = "Bookmarks.bak"
+
+// jsonNode is a node of the Bookmarks file
+type jsonNode struct {
+	Type      string      `json:"type"`
+	Name      string      `json:"name"`
+	URL       string      `json:"url"`
+	DateAdded string      `json:"date_added"`
+	Children  []*jsonNode `json:"children"`
+}
+
+// Backups returns the Bookmarks.bak file of the profile if it exists
+func (*Chrome) Backups(p *profiles.Profile) ([]browsers.Backup, error) {
+	dir, err := p.AbsolutePath()
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, BackupFile)
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return []browsers.Backup{{Path: path, Time: info.ModTime()}}, nil
+}
+
+func (*Chrome) LoadBackup(b browsers.Backup) ([]*gosuki.Bookmark, error) {
+	data, err := os.ReadFile(b.Path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBookmarks(data)
+}
+
+// ParseBookmarks returns the bookmarks of a Bookmarks file. The names of the
+// folders, except the root folders, are added to the tags of their bookmarks.
+func ParseBookmarks(data []byte) ([]*gosuki.Bookmark, error) {
+	var file struct {
+		Roots map[string]json.RawMessage `json:"roots"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing bookmarks: %w", err)
+	}
+
+	var bookmarks []*gosuki.Bookmark
+	var walk func(node *jsonNode, folders []string)
+	walk = func(node *jsonNode, folders []string) {
+		switch node.Type {
+		case "url":
+			bookmarks = append(bookmarks, &gosuki.Bookmark{
+				URL:     node.URL,
+				Title:   node.Name,
+				Tags:    slices.Clone(folders),
+				Created: chromeTime([]byte(node.DateAdded)),
+			})
+		case "folder":
+			folders = append(folders, node.Name)
+			for _, child := range node.Children {
+				walk(child, folders)
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(file.Roots)) {
+		// roots also has a sync_transaction_version string
+		var root jsonNode
+		if json.Unmarshal(file.Roots[name], &root) != nil {
+			continue
+		}
+		for _, child := range root.Children {
+			walk(child, nil)
+		}
+	}
+
+	return bookmarks, nil
+}
+
+// interface guards
+var _ modules.BackupReader = (*Chrome)(nil)
@@ -0,0 +1,29 @@
+package chrome
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBookmarks(t *testing.T) {
+	data, err := os.ReadFile("testdata/Bookmarks")
+	require.NoError(t, err)
+
+	bookmarks, err := ParseBookmarks(data)
+	require.NoError(t, err)
+	require.NotEmpty(t, bookmarks)
+
+	urls := map[string][]string{}
+	for _, bk := range bookmarks {
+		urls[bk.URL] = bk.Tags
+	}
+	// bookmarks of the root folders have no tags, the others have their
+	// folder names
+	require.Contains(t, urls, "https://Llu7Yy64nT.com")
+	assert.Empty(t, urls["https://Llu7Yy64nT.com"])
+	require.Contains(t, urls, "https://Uljfwm3Ywt.com")
+	assert.Equal(t, []string{"elolR1L5A8"}, urls["https://Uljfwm3Ywt.com"])
+}
@@ -33,6 +33,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/OneOfOne/xxhash"
@@ -76,6 +77,7 @@ type RawNode struct {
 	url          []byte
 	children     []byte
 	childrenType jsonparser.ValueType
+	dateAdded    []byte
 }
 
 func (rawNode *RawNode) parseItems(nodeData []byte) {
@@ -86,6 +88,7 @@ func (rawNode *RawNode) parseItems(nodeData []byte) {
 		{"name"}, // Title of page
 		{"url"},
 		{"children"},
+		{"date_added"},
 	}
 
 	jsonparser.EachKey(nodeData, func(idx int, value []byte, vt jsonparser.ValueType, err error) {
@@ -105,6 +108,8 @@ func (rawNode *RawNode) parseItems(nodeData []byte) {
 			rawNode.url = value
 		case 3:
 			rawNode.children, rawNode.childrenType = value, vt
+		case 4:
+			rawNode.dateAdded = value
 		}
 	}, paths...)
 }
@@ -119,6 +124,7 @@ func (rawNode *RawNode) getNode(ch *Chrome) *tree.Node {
 	node.Type = nType
 
 	node.Title = string(rawNode.title)
+	node.Created = chromeTime(rawNode.dateAdded)
 	modName := ch.Name
 
 	if ch.activeFlavour != nil {
@@ -133,6 +139,23 @@ func (rawNode *RawNode) getNode(ch *Chrome) *tree.Node {
 	return node
 }
 
+// chromeTime converts a chrome timestamp, in microseconds since 1601-01-01
+// UTC, to a unix time. It returns 0 for invalid or future timestamps.
+func chromeTime(value []byte) uint64 {
+	micros, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	// seconds between 1601-01-01 and 1970-01-01
+	const epochDelta = 11644473600
+	secs := micros/1_000_000 - epochDelta
+	if secs <= 0 || secs > time.Now().Unix() {
+		return 0
+	}
+	return uint64(secs)
+}
+
 // Chrome browser module
 type Chrome struct {
 	// holds browsers.BrowserConfig
@@ -381,18 +404,16 @@ func (ch *Chrome) run(runTask bool) {
 			progress := ch.Progress()
 			if progress-ch.lastSentProgress >= 0.05 || progress == 1 {
 				ch.lastSentProgress = progress
-				go func() {
-					msg := events.ProgressUpdateMsg{
-						ID:           ch.ModInfo().ID,
-						Instance:     ch,
-						CurrentCount: ch.URLCount(),
-						Total:        ch.Total(),
-					}
-					if runTask {
-						msg.NewBk = true
-					}
-					events.TUIBus <- msg
-				}()
+				msg := events.ProgressUpdateMsg{
+					ID:           ch.ModInfo().ID,
+					Instance:     ch,
+					CurrentCount: ch.URLCount(),
+					Total:        ch.Total(),
+				}
+				if runTask {
+					msg.NewBk = true
+				}
+				events.TUI.Publish(msg)
 			}
 
 			// Check if url-node already in index
@@ -518,12 +539,10 @@ func (ch *Chrome) PreLoad(_ *modules.Context) error {
 	ch.SetTotal(preCountCountUrls(bookmarkPath))
 
 	// Send total to msg bus
-	go func() {
-		events.TUIBus <- events.StartedLoadingMsg{
-			ID:    modules.ModID(ch.Name),
-			Total: ch.Total(),
-		}
-	}()
+	events.TUI.Publish(events.StartedLoadingMsg{
+		ID:    modules.ModID(ch.Name),
+		Total: ch.Total(),
+	})
 
 	go ch.run(false)
 	return nil
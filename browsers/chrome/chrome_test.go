@@ -114,6 +114,15 @@ func TestPreCount(t *testing.T) {
 	assert.EqualValues(t, 2007, int(total), "wrong # of url count")
 }
 
+func TestChromeTime(t *testing.T) {
+	// 2017-10-11T07:53:35Z
+	assert.EqualValues(t, 1507708415, chromeTime([]byte("13152182015000000")))
+	assert.Zero(t, chromeTime([]byte("0")))
+	assert.Zero(t, chromeTime([]byte("")))
+	// unix time in nanoseconds written by some tools
+	assert.Zero(t, chromeTime([]byte("1732378959581067040")))
+}
+
 func BenchmarkRun(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		ch.Run()
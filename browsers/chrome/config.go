@@ -81,7 +81,7 @@ func NewChromeConfig() *ChromeConfig {
 				Type:   tree.RootNode,
 			},
 			UseFileWatcher: true,
-			UseHooks:       []string{"node_tags_from_name", "node_marktab"},
+			UseHooks:       []string{"node_tags_from_name", "node_read_later", "node_marktab"},
 		},
 		ProfilePrefs: modules.ProfilePrefs{
 			Profile:          DefaultProfile,
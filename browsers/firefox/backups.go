@@ -0,0 +1,47 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package firefox
+
+import (
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/browsers"
+	"github.com/blob42/gosuki/pkg/browsers/mozilla"
+	"github.com/blob42/gosuki/pkg/modules"
+	"github.com/blob42/gosuki/pkg/profiles"
+)
+
+// Backups lists the daily backups of the bookmarkbackups directory
+func (*Firefox) Backups(p *profiles.Profile) ([]browsers.Backup, error) {
+	dir, err := p.AbsolutePath()
+	if err != nil {
+		return nil, err
+	}
+	return mozilla.ListBackups(dir)
+}
+
+func (*Firefox) LoadBackup(b browsers.Backup) ([]*gosuki.Bookmark, error) {
+	return mozilla.LoadBackup(b.Path)
+}
+
+// interface guards
+var _ modules.BackupReader = (*Firefox)(nil)
@@ -153,6 +153,9 @@ func (f *Firefox) loadBookmarksToTree(bookmarks []*MozBookmark, runTask bool) {
 	for _, bkEntry := range bookmarks {
 		// Create/Update URL node and apply tag node
 		created, urlNode := f.addURLNode(bkEntry.URL, bkEntry.Title, bkEntry.PlDesc)
+		if bkEntry.DateAdded > 0 {
+			urlNode.Created = uint64(bkEntry.DateAdded / 1_000_000)
+		}
 		if !created {
 			log.Debugf("url <%s> already in url index", bkEntry.URL)
 		} else {
@@ -160,18 +163,16 @@ func (f *Firefox) loadBookmarksToTree(bookmarks []*MozBookmark, runTask bool) {
 			progress := f.Progress()
 			if progress-f.lastSentProgress >= 0.05 || progress == 1 {
 				f.lastSentProgress = progress
-				go func() {
-					msg := events.ProgressUpdateMsg{
-						ID:           f.ModInfo().ID,
-						Instance:     f,
-						CurrentCount: f.URLCount(),
-						Total:        f.Total(),
-					}
-					if runTask {
-						msg.NewBk = true
-					}
-					events.TUIBus <- msg
-				}()
+				msg := events.ProgressUpdateMsg{
+					ID:           f.ModInfo().ID,
+					Instance:     f,
+					CurrentCount: f.URLCount(),
+					Total:        f.Total(),
+				}
+				if runTask {
+					msg.NewBk = true
+				}
+				events.TUI.Publish(msg)
 			}
 		}
 
@@ -433,12 +434,10 @@ func (f *Firefox) PreLoad(_ *modules.Context) error {
 	f.SetTotal(uint(len(bookmarks)))
 
 	// Send total to msg bus
-	go func() {
-		events.TUIBus <- events.StartedLoadingMsg{
-			ID:    modules.ModID(f.Name),
-			Total: f.Total(),
-		}
-	}()
+	events.TUI.Publish(events.StartedLoadingMsg{
+		ID:    modules.ModID(f.Name),
+		Total: f.Total(),
+	})
 
 	f.loadBookmarksToTree(bookmarks, false)
 
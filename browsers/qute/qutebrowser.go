@@ -84,12 +84,10 @@ func (qu *Qute) preCountUrls() error {
 	qu.AddTotal(uint(count))
 
 	// Send total to msg bus
-	go func() {
-		events.TUIBus <- events.StartedLoadingMsg{
-			ID:    modules.ModID(qu.Name),
-			Total: qu.Total(),
-		}
-	}()
+	events.TUI.Publish(events.StartedLoadingMsg{
+		ID:    modules.ModID(qu.Name),
+		Total: qu.Total(),
+	})
 
 	return nil
 }
@@ -238,7 +236,9 @@ func (qu *Qute) loadBookmarks(runTask bool) error {
 			Module: qu.Name,
 		}
 
-		qu.CallHooks(bk)
+		if err := qu.CallHooks(bk); errors.Is(err, hooks.ErrDrop) {
+			continue
+		}
 
 		qu.BufferDB.UpsertBookmark(bk)
 		qu.IncURLCount()
@@ -280,7 +280,9 @@ func (qu *Qute) loadQuickMarks(runTask bool) error {
 
 		// Call hooks on bookmark instead of node
 		err = qu.CallHooks(bk)
-		if err != nil {
+		if errors.Is(err, hooks.ErrDrop) {
+			continue
+		} else if err != nil {
 			return err
 		}
 
@@ -297,18 +299,16 @@ func (qu *Qute) trackProgress(runTask bool) {
 	progress := qu.Progress()
 	if progress-qu.lastSentProgress >= 0.05 || progress == 1 {
 		qu.lastSentProgress = progress
-		go func() {
-			msg := events.ProgressUpdateMsg{
-				ID:           qu.ModInfo().ID,
-				Instance:     qu,
-				CurrentCount: qu.URLCount(),
-				Total:        qu.Total(),
-			}
-			if runTask {
-				msg.NewBk = true
-			}
-			events.TUIBus <- msg
-		}()
+		msg := events.ProgressUpdateMsg{
+			ID:           qu.ModInfo().ID,
+			Instance:     qu,
+			CurrentCount: qu.URLCount(),
+			Total:        qu.Total(),
+		}
+		if runTask {
+			msg.NewBk = true
+		}
+		events.TUI.Publish(msg)
 	}
 }
 
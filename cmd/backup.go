@@ -0,0 +1,273 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/browsers"
+	"github.com/blob42/gosuki/pkg/modules"
+	"github.com/blob42/gosuki/pkg/profiles"
+)
+
+var importBackupCmd = &cli.Command{
+	Name:  "backup",
+	Usage: "Restore bookmarks from the backups kept by the browsers",
+	Description: `Firefox keeps daily backups of the bookmarks in the bookmarkbackups directory
+of the profiles and Chromium browsers keep a Bookmarks.bak copy. These backups
+can bring back bookmarks lost to a corrupted profile.
+
+List the backups of the detected profiles, compare one with the gosuki database
+then restore it:
+
+    gosuki import backup list
+    gosuki import backup diff 2
+    gosuki import backup restore --missing 2
+
+A backup is chosen by its number in the list or by its path. Restored
+bookmarks are saved under the <flavour>-backup module. Folder names are kept
+as tags.`,
+	Commands: []*cli.Command{
+		{
+			Name:   "list",
+			Usage:  "List the bookmark backups of the detected profiles, most recent first",
+			Action: listBackups,
+		},
+		{
+			Name:      "diff",
+			Usage:     "List the bookmarks of a backup missing from the database",
+			ArgsUsage: "<number|path>",
+			Arguments: []cli.Argument{backupArg},
+			Action:    diffBackup,
+		},
+		{
+			Name:      "restore",
+			Usage:     "Import the bookmarks of a backup",
+			ArgsUsage: "<number|path>",
+			Arguments: []cli.Argument{backupArg},
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "missing",
+					Usage: "Only import the bookmarks missing from the database",
+				},
+				dryRunFlag,
+			},
+			Action: restoreBackup,
+		},
+	},
+}
+
+var backupArg = &cli.StringArg{
+	Name:      "backup",
+	UsageText: "Number of the backup in the list or path of the backup file",
+	Config: cli.StringConfig{
+		TrimSpace: true,
+	},
+}
+
+// profileBackup is a backup of a detected browser profile
+type profileBackup struct {
+	browsers.Backup
+	reader  modules.BackupReader
+	flavour string
+	profile string
+}
+
+// module returns the module name of the restored bookmarks
+func (b *profileBackup) module() string {
+	return b.flavour + "-backup"
+}
+
+// findBackups lists the backups of the profiles of all the detected browsers
+// implementing [modules.BackupReader], most recent first
+func findBackups() []*profileBackup {
+	var result []*profileBackup
+	for _, br := range modules.GetBrowserModules() {
+		mod := br.ModInfo().New()
+		reader, ok := mod.(modules.BackupReader)
+		if !ok {
+			continue
+		}
+		pm, ok := mod.(profiles.ProfileManager)
+		if !ok {
+			continue
+		}
+
+		for _, flv := range pm.ListFlavours() {
+			profs, err := pm.GetProfiles(flv.Flavour)
+			if err != nil {
+				log.Debugf("listing %s profiles: %s", flv.Flavour, err)
+				continue
+			}
+			for _, p := range profs {
+				backups, err := reader.Backups(p)
+				if err != nil {
+					log.Debugf("listing backups of %s profile %s: %s", flv.Flavour, p.Name, err)
+					continue
+				}
+				for _, b := range backups {
+					result = append(result, &profileBackup{
+						Backup:  b,
+						reader:  reader,
+						flavour: flv.Flavour,
+						profile: p.Name,
+					})
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b *profileBackup) int {
+		return b.Time.Compare(a.Time)
+	})
+	return result
+}
+
+// chosenBackup returns the backup given by its number in the list or its path
+func chosenBackup(c *cli.Command) (*profileBackup, error) {
+	arg := c.StringArg(backupArg.Name)
+	if arg == "" {
+		return nil, errors.New("missing backup number or path, see 'gosuki import backup list'")
+	}
+
+	backups := findBackups()
+	if n, err := strconv.Atoi(arg); err == nil {
+		if n < 1 || n > len(backups) {
+			return nil, fmt.Errorf("no backup number %d, see 'gosuki import backup list'", n)
+		}
+		return backups[n-1], nil
+	}
+	for _, b := range backups {
+		if b.Path == arg {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("%s is not a backup of a detected profile", arg)
+}
+
+func listBackups(_ context.Context, _ *cli.Command) error {
+	backups := findBackups()
+	if len(backups) == 0 {
+		fmt.Println("no bookmark backups found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tDATE\tBROWSER\tPROFILE\tBOOKMARKS\tPATH")
+	for i, b := range backups {
+		count := "-"
+		if b.Count > 0 {
+			count = strconv.Itoa(b.Count)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", i+1,
+			b.Time.Format("2006-01-02 15:04"), b.flavour, b.profile, count, b.Path)
+	}
+	return w.Flush()
+}
+
+// loadBackup reads the bookmarks of the backup and marks those missing from
+// the on-disk db. The caller closes the db.
+func loadBackup(ctx context.Context, c *cli.Command, b *profileBackup) (bookmarks []*gosuki.Bookmark, missing []bool, err error) {
+	bookmarks, err = b.reader.LoadBackup(b.Backup)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading %s: %w", b.Path, err)
+	}
+
+	db.Init(ctx, c)
+	for _, bk := range bookmarks {
+		_, err := db.GetBookmarkByURL(ctx, bk.URL)
+		if err != nil && !errors.Is(err, db.ErrBookmarkNotFound) {
+			db.DiskDB.Close()
+			return nil, nil, err
+		}
+		missing = append(missing, err != nil)
+	}
+	return bookmarks, missing, nil
+}
+
+func diffBackup(ctx context.Context, c *cli.Command) error {
+	b, err := chosenBackup(c)
+	if err != nil {
+		return err
+	}
+	bookmarks, missing, err := loadBackup(ctx, c, b)
+	if err != nil {
+		return err
+	}
+	defer db.DiskDB.Close()
+
+	count := 0
+	for i, bk := range bookmarks {
+		if missing[i] {
+			count++
+			fmt.Printf("+ %s\t%s\n", bk.URL, bk.Title)
+		}
+	}
+	fmt.Printf("\n%s backup of %s: %d bookmarks, %d missing from the database\n",
+		b.flavour, b.Time.Format(time.DateOnly), len(bookmarks), count)
+	return nil
+}
+
+func restoreBackup(ctx context.Context, c *cli.Command) error {
+	b, err := chosenBackup(c)
+	if err != nil {
+		return err
+	}
+	bookmarks, missing, err := loadBackup(ctx, c, b)
+	if err != nil {
+		return err
+	}
+	defer db.DiskDB.Close()
+
+	var items []importItem
+	for i, bk := range bookmarks {
+		if c.Bool("missing") && !missing[i] {
+			continue
+		}
+		bk.Module = b.module()
+		item := importItem{bookmark: bk}
+		if bk.Created != 0 {
+			item.added = time.Unix(int64(bk.Created), 0)
+		}
+		items = append(items, item)
+	}
+
+	if c.Bool(dryRunFlag.Name) {
+		printImportSummary(b.Path, b.module(), items)
+		return nil
+	}
+
+	fmt.Printf("restoring %s\n", b.Path)
+	return saveItems(ctx, items)
+}
@@ -0,0 +1,202 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki/internal/ctl"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/manager"
+)
+
+var CtlCmds = &cli.Command{
+	Name:  "ctl",
+	Usage: "control the running gosuki daemon",
+	Description: `Send commands to a running gosuki daemon through its control socket.
+The socket is created next to the database file when running 'gosuki start'.`,
+	Commands: []*cli.Command{
+		ctlStatusCmd,
+		ctlSyncCmd,
+		ctlReloadCmd,
+		ctlStopCmd,
+		ctlStartCmd,
+	},
+}
+
+// StatusCmd shows the status of the running daemon
+var StatusCmd = &cli.Command{
+	Name:   "status",
+	Usage:  "show the daemon, module and unit status",
+	Action: statusAction,
+}
+
+var ctlStatusCmd = &cli.Command{
+	Name:   "status",
+	Usage:  "show the daemon, module and unit status",
+	Action: statusAction,
+}
+
+func statusAction(ctx context.Context, cmd *cli.Command) error {
+	client, err := dialDaemon()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	status, err := client.Status()
+	if err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	fmt.Printf("gosuki %s running\n\nmodules:\n", status.Version)
+	for _, mod := range status.Modules {
+		state := green("enabled")
+		if !mod.Enabled {
+			state = red("disabled")
+		}
+		kind := "module"
+		if mod.Browser {
+			kind = "browser"
+		}
+		fmt.Printf("  %-15s\t%-8s\t%s\n", mod.ID, kind, state)
+	}
+
+	fmt.Printf("\nunits:\n")
+	return printUnits(status.Units)
+}
+
+func printUnits(units []manager.UnitStatus) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  UNIT\tSTATE\tUPTIME\tRESTARTS\tLAST ERROR")
+	for _, unit := range units {
+		uptime := "-"
+		if unit.State == manager.UnitRunning {
+			uptime = time.Since(unit.Since).Truncate(time.Second).String()
+		}
+
+		lastErr := "-"
+		if unit.LastError != "" {
+			lastErr = fmt.Sprintf("%s (%s ago)", unit.LastError,
+				time.Since(unit.LastErrorAt).Truncate(time.Second))
+		}
+
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%d\t%s\n",
+			unit.Name,
+			colorUnitState(unit.State),
+			uptime,
+			unit.Restarts,
+			lastErr,
+		)
+	}
+	return w.Flush()
+}
+
+func colorUnitState(state manager.UnitState) string {
+	switch state {
+	case manager.UnitRunning:
+		return color.GreenString(string(state))
+	case manager.UnitRestarting:
+		return color.YellowString(string(state))
+	case manager.UnitFailed:
+		return color.RedString(string(state))
+	default:
+		return string(state)
+	}
+}
+
+var ctlSyncCmd = &cli.Command{
+	Name:  "sync",
+	Usage: "write the daemon cache to disk now",
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		client, err := dialDaemon()
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		return client.Sync()
+	},
+}
+
+var ctlReloadCmd = &cli.Command{
+	Name:  "reload",
+	Usage: "reload the config file",
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		client, err := dialDaemon()
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		return client.ReloadConfig()
+	},
+}
+
+var ctlStopCmd = &cli.Command{
+	Name:      "stop",
+	Usage:     "stop a unit of the daemon",
+	ArgsUsage: "<unit>",
+	Description: `Stop the units with the given name without shutting down the daemon.
+Unit names are listed by 'gosuki status'.`,
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		return unitAction(cmd, (*ctl.Client).StopUnit)
+	},
+}
+
+var ctlStartCmd = &cli.Command{
+	Name:      "start",
+	Usage:     "start a stopped or failed unit of the daemon",
+	ArgsUsage: "<unit>",
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		return unitAction(cmd, (*ctl.Client).StartUnit)
+	},
+}
+
+func unitAction(cmd *cli.Command, fn func(*ctl.Client, string) error) error {
+	if cmd.Args().Len() != 1 {
+		return fmt.Errorf("expected a unit name")
+	}
+
+	client, err := dialDaemon()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return fn(client, cmd.Args().First())
+}
+
+func dialDaemon() (*ctl.Client, error) {
+	return ctl.Dial(ctl.SocketPath(config.DBPath))
+}
@@ -0,0 +1,113 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v3"
+
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+var DedupeCmd = &cli.Command{
+	Name:  "dedupe",
+	Usage: "find and merge duplicate bookmarks",
+	Description: `Bookmarks are duplicates when their urls have the same canonical form, as
+in http://www.example.com/page/ and https://example.com/page?utm_source=feed.
+The canonical form is configured in the [urls] section of the config file.
+
+Without --merge the duplicates are only listed. With --merge each group is
+merged into its oldest bookmark: the tags of the group are combined, the
+description and flags of the newer bookmarks are kept when the oldest has none,
+and the newer bookmarks are deleted.`,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "merge",
+			Usage: "merge the duplicates",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		merge := cmd.Bool("merge")
+
+		dups, err := dedupe(ctx, cmd, merge)
+		if err != nil {
+			return err
+		}
+
+		if len(dups) == 0 {
+			fmt.Println("no duplicates found")
+			return nil
+		}
+
+		removed := 0
+		for _, d := range dups {
+			fmt.Println(color.New(color.Bold).Sprint(d.Canonical))
+			for i, bk := range d.Bookmarks {
+				mark := "  "
+				if i == 0 {
+					mark = color.GreenString("* ")
+				} else {
+					removed++
+				}
+				fmt.Printf("  %s%d\t%s\t%s\n", mark, bk.ID,
+					time.Unix(int64(bk.Created), 0).Format(time.DateOnly), bk.URL)
+			}
+		}
+
+		if merge {
+			fmt.Printf("\nmerged %d duplicates into %d bookmarks\n", removed, len(dups))
+		} else {
+			fmt.Printf("\n%d duplicates of %d bookmarks, run with --merge to merge them into the bookmarks marked with *\n",
+				removed, len(dups))
+		}
+		return nil
+	},
+}
+
+// dedupe finds and optionally merges the duplicates through the running
+// daemon or in the database if the daemon is not running.
+func dedupe(ctx context.Context, cmd *cli.Command, merge bool) ([]db.Duplicates, error) {
+	if client, err := dialDaemon(); err == nil {
+		defer client.Close()
+		return client.Dedupe(merge)
+	}
+
+	db.Init(ctx, cmd)
+	defer db.DiskDB.Close()
+
+	dups, err := db.FindDuplicates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if merge {
+		if err = db.MergeDuplicates(ctx, dups); err != nil {
+			return nil, err
+		}
+	}
+	return dups, nil
+}
@@ -24,6 +24,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"html"
 	"os"
@@ -32,7 +33,9 @@ import (
 	"github.com/urfave/cli/v3"
 
 	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/ctl"
 	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/internal/feeds"
 )
 
 var ExportCmds = &cli.Command{
@@ -41,6 +44,7 @@ var ExportCmds = &cli.Command{
 	Description: `The export command provides functionality to export bookmarks to other browser or application formats. `,
 	Commands: []*cli.Command{
 		exportHTMLCmd,
+		exportOPMLCmd,
 	},
 }
 
@@ -65,9 +69,49 @@ var exportHTMLCmd = &cli.Command{
 			Aliases: []string{"f"},
 			Usage:   "Overwrite existing files without prompting",
 		},
+		includePrivateFlag,
 	},
 }
 
+var exportOPMLCmd = &cli.Command{
+	Name:  "opml",
+	Usage: "Export bookmarks to OPML grouped by tag",
+	Description: `Exports all bookmarks to an OPML document with an outline per tag. A
+bookmark with several tags is listed under each of them.
+
+With --server-url, each tag outline also links to the Atom feed of the tag
+published by the web server, so the file can be imported in a feed reader to
+follow the tags.`,
+	ArgsUsage: "path/to/export.opml",
+	Action:    exportToOPML,
+	Arguments: []cli.Argument{
+		&cli.StringArg{
+			Name:      "path",
+			UsageText: "Path of the OPML file, - for stdout",
+			Config: cli.StringConfig{
+				TrimSpace: true,
+			},
+		},
+	},
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "force",
+			Aliases: []string{"f"},
+			Usage:   "Overwrite existing files without prompting",
+		},
+		&cli.StringFlag{
+			Name:  "server-url",
+			Usage: "Base `URL` of the gosuki web server used to link the tag feeds, e.g. http://localhost:2025",
+		},
+		includePrivateFlag,
+	},
+}
+
+var includePrivateFlag = &cli.BoolFlag{
+	Name:  "include-private",
+	Usage: "Export the bookmarks flagged as private",
+}
+
 func exportToHTML(ctx context.Context, c *cli.Command) error {
 	path := c.StringArg("path")
 
@@ -75,17 +119,12 @@ func exportToHTML(ctx context.Context, c *cli.Command) error {
 		return fmt.Errorf("file %s already exists. Use -f to overwrite", path)
 	}
 
-	db.Init(ctx, c)
-	var rawResults db.RawBookmarks
-
-	err := db.DiskDB.Handle.SelectContext(ctx,
-		&rawResults,
-		`SELECT * FROM gskbookmarks`)
+	bookmarks, err := exportedBookmarks(ctx, c)
 	if err != nil {
 		return err
 	}
 
-	htmlContent := generateNetscapeHTML(rawResults.AsBookmarks())
+	htmlContent := generateNetscapeHTML(bookmarks)
 
 	if path == "-" {
 		if _, err = fmt.Fprint(os.Stdout, htmlContent); err != nil {
@@ -101,6 +140,66 @@ func exportToHTML(ctx context.Context, c *cli.Command) error {
 	return nil
 }
 
+func exportToOPML(ctx context.Context, c *cli.Command) error {
+	path := c.StringArg("path")
+	if path == "" {
+		return errors.New("missing path to export file")
+	}
+
+	if _, err := os.Stat(path); err == nil && !c.Bool("force") {
+		return fmt.Errorf("file %s already exists. Use -f to overwrite", path)
+	}
+
+	bookmarks, err := exportedBookmarks(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	if path == "-" {
+		return feeds.WriteOPML(os.Stdout, bookmarks, c.String("server-url"))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = feeds.WriteOPML(f, bookmarks, c.String("server-url")); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write to %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// exportedBookmarks returns all bookmarks from the running daemon or from the
+// database if the daemon is not running. Private bookmarks are excluded unless
+// the --include-private flag is set.
+func exportedBookmarks(ctx context.Context, c *cli.Command) ([]*gosuki.Bookmark, error) {
+	bookmarks, err := allBookmarks(ctx, c)
+	if err != nil || c.Bool(includePrivateFlag.Name) {
+		return bookmarks, err
+	}
+	return db.ExcludePrivate(bookmarks), nil
+}
+
+func allBookmarks(ctx context.Context, c *cli.Command) ([]*gosuki.Bookmark, error) {
+	if client, err := dialDaemon(); err == nil {
+		defer client.Close()
+		return client.Query(ctl.QueryArgs{All: true})
+	}
+
+	db.Init(ctx, c)
+	var rawResults db.RawBookmarks
+
+	err := db.DiskDB.Handle.SelectContext(ctx,
+		&rawResults,
+		`SELECT * FROM gskbookmarks`)
+	if err != nil {
+		return nil, err
+	}
+
+	return rawResults.AsBookmarks(), nil
+}
+
 func generateNetscapeHTML(bookmarks []*gosuki.Bookmark) string {
 	var sb strings.Builder
 	sb.WriteString(`<!DOCTYPE NETSCAPE-Bookmark-file-1>
@@ -25,6 +25,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/blob42/gosuki/pkg/browsers"
 	"github.com/blob42/gosuki/pkg/config"
@@ -101,9 +103,7 @@ func runBrowserModule(m *manager.Manager,
 		return errors.New("must implement watch.WatchRunner interface")
 	}
 
-	go func() {
-		events.TUIBus <- events.RunnerStarted{WatchRunner: runner}
-	}()
+	events.TUI.Publish(events.RunnerStarted{WatchRunner: runner})
 
 	// calls the setup logic for each browser instance
 	//PERF:
@@ -127,7 +127,7 @@ func runBrowserModule(m *manager.Manager,
 		WatchRunner: runner,
 	}
 
-	m.AddUnit(worker, unitName)
+	addModuleUnit(m, mod.ID, worker, unitName)
 
 	return nil
 }
@@ -145,162 +145,213 @@ func bootstrapModules(ctx context.Context, cmd *cli.Command, mngr *manager.Manag
 		go m.Start()
 	}(mngr)
 
+	startReloader(ctx, cmd, mngr)
+
 	// Handle generic modules
 	mods := modules.GetModules()
 	for _, mod := range mods {
-		name := mod.ModInfo().ID
-		modInstance := mod.ModInfo().New()
-		if _, ok := modInstance.(modules.BrowserModule); ok {
-			log.Debugf("skipping non browser module")
-			continue
-		}
+		startModule(ctx, cmd, mngr, mod)
+	}
 
-		log.Debugf("starting <%s>", name)
+	registeredBrowsers := modules.GetBrowserModules()
 
-		modContext := &modules.Context{
-			Context: ctx,
-			Cli:     cmd,
-		}
+	// start all registered browser modules
+	for _, browserMod := range registeredBrowsers {
+		startBrowserModule(ctx, cmd, mngr, browserMod)
+	}
 
-		// A generic modules need to implement on of:
-		// - watch.Poller
-		// - watch.WatchLoader
-		// - and (optionally) modules.MsgListener
-		// OR
-		// - ONLY implement modules.MsgListener
-		var worker manager.WorkUnit
-		listener, isMsgListener := modInstance.(modules.MsgListener)
-		listenerQueue := make(chan modules.ModMsg, 64)
-
-		// Check for Poller or Loader first
-		if poller, ok := modInstance.(watch.Poller); ok {
-			worker = watch.PollWork{
-				Name:   string(name),
-				Poller: poller,
-			}
+	return nil
+}
 
-			// Check if it's also a MsgListener
-			if isMsgListener {
-				listeningWorker := modules.Listener{
-					Ctx:         ctx,
-					Queue:       listenerQueue,
-					MsgListener: listener,
-				}
-				mngr.AddUnit(listeningWorker, string(name)).SetRecoverable()
-			}
-		} else if loader, ok := modInstance.(watch.WatchLoader); ok {
-			worker = watch.WatchLoad{
-				WatchLoader: loader,
-			}
+// startModule sets up a generic module and adds its work units to the manager.
+// Browser modules are skipped.
+func startModule(ctx context.Context, cmd *cli.Command, mngr *manager.Manager, mod modules.Module) {
+	name := mod.ModInfo().ID
+	modInstance := mod.ModInfo().New()
+	if _, ok := modInstance.(modules.BrowserModule); ok {
+		log.Debugf("skipping non browser module")
+		return
+	}
 
-			// Check if it's also a MsgListener
-			if isMsgListener {
-				listeningWorker := modules.Listener{
-					Ctx:         ctx,
-					Queue:       listenerQueue,
-					MsgListener: listener,
-				}
-				mngr.AddUnit(listeningWorker, string(name)).SetRecoverable()
-			}
-		} else if isMsgListener {
-			worker = modules.Listener{
+	log.Debugf("starting <%s>", name)
+
+	modContext := &modules.Context{
+		Context: ctx,
+		Cli:     cmd,
+	}
+
+	// A generic modules need to implement on of:
+	// - watch.Poller
+	// - watch.WatchLoader
+	// - and (optionally) modules.MsgListener
+	// OR
+	// - ONLY implement modules.MsgListener
+	var worker manager.WorkUnit
+	listener, isMsgListener := modInstance.(modules.MsgListener)
+	listenerQueue := make(chan modules.ModMsg, 64)
+
+	// Check for Poller or Loader first
+	if poller, ok := modInstance.(watch.Poller); ok {
+		worker = watch.PollWork{
+			Name:   string(name),
+			Poller: poller,
+		}
+
+		// Check if it's also a MsgListener
+		if isMsgListener {
+			listeningWorker := modules.Listener{
 				Ctx:         ctx,
 				Queue:       listenerQueue,
 				MsgListener: listener,
 			}
+			addModuleUnit(mngr, name, listeningWorker, string(name))
 		}
-
-		// Setup the module
-		if err := modules.SetupModule(mod, modContext); err != nil {
-			log.Warn(err, "mod", name)
-			continue
+	} else if loader, ok := modInstance.(watch.WatchLoader); ok {
+		worker = watch.WatchLoad{
+			WatchLoader: loader,
 		}
 
-		wum := mngr.AddUnit(worker, string(name))
-
-		// Register as a message listener if applicable
+		// Check if it's also a MsgListener
 		if isMsgListener {
-			wum.SetRecoverable()
-			modules.MsgDispatcher.AddListener(name, listenerQueue)
+			listeningWorker := modules.Listener{
+				Ctx:         ctx,
+				Queue:       listenerQueue,
+				MsgListener: listener,
+			}
+			addModuleUnit(mngr, name, listeningWorker, string(name))
+		}
+	} else if isMsgListener {
+		worker = modules.Listener{
+			Ctx:         ctx,
+			Queue:       listenerQueue,
+			MsgListener: listener,
 		}
 	}
 
-	registeredBrowsers := modules.GetBrowserModules()
+	// Setup the module
+	if err := modules.SetupModule(mod, modContext); err != nil {
+		log.Warn(err, "mod", name)
+		return
+	}
 
-	// start all registered browser modules
-	for _, browserMod := range registeredBrowsers {
-		mod := browserMod.ModInfo()
-		log.Infof("starting <%s>", mod.ID)
+	addModuleUnit(mngr, name, worker, string(name))
 
-		//Create a temporary browser instance to check if it implements
-		// the ProfileManager interface
-		browser, ok := mod.New().(modules.BrowserModule)
-		if !ok {
-			log.Fatalf("Module <%s> is not a BrowserModule", mod.ID)
-		}
+	// Register as a message listener if applicable
+	if isMsgListener {
+		modules.MsgDispatcher.AddListener(name, listenerQueue)
+	}
+}
 
-		// call runModule for each profile
-		bpm, ok := browser.(profiles.ProfileManager)
-		if ok {
-			if bpm.WatchAllProfiles() ||
-				config.GlobalConfig.WatchAll {
-				flavours := bpm.ListFlavours()
-				for _, flav := range flavours {
-					profs, err := bpm.GetProfiles(flav.Flavour)
+// startBrowserModule starts a browser module, once for each of its profiles
+// if it can handle profiles.
+func startBrowserModule(ctx context.Context,
+	cmd *cli.Command,
+	mngr *manager.Manager,
+	browserMod modules.BrowserModule,
+) {
+	mod := browserMod.ModInfo()
+	log.Infof("starting <%s>", mod.ID)
+
+	//Create a temporary browser instance to check if it implements
+	// the ProfileManager interface
+	browser, ok := mod.New().(modules.BrowserModule)
+	if !ok {
+		log.Fatalf("Module <%s> is not a BrowserModule", mod.ID)
+	}
+
+	// call runModule for each profile
+	bpm, ok := browser.(profiles.ProfileManager)
+	if ok {
+		if bpm.WatchAllProfiles() ||
+			config.GlobalConfig.WatchAll {
+			flavours := bpm.ListFlavours()
+			for _, flav := range flavours {
+				profs, err := bpm.GetProfiles(flav.Flavour)
+				if err != nil {
+					log.Info("no profiles found", "browser", flav.Flavour)
+					continue
+				}
+				for _, p := range profs {
+					log.Debug("", "flavour", flav.Flavour, "profile", p.Name)
+					err = runBrowserModule(mngr, ctx, cmd, browserMod, p, &flav)
 					if err != nil {
-						log.Info("no profiles found", "browser", flav.Flavour)
-						continue
-					}
-					for _, p := range profs {
-						log.Debug("", "flavour", flav.Flavour, "profile", p.Name)
-						err = runBrowserModule(mngr, ctx, cmd, browserMod, p, &flav)
-						if err != nil {
-							if errDisabled, errDisable := err.(*modules.ErrModDisabled); errDisable {
-								log.Warn(
-									"disabling browser profile",
-									"profile",
-									p.Name,
-									"mod",
-									browserMod.ModInfo().ID,
-									"reason",
-									errDisabled.Reason,
-								)
-								modules.Disable(browserMod.ModInfo().ID)
-							} else {
-								log.Error(err, "browser", flav.Flavour)
-							}
-							continue
+						if errDisabled, errDisable := err.(*modules.ErrModDisabled); errDisable {
+							log.Warn(
+								"disabling browser profile",
+								"profile",
+								p.Name,
+								"mod",
+								browserMod.ModInfo().ID,
+								"reason",
+								errDisabled.Reason,
+							)
+							modules.Disable(browserMod.ModInfo().ID)
+						} else {
+							log.Error(err, "browser", flav.Flavour)
 						}
+						continue
 					}
 				}
-			} else {
-				log.Debugf("profile manager <%s> not watching all profiles",
-					browser.Config().Name)
-				err := runBrowserModule(mngr, ctx, cmd, browserMod, nil, nil)
-				if err != nil {
-					if _, errDisable := err.(*modules.ErrModDisabled); errDisable {
-						log.Warn("disabling browser", "mod", browserMod.ModInfo().ID)
-						modules.Disable(browserMod.ModInfo().ID)
-					} else {
-						log.Error(err, "browser", browserMod.Config().Name)
-					}
-					continue
-				}
 			}
 		} else {
-			log.Info("not implemented profiles.ProfileManager", "browser",
+			log.Debugf("profile manager <%s> not watching all profiles",
 				browser.Config().Name)
-			if err := runBrowserModule(mngr, ctx, cmd, browserMod, nil, nil); err != nil {
+			err := runBrowserModule(mngr, ctx, cmd, browserMod, nil, nil)
+			if err != nil {
 				if _, errDisable := err.(*modules.ErrModDisabled); errDisable {
 					log.Warn("disabling browser", "mod", browserMod.ModInfo().ID)
 					modules.Disable(browserMod.ModInfo().ID)
 				} else {
-					log.Error(err, "browser", browser.Config().Name)
+					log.Error(err, "browser", browserMod.Config().Name)
 				}
-				continue
 			}
 		}
+	} else {
+		log.Info("not implemented profiles.ProfileManager", "browser",
+			browser.Config().Name)
+		if err := runBrowserModule(mngr, ctx, cmd, browserMod, nil, nil); err != nil {
+			if _, errDisable := err.(*modules.ErrModDisabled); errDisable {
+				log.Warn("disabling browser", "mod", browserMod.ModInfo().ID)
+				modules.Disable(browserMod.ModInfo().ID)
+			} else {
+				log.Error(err, "browser", browser.Config().Name)
+			}
+		}
+	}
+}
+
+var (
+	// names of the work units started for each module
+	moduleUnits   = map[modules.ModID][]string{}
+	moduleUnitsMu sync.Mutex
+)
+
+// addModuleUnit adds a work unit to the manager and records it as belonging
+// to the module so it can be stopped later. Module units are recoverable so
+// that a failing module does not take down the daemon.
+func addModuleUnit(m *manager.Manager,
+	id modules.ModID,
+	unit manager.WorkUnit,
+	name string,
+) *manager.WorkUnitManager {
+	moduleUnitsMu.Lock()
+	if !slices.Contains(moduleUnits[id], name) {
+		moduleUnits[id] = append(moduleUnits[id], name)
 	}
+	moduleUnitsMu.Unlock()
 
-	return nil
+	return m.AddRecoverableUnit(unit, name)
+}
+
+// stopModule stops all the work units of a module
+func stopModule(m *manager.Manager, id modules.ModID) {
+	moduleUnitsMu.Lock()
+	names := moduleUnits[id]
+	delete(moduleUnits, id)
+	moduleUnitsMu.Unlock()
+
+	modules.MsgDispatcher.RemoveListener(id)
+	for _, name := range names {
+		m.RemoveUnit(name)
+	}
 }
@@ -34,7 +34,6 @@ import (
 
 	db "github.com/blob42/gosuki/internal/database"
 	"github.com/blob42/gosuki/internal/utils"
-	"github.com/blob42/gosuki/pkg/events"
 	"github.com/blob42/gosuki/pkg/logging"
 	"github.com/blob42/gosuki/pkg/modules"
 )
@@ -65,11 +64,6 @@ func startDaemon(ctx context.Context, cmd *cli.Command) error {
 
 		logging.SetTUI(tui.model.logBuffer)
 		return tui.Run()
-	} else { // drain tui bus
-		go func() {
-			for range events.TUIBus {
-			}
-		}()
 	}
 
 	manager := initManager(false)
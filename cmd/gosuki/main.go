@@ -158,6 +158,11 @@ func main() {
 		cmd.ModuleCmds,
 		cmd.ImportCmds,
 		cmd.ExportCmds,
+		cmd.CtlCmds,
+		cmd.StatusCmd,
+		cmd.MarktabCmds,
+		cmd.DedupeCmd,
+		cmd.NativeHostCmd,
 	}...)
 
 	app.Commands = EntryCommands
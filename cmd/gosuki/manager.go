@@ -28,8 +28,11 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/blob42/gosuki/hooks"
+	"github.com/blob42/gosuki/internal/ctl"
 	"github.com/blob42/gosuki/internal/server"
 	"github.com/blob42/gosuki/internal/webui"
+	"github.com/blob42/gosuki/pkg/config"
 	"github.com/blob42/gosuki/pkg/manager"
 	"github.com/blob42/gosuki/pkg/modules"
 )
@@ -38,10 +41,17 @@ func initManager(tuiMode bool) *manager.Manager {
 	manager := manager.NewManager()
 	manager.ShutdownOn(os.Interrupt)
 
-	uiServ := server.NewWebUIServer(tuiMode)
+	uiServ := server.NewWebUIServer(tuiMode, manager)
 	manager.AddUnit(uiServ, fmt.Sprintf("webui[%s]", webui.BindAddr))
 
-	manager.AddUnit(&modules.MsgDispatcher, modules.DispatcherID).SetRecoverable()
+	manager.AddRecoverableUnit(&modules.MsgDispatcher, modules.DispatcherID)
+	manager.AddRecoverableUnit(hooks.NewWebhookDispatcher(), hooks.WebhooksUnitID)
+
+	ctlServ, err := ctl.NewServer(ctl.SocketPath(config.DBPath), manager)
+	if err != nil {
+		log.Fatal(err)
+	}
+	manager.AddUnit(ctlServ, "ctl")
 
 	return manager
 }
@@ -0,0 +1,167 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki/hooks"
+	"github.com/blob42/gosuki/internal/ctl"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/manager"
+	"github.com/blob42/gosuki/pkg/marktab"
+	"github.com/blob42/gosuki/pkg/modules"
+	"github.com/blob42/gosuki/pkg/watch"
+)
+
+// editors usually write files in several steps, wait for the events to settle
+// before reloading
+const reloadInterval = 500 * time.Millisecond
+
+// reloader watches the config file and the marktab and applies their changes
+// to the running daemon. It implements [watch.WatchRunner].
+type reloader struct {
+	ctx  context.Context
+	cmd  *cli.Command
+	mngr *manager.Manager
+
+	configPath string
+	watcher    *watch.WatchDescriptor
+
+	mu sync.Mutex
+}
+
+func (r *reloader) Watch() *watch.WatchDescriptor {
+	return r.watcher
+}
+
+// Run is called by the watch reducer when a watched file changed
+func (r *reloader) Run() {
+	if err := r.reload(); err != nil {
+		log.Error("reloading config", "err", err)
+	}
+}
+
+// reload parses the marktab and the config file again and applies the
+// changes. On error the previous config is kept and the daemon keeps running.
+func (r *reloader) reload() error {
+	var errs []error
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := marktab.ReloadRules(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := hooks.ReloadScripts(); err != nil {
+		errs = append(errs, err)
+	}
+
+	wasDisabled := slices.Clone(config.GlobalConfig.DisabledModules)
+	if err := config.Reload(r.configPath); err != nil {
+		errs = append(errs, err)
+		return errors.Join(errs...)
+	}
+	log.Info("reloaded config", "path", r.configPath)
+
+	// enable or disable modules
+	for _, mod := range modules.GetAllModules() {
+		id := mod.ModInfo().ID
+		was := slices.Contains(wasDisabled, string(id))
+		now := slices.Contains(config.GlobalConfig.DisabledModules, string(id))
+
+		switch {
+		case !was && now:
+			log.Info("disabling module", "mod", id)
+			modules.Disable(id)
+			stopModule(r.mngr, id)
+		case was && !now:
+			log.Info("enabling module", "mod", id)
+			modules.Enable(id)
+			if browserMod, ok := mod.(modules.BrowserModule); ok {
+				startBrowserModule(r.ctx, r.cmd, r.mngr, browserMod)
+			} else {
+				startModule(r.ctx, r.cmd, r.mngr, mod)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// startReloader watches the config file and marktab for changes. Errors are
+// logged and hot reloading is disabled.
+func startReloader(ctx context.Context, cmd *cli.Command, mngr *manager.Manager) {
+	r := &reloader{
+		ctx:        ctx,
+		cmd:        cmd,
+		mngr:       mngr,
+		configPath: config.ConfigFileFlag,
+	}
+
+	// `gosuki ctl reload` uses the same logic
+	ctl.OnReload(r.reload)
+
+	paths := []string{r.configPath}
+	if mtabPath, err := marktab.Path(); err == nil {
+		paths = append(paths, mtabPath)
+	}
+
+	// Watch the parent directories, editors often replace files when saving
+	var watches []*watch.Watch
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		if _, err := os.Stat(dir); err != nil {
+			log.Debug("not watching", "path", path, "err", err)
+			continue
+		}
+
+		watches = append(watches, &watch.Watch{
+			Path:       dir,
+			EventTypes: []fsnotify.Op{fsnotify.Write, fsnotify.Create},
+			EventNames: []string{path},
+		})
+	}
+
+	w, err := watch.NewWatcherWithReducer("reloader", 16, watches...)
+	if err != nil {
+		log.Error("config hot reload disabled", "err", err)
+		return
+	}
+	r.watcher = w
+
+	go watch.ReduceEvents(reloadInterval, r)
+	mngr.AddUnit(watch.WatchWork{WatchRunner: r}, "reloader")
+}
+
+var _ watch.WatchRunner = (*reloader)(nil)
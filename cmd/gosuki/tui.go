@@ -44,6 +44,7 @@ import (
 	"github.com/blob42/gosuki/internal/utils"
 	"github.com/blob42/gosuki/internal/webui"
 	"github.com/blob42/gosuki/pkg/build"
+	"github.com/blob42/gosuki/pkg/bus"
 	"github.com/blob42/gosuki/pkg/config"
 	"github.com/blob42/gosuki/pkg/events"
 	"github.com/blob42/gosuki/pkg/logging"
@@ -173,6 +174,7 @@ type tuiModel struct {
 	help        help.Model
 	daemon      daemonState
 	syncPeers   map[uuid.UUID]string
+	events      *bus.Subscription[any]
 }
 
 type keymap struct {
@@ -392,7 +394,7 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(
 			cmds,
 			// watch tui event bus
-			func() tea.Msg { return <-events.TUIBus },
+			func() tea.Msg { return <-m.events.C },
 
 			// watch module messages
 			func() tea.Msg { return <-ModMsgQ },
@@ -730,6 +732,14 @@ func NewTUI(
 			},
 			help:   help.New(),
 			daemon: DaemonLoading,
+
+			// subscribe before the modules are started to receive all
+			// loading messages
+			events: events.TUI.Subscribe(bus.Options{
+				Name:   "tui",
+				Buffer: 256,
+				Policy: bus.Coalesce,
+			}),
 		},
 		opts: opts,
 	}
@@ -742,6 +752,7 @@ func NewTUI(
 }
 
 func (tui *tui) Run() error {
+	defer tui.model.events.Close()
 	_, err := tea.NewProgram(tui.model, tui.opts...).Run()
 	if err != nil {
 		return errors.New("could not start TUI")
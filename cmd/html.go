@@ -0,0 +1,59 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki/pkg/browsers/netscape"
+)
+
+const HTMLImporterID = "html-import"
+
+var importHTMLCmd = &cli.Command{
+	Name:  "html",
+	Usage: "Import bookmarks from an HTML export of Opera, Edge or another browser",
+	Description: `Import the bookmarks of a Netscape bookmark file, the HTML format used by the
+bookmark exports of Opera, Edge, Chrome, Firefox and most other browsers.
+
+Nested folders are kept as tags, except the root folders of the browser such
+as the bookmarks bar.`,
+	Action:    runImport(htmlImporter{}, ".html", ".htm"),
+	ArgsUsage: "path/to/bookmarks.html",
+	Arguments: []cli.Argument{importPathArg("Path to the HTML bookmarks file")},
+	Flags:     []cli.Flag{dryRunFlag},
+}
+
+type htmlImporter struct{}
+
+func (htmlImporter) Module() string { return HTMLImporterID }
+
+func (htmlImporter) Parse(r io.Reader, _ string) ([]importItem, error) {
+	root, err := netscape.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	return treeItems(root), nil
+}
@@ -23,16 +23,291 @@
 package cmd
 
 import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/tree"
 )
 
 var ImportCmds = &cli.Command{
 	Name:  "import",
 	Usage: "one-time import bookmarks from other programs",
 	Description: `The import command provides subcommands to migrate bookmarks from various sources.
-Use the specific import subcommands to perform migrations.`,
+Use the specific import subcommands to perform migrations.
+
+Use --dry-run to print a summary of an export file without importing it.`,
 	Commands: []*cli.Command{
 		importBukuDBCmd,
 		importPocketCmd,
+		importInstapaperCmd,
+		importWallabagCmd,
+		importPinboardCmd,
+		importRaindropCmd,
+		importLinkdingCmd,
+		importShioriCmd,
+		importSafariCmd,
+		importHTMLCmd,
+		importBackupCmd,
 	},
 }
+
+var dryRunFlag = &cli.BoolFlag{
+	Name:  "dry-run",
+	Usage: "Print a summary of the bookmarks found in the export without importing them",
+}
+
+// importer reads the export files of a bookmarking service
+type importer interface {
+	// Module returns the module name of the imported bookmarks
+	Module() string
+
+	// Parse reads the items of an export file. ext is the lower case
+	// extension of the file.
+	Parse(r io.Reader, ext string) ([]importItem, error)
+}
+
+// importItem is a bookmark read from an export file
+type importItem struct {
+	bookmark *gosuki.Bookmark
+
+	// the item is in the read later queue of the service, read or not
+	queued bool
+	unread bool
+
+	// zero when unknown
+	added time.Time
+	read  time.Time
+}
+
+// importPathArg is the export file argument of the import commands
+func importPathArg(usage string) cli.Argument {
+	return &cli.StringArg{
+		Name:      "path",
+		UsageText: usage,
+		Config: cli.StringConfig{
+			TrimSpace: true,
+		},
+	}
+}
+
+// runImport returns the action of an import command reading export files
+// with one of the given extensions
+func runImport(imp importer, exts ...string) cli.ActionFunc {
+	return func(ctx context.Context, c *cli.Command) error {
+		path := c.StringArg("path")
+		file, err := openImport(path, exts...)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		items, err := imp.Parse(file, strings.ToLower(filepath.Ext(path)))
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			item.bookmark.Module = imp.Module()
+		}
+
+		if c.Bool(dryRunFlag.Name) {
+			printImportSummary(path, imp.Module(), items)
+			return nil
+		}
+
+		fmt.Printf("importing from %s\n", path)
+		return importItems(ctx, c, items)
+	}
+}
+
+// openImport opens the export file at path, which must have one of the given
+// extensions
+func openImport(path string, exts ...string) (*os.File, error) {
+	if path == "" {
+		return nil, errors.New("missing path to export file")
+	}
+	if !slices.Contains(exts, strings.ToLower(filepath.Ext(path))) {
+		return nil, fmt.Errorf("file does not end in %s", strings.Join(exts, " or "))
+	}
+
+	expandedPath, err := utils.ExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(expandedPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return file, nil
+}
+
+// printImportSummary prints what would be imported from the items
+func printImportSummary(path, module string, items []importItem) {
+	var tagged, described, dated, queued, unread int
+	tags := map[string]bool{}
+	urls := map[string]bool{}
+	for _, item := range items {
+		bk := item.bookmark
+		urls[bk.URL] = true
+		if len(bk.Tags) > 0 {
+			tagged++
+		}
+		for _, tag := range bk.Tags {
+			tags[tag] = true
+		}
+		if bk.Desc != "" {
+			described++
+		}
+		if !item.added.IsZero() {
+			dated++
+		}
+		if item.queued {
+			queued++
+		}
+		if item.unread {
+			unread++
+		}
+	}
+
+	fmt.Printf("dry run: %s was not imported\n", path)
+	fmt.Printf("  module:       %s\n", module)
+	fmt.Printf("  bookmarks:    %d (%d unique urls)\n", len(items), len(urls))
+	fmt.Printf("  tagged:       %d (%d tags)\n", tagged, len(tags))
+	fmt.Printf("  description:  %d\n", described)
+	fmt.Printf("  created time: %d\n", dated)
+	fmt.Printf("  read later:   %d unread, %d read\n", unread, queued-unread)
+}
+
+// importItems saves the items in the on-disk db, see [saveItems]
+func importItems(ctx context.Context, c *cli.Command, items []importItem) error {
+	db.Init(ctx, c)
+	defer db.DiskDB.Close()
+	return saveItems(ctx, items)
+}
+
+// saveItems saves the items in the opened on-disk db with their read state,
+// see [db.ImportBookmarks]. Queued items without a read time are considered
+// read when they were added.
+func saveItems(ctx context.Context, items []importItem) error {
+	imported := make([]db.ImportedBookmark, 0, len(items))
+	var unread int
+	for _, item := range items {
+		bk := item.bookmark
+		if !item.added.IsZero() {
+			bk.Created = uint64(item.added.Unix())
+		}
+		imp := db.ImportedBookmark{Bookmark: bk}
+
+		if item.queued {
+			added := item.added
+			if added.IsZero() {
+				added = time.Now()
+			}
+			read := item.read
+			if read.IsZero() {
+				read = added
+			}
+			imp.Queued, imp.Unread = true, item.unread
+			imp.QueuedAt, imp.ReadAt = added, read
+			if item.unread {
+				unread++
+			}
+		}
+		imported = append(imported, imp)
+	}
+
+	if err := db.ImportBookmarks(ctx, imported); err != nil {
+		return fmt.Errorf("importing bookmarks: %w", err)
+	}
+	fmt.Printf("imported %d bookmarks, %d unread\n", len(imported), unread)
+
+	return nil
+}
+
+// treeItems returns the bookmarks of a bookmark tree. The folders of the
+// bookmarks are added to their tags.
+func treeItems(root *tree.Node) []importItem {
+	var items []importItem
+	tree.MapNodeFunc(root, tree.URLNode, func(node *tree.Node) {
+		bk := node.GetBookmark()
+		items = append(items, importItem{
+			bookmark: bk,
+			added:    unixTime(strconv.FormatUint(bk.Created, 10)),
+		})
+	})
+	return items
+}
+
+// readCSV reads a csv export with a header. It returns the records and the
+// index of the lower case column names.
+func readCSV(r io.Reader) ([][]string, map[string]int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, errors.New("empty CSV file")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	return records[1:], columns, nil
+}
+
+// csvField returns the value of the named column of row
+func csvField(row []string, columns map[string]int, name string) string {
+	i, ok := columns[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// unixTime parses a unix timestamp, it returns the zero time on error
+func unixTime(s string) time.Time {
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// parseTime parses s with the first matching layout, it returns the zero time
+// on error
+func parseTime(s string, layouts ...string) time.Time {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// splitTags splits a list of tags on sep and drops the empty ones
+func splitTags(s, sep string) []string {
+	var tags []string
+	for tag := range strings.SplitSeq(s, sep) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
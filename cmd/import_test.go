@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// importTestItem is the expected result of parsing an export
+type importTestItem struct {
+	url    string
+	title  string
+	desc   string
+	tags   []string
+	queued bool
+	unread bool
+	added  time.Time
+	read   time.Time
+}
+
+func utc(s string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestImportersParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		importer importer
+		file     string
+		want     []importTestItem
+	}{
+		{
+			name:     "pinboard",
+			importer: pinboardImporter{},
+			file:     "pinboard.json",
+			want: []importTestItem{
+				{
+					url:   "https://go.dev/",
+					title: "The Go Programming Language",
+					desc:  "Go docs and downloads",
+					tags:  []string{"go", "dev"},
+					added: utc("2024-05-06T12:00:00Z"),
+				},
+				{
+					url:    "https://lwn.net/",
+					title:  "LWN.net",
+					queued: true,
+					unread: true,
+					added:  utc("2024-05-07T08:30:00Z"),
+				},
+			},
+		},
+		{
+			name:     "raindrop csv",
+			importer: raindropImporter{},
+			file:     "raindrop.csv",
+			want: []importTestItem{
+				{
+					url:   "https://go.dev/",
+					title: "The Go Programming Language",
+					desc:  "Go docs",
+					tags:  []string{"go", "lang", "dev", "starred"},
+					added: utc("2024-05-06T12:00:00Z"),
+				},
+				{
+					url:   "https://lwn.net/",
+					title: "LWN.net",
+					desc:  "Linux news",
+					added: utc("2024-05-07T08:30:00Z"),
+				},
+			},
+		},
+		{
+			name:     "raindrop html",
+			importer: raindropImporter{},
+			file:     "raindrop.html",
+			want: []importTestItem{
+				{
+					url:   "https://go.dev/",
+					title: "The Go Programming Language",
+					desc:  "Go docs",
+					tags:  []string{"go", "lang", "dev"},
+					added: time.Unix(1715000100, 0),
+				},
+				{
+					url:   "https://sqlite.org/",
+					title: "SQLite",
+					tags:  []string{"dev"},
+					added: time.Unix(1715000200, 0),
+				},
+			},
+		},
+		{
+			name:     "linkding",
+			importer: linkdingImporter{},
+			file:     "linkding.json",
+			want: []importTestItem{
+				{
+					url:   "https://go.dev/",
+					title: "The Go Programming Language",
+					desc:  "Build simple software\n\nread the spec",
+					tags:  []string{"go", "dev"},
+					added: utc("2024-05-06T12:00:00.123456Z"),
+				},
+				{
+					url:    "https://lwn.net/",
+					title:  "LWN",
+					desc:   "Linux news",
+					queued: true,
+					unread: true,
+					added:  utc("2024-05-07T08:30:00Z"),
+				},
+			},
+		},
+		{
+			name:     "shiori",
+			importer: shioriImporter{},
+			file:     "shiori.json",
+			want: []importTestItem{
+				{
+					url:   "https://go.dev/",
+					title: "The Go Programming Language",
+					desc:  "Build simple software",
+					tags:  []string{"go", "dev"},
+					added: utc("2024-05-06T12:00:00Z"),
+				},
+				{
+					url:   "https://lwn.net/",
+					title: "LWN.net",
+					added: utc("2024-05-07T08:30:00Z"),
+				},
+			},
+		},
+		{
+			name:     "wallabag",
+			importer: wallabagImporter{},
+			file:     "wallabag.json",
+			want: []importTestItem{
+				{
+					url:    "https://go.dev/",
+					title:  "The Go Programming Language",
+					tags:   []string{"go"},
+					queued: true,
+					added:  utc("2024-05-06T12:00:00Z"),
+					read:   utc("2024-05-08T09:00:00Z"),
+				},
+				{
+					url:    "https://lwn.net/",
+					title:  "LWN.net",
+					tags:   []string{"starred"},
+					queued: true,
+					unread: true,
+					added:  utc("2024-05-07T08:30:00Z"),
+				},
+			},
+		},
+		{
+			name:     "instapaper",
+			importer: instapaperImporter{},
+			file:     "instapaper.csv",
+			want: []importTestItem{
+				{
+					url:    "https://go.dev/",
+					title:  "The Go Programming Language",
+					desc:   "Build simple software",
+					tags:   []string{"go", "dev"},
+					queued: true,
+					added:  time.Unix(1715000100, 0),
+				},
+				{
+					url:    "https://lwn.net/",
+					title:  "LWN.net",
+					queued: true,
+					unread: true,
+					added:  time.Unix(1715070600, 0),
+				},
+				{
+					url:    "https://sqlite.org/",
+					title:  "SQLite",
+					tags:   []string{"starred"},
+					queued: true,
+					unread: true,
+					added:  time.Unix(1715000200, 0),
+				},
+				{
+					url:    "https://kernel.org/",
+					title:  "The Linux Kernel",
+					tags:   []string{"linux", "kernel", "Reading"},
+					queued: true,
+					unread: true,
+					added:  time.Unix(1715000300, 0),
+				},
+			},
+		},
+		{
+			name:     "pocket",
+			importer: pocketImporter{},
+			file:     "pocket.csv",
+			want: []importTestItem{
+				{
+					url:    "https://go.dev/",
+					title:  "The Go Programming Language",
+					tags:   []string{"go", "dev"},
+					queued: true,
+					added:  time.Unix(1715000100, 0),
+				},
+				{
+					url:    "https://lwn.net/",
+					title:  "LWN.net",
+					queued: true,
+					unread: true,
+					added:  time.Unix(1715070600, 0),
+				},
+				{
+					url:    "https://sqlite.org/",
+					title:  "SQLite",
+					tags:   []string{"db"},
+					queued: true,
+					unread: true,
+					added:  time.Unix(1715000200, 0),
+				},
+			},
+		},
+		{
+			name:     "pocket without header",
+			importer: pocketImporter{},
+			file:     "pocket-legacy.csv",
+			want: []importTestItem{
+				{
+					url:    "https://go.dev/",
+					title:  "The Go Programming Language",
+					desc:   "Build simple software",
+					tags:   []string{"go", "dev"},
+					queued: true,
+					unread: true,
+					added:  time.Unix(1715000100, 0),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("testdata", tt.file))
+			require.NoError(t, err)
+			defer f.Close()
+
+			items, err := tt.importer.Parse(f, filepath.Ext(tt.file))
+			require.NoError(t, err)
+			require.Len(t, items, len(tt.want), "items without url are skipped")
+
+			for i, want := range tt.want {
+				got := items[i]
+				assert.Equal(t, want.url, got.bookmark.URL)
+				assert.Equal(t, want.title, got.bookmark.Title, want.url)
+				assert.Equal(t, want.desc, got.bookmark.Desc, want.url)
+				assert.ElementsMatch(t, want.tags, got.bookmark.Tags, want.url)
+				assert.Equal(t, want.queued, got.queued, "queued %s", want.url)
+				assert.Equal(t, want.unread, got.unread, "unread %s", want.url)
+				assert.True(t, want.added.Equal(got.added), "added %s: %v", want.url, got.added)
+				assert.True(t, want.read.Equal(got.read), "read %s: %v", want.url, got.read)
+			}
+		})
+	}
+}
+
+func TestImportParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		importer importer
+		file     string
+	}{
+		{"pinboard not json", pinboardImporter{}, "pocket.csv"},
+		{"raindrop without url column", raindropImporter{}, "pocket-legacy.csv"},
+		{"instapaper without url column", instapaperImporter{}, "pocket-legacy.csv"},
+		{"linkding not json", linkdingImporter{}, "raindrop.csv"},
+		{"shiori not json", shioriImporter{}, "raindrop.csv"},
+		{"wallabag not json", wallabagImporter{}, "raindrop.csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("testdata", tt.file))
+			require.NoError(t, err)
+			defer f.Close()
+
+			_, err = tt.importer.Parse(f, filepath.Ext(tt.file))
+			assert.Error(t, err)
+		})
+	}
+}
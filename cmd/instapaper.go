@@ -0,0 +1,111 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
+)
+
+const (
+	InstapaperImporterID = "instapaper-import"
+)
+
+var importInstapaperCmd = &cli.Command{
+	Name:  "instapaper",
+	Usage: "Import bookmarks from an Instapaper export in CSV format",
+	Description: `Import bookmarks from the CSV file exported from the Instapaper settings.
+
+Bookmarks in the Unread folder or in a custom folder are added to the read
+later queue, the ones in the Archive folder are marked as read. Custom folders
+and starred bookmarks are kept as tags.`,
+	Action:    runImport(instapaperImporter{}, ".csv"),
+	ArgsUsage: "path/to/instapaper-export.csv",
+	Arguments: []cli.Argument{importPathArg("Path to the Instapaper CSV export file")},
+	Flags:     []cli.Flag{dryRunFlag},
+}
+
+type instapaperImporter struct{}
+
+func (instapaperImporter) Module() string { return InstapaperImporterID }
+
+func (instapaperImporter) Parse(r io.Reader, _ string) ([]importItem, error) {
+	return parseInstapaperCSV(r)
+}
+
+// parseInstapaperCSV parses the `URL,Title,Selection,Folder,Timestamp,Tags`
+// export of Instapaper. Tags are a JSON list.
+func parseInstapaperCSV(r io.Reader) ([]importItem, error) {
+	records, columns, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := columns["url"]; !ok {
+		return nil, fmt.Errorf("missing URL column, not an Instapaper export")
+	}
+
+	var items []importItem
+	for _, row := range records {
+		url := csvField(row, columns, "url")
+		if url == "" {
+			continue
+		}
+
+		var tags []string
+		if field := csvField(row, columns, "tags"); field != "" {
+			if err := json.Unmarshal([]byte(field), &tags); err != nil {
+				tags = splitTags(field, ",")
+			}
+		}
+
+		unread := true
+		switch folder := csvField(row, columns, "folder"); strings.ToLower(folder) {
+		case "unread", "":
+		case "archive":
+			unread = false
+		case "starred":
+			tags = append(tags, "starred")
+		default:
+			tags = append(tags, folder)
+		}
+
+		items = append(items, importItem{
+			bookmark: &gosuki.Bookmark{
+				URL:   url,
+				Title: csvField(row, columns, "title"),
+				Tags:  tags,
+				Desc:  csvField(row, columns, "selection"),
+			},
+			queued: true,
+			unread: unread,
+			added:  unixTime(csvField(row, columns, "timestamp")),
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,128 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
+)
+
+const (
+	LinkdingImporterID = "linkding-import"
+)
+
+var importLinkdingCmd = &cli.Command{
+	Name:  "linkding",
+	Usage: "Import bookmarks from linkding in JSON format",
+	Description: `Import the bookmarks returned by the /api/bookmarks/ endpoint of linkding,
+either a single page of results or a list of bookmarks:
+
+    curl -H "Authorization: Token $TOKEN" "$LINKDING/api/bookmarks/?limit=10000" > linkding.json
+
+Tags, descriptions, notes and creation times are kept. Unread bookmarks are
+added to the read later queue.`,
+	Action:    runImport(linkdingImporter{}, ".json"),
+	ArgsUsage: "path/to/linkding.json",
+	Arguments: []cli.Argument{importPathArg("Path to the linkding JSON file")},
+	Flags:     []cli.Flag{dryRunFlag},
+}
+
+type linkdingImporter struct{}
+
+func (linkdingImporter) Module() string { return LinkdingImporterID }
+
+func (linkdingImporter) Parse(r io.Reader, _ string) ([]importItem, error) {
+	return parseLinkdingJSON(r)
+}
+
+type linkdingBookmark struct {
+	URL                string   `json:"url"`
+	Title              string   `json:"title"`
+	Description        string   `json:"description"`
+	Notes              string   `json:"notes"`
+	WebsiteTitle       string   `json:"website_title"`
+	WebsiteDescription string   `json:"website_description"`
+	Unread             bool     `json:"unread"`
+	TagNames           []string `json:"tag_names"`
+	DateAdded          string   `json:"date_added"`
+}
+
+// parseLinkdingJSON parses a page of the linkding API, with the bookmarks in
+// its results field, or a list of bookmarks. The title and description
+// fetched by linkding are used when the bookmark has none.
+func parseLinkdingJSON(r io.Reader) ([]importItem, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var bookmarks []linkdingBookmark
+	if err = json.Unmarshal(data, &bookmarks); err != nil {
+		var page struct {
+			Results []linkdingBookmark `json:"results"`
+		}
+		if json.Unmarshal(data, &page) != nil {
+			return nil, fmt.Errorf("failed to read JSON: %w", err)
+		}
+		bookmarks = page.Results
+	}
+
+	var items []importItem
+	for _, bk := range bookmarks {
+		if bk.URL == "" {
+			continue
+		}
+
+		title := bk.Title
+		if title == "" {
+			title = bk.WebsiteTitle
+		}
+		desc := bk.Description
+		if desc == "" {
+			desc = bk.WebsiteDescription
+		}
+		if bk.Notes != "" {
+			desc = strings.TrimSpace(desc + "\n\n" + bk.Notes)
+		}
+
+		items = append(items, importItem{
+			bookmark: &gosuki.Bookmark{
+				URL:   bk.URL,
+				Title: title,
+				Tags:  bk.TagNames,
+				Desc:  desc,
+			},
+			queued: bk.Unread,
+			unread: bk.Unread,
+			added:  parseTime(bk.DateAdded, time.RFC3339),
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,154 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v3"
+
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+var MarktabCmds = &cli.Command{
+	Name:  "marktab",
+	Usage: "inspect the actions run by marktab rules",
+	Commands: []*cli.Command{
+		marktabRunsCmd,
+	},
+}
+
+var marktabRunsCmd = &cli.Command{
+	Name:      "runs",
+	Usage:     "list the marktab action runs or show the details of a run",
+	ArgsUsage: "[id]",
+	Description: `Each attempt at running the action of a marktab rule is recorded with its
+status, exit code and output. A rule runs successfully only once for a given
+bookmark, edit the rule to run it again on the bookmarks it already handled.
+Failed actions are run again after a delay of one hour, doubled on each failure.
+The history keeps the last [marktab] history runs.`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "status",
+			Usage: "only show runs with `STATUS` (success, failed, timeout)",
+		},
+		&cli.StringFlag{
+			Name:  "url",
+			Usage: "only show runs for urls containing `TEXT`",
+		},
+		&cli.IntFlag{
+			Name:    "limit",
+			Aliases: []string{"n"},
+			Value:   20,
+			Usage:   "maximum number of runs to show, 0 shows all",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		filter := db.RunFilter{
+			Status: db.RunStatus(cmd.String("status")),
+			URL:    cmd.String("url"),
+			Limit:  int(cmd.Int("limit")),
+		}
+
+		if cmd.Args().Present() {
+			id, err := strconv.ParseUint(cmd.Args().First(), 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid run id: %s", cmd.Args().First())
+			}
+			filter = db.RunFilter{ID: id}
+		}
+
+		runs, err := marktabRuns(ctx, cmd, filter)
+		if err != nil {
+			return err
+		}
+
+		if filter.ID != 0 {
+			if len(runs) == 0 {
+				return errors.New("run not found")
+			}
+			printRun(runs[0])
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSTARTED\tSTATUS\tEXIT\tTRY\tRULE\tURL")
+		for _, run := range runs {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\t%s\t%s\n",
+				run.ID,
+				time.Unix(run.Started, 0).Format(time.DateTime),
+				colorStatus(run.Status),
+				run.ExitCode,
+				run.Attempt,
+				fmt.Sprintf("line %d", run.Line),
+				run.URL,
+			)
+		}
+		return w.Flush()
+	},
+}
+
+// marktabRuns returns the runs from the running daemon or from the database
+// if the daemon is not running.
+func marktabRuns(ctx context.Context, cmd *cli.Command, filter db.RunFilter) ([]db.MarktabRun, error) {
+	if client, err := dialDaemon(); err == nil {
+		defer client.Close()
+		return client.MarktabRuns(filter)
+	}
+
+	db.Init(ctx, cmd)
+	return db.DiskDB.MarktabRuns(ctx, filter)
+}
+
+func colorStatus(status db.RunStatus) string {
+	switch status {
+	case db.RunSuccess:
+		return color.GreenString(string(status))
+	case db.RunTimeout:
+		return color.YellowString(string(status))
+	}
+	return color.RedString(string(status))
+}
+
+func printRun(run db.MarktabRun) {
+	fmt.Printf("id:       %d\n", run.ID)
+	fmt.Printf("rule:     %s (line %d)\n", run.Rule, run.Line)
+	fmt.Printf("url:      %s\n", run.URL)
+	fmt.Printf("action:   %s\n", run.Action)
+	fmt.Printf("status:   %s\n", colorStatus(run.Status))
+	fmt.Printf("attempt:  %d\n", run.Attempt)
+	fmt.Printf("exit:     %d\n", run.ExitCode)
+	fmt.Printf("started:  %s\n", time.Unix(run.Started, 0).Format(time.DateTime))
+	fmt.Printf("duration: %s\n", time.Duration(run.Duration)*time.Millisecond)
+	if output := strings.TrimSpace(run.Output); output != "" {
+		fmt.Printf("\n%s\n", output)
+	}
+}
@@ -0,0 +1,287 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/ctl"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/internal/nativehost"
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/browsers"
+	"github.com/blob42/gosuki/pkg/config"
+)
+
+// name of the script started by the browsers, written next to the config
+// file. Browsers pass their own arguments to the host which are not gosuki
+// arguments.
+const nativeHostScript = "native-host"
+
+var NativeHostCmd = &cli.Command{
+	Name:  "native-host",
+	Usage: "native messaging host for the gosuki browser extension",
+	Description: `Without a subcommand, answer the requests of a browser extension on stdin
+and stdout using the WebExtension native messaging protocol. This is started
+by the browser, run 'gosuki native-host install' to register the host with the
+installed browsers.
+
+The bookmarks are read and saved through the daemon when it is running.`,
+	// the browser passes the manifest path and extension id or origin
+	SkipFlagParsing: true,
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		log.Debug("starting native host", "args", cmd.Args().Slice())
+		host := &nativehost.Host{Store: &nativeStore{cmd: cmd}}
+		return host.Serve(ctx, os.Stdin, os.Stdout)
+	},
+	Commands: []*cli.Command{
+		nativeHostInstallCmd,
+		nativeHostManifestCmd,
+	},
+}
+
+var nativeHostFlags = []cli.Flag{
+	&cli.StringSliceFlag{
+		Name:    "browser",
+		Aliases: []string{"b"},
+		Usage:   "browser `flavour`, all the detected browsers by default",
+	},
+	&cli.StringSliceFlag{
+		Name:  "extension-id",
+		Usage: "`id` of the extension allowed to use the host, required for Chromium browsers",
+	},
+}
+
+var nativeHostInstallCmd = &cli.Command{
+	Name:  "install",
+	Usage: "register the native host with the browsers",
+	Flags: nativeHostFlags,
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		script, err := writeNativeHostScript()
+		if err != nil {
+			return err
+		}
+		fmt.Println("host script:", utils.Shorten(script))
+
+		defs, err := nativeHostBrowsers(cmd.StringSlice("browser"))
+		if err != nil {
+			return err
+		}
+
+		var errs []error
+		for _, bd := range defs {
+			manifest, err := bd.NativeHostManifest(script, cmd.StringSlice("extension-id")...)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			path, err := bd.InstallNativeHost(manifest)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("<%s>: %w", bd.Flavour, err))
+				continue
+			}
+			fmt.Printf("%s: %s\n", bd.Flavour, utils.Shorten(path))
+		}
+		return errors.Join(errs...)
+	},
+}
+
+var nativeHostManifestCmd = &cli.Command{
+	Name:  "manifest",
+	Usage: "print the native host manifest of a browser",
+	Flags: nativeHostFlags,
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		flavours := cmd.StringSlice("browser")
+		if len(flavours) != 1 {
+			return errors.New("select a browser with --browser")
+		}
+		defs, err := nativeHostBrowsers(flavours)
+		if err != nil {
+			return err
+		}
+
+		script, err := nativeHostScriptPath()
+		if err != nil {
+			return err
+		}
+		manifest, err := defs[0].NativeHostManifest(script, cmd.StringSlice("extension-id")...)
+		if err != nil {
+			return err
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(manifest)
+	},
+}
+
+// nativeHostBrowsers returns the browsers supporting native messaging with
+// the given flavours or the detected ones
+func nativeHostBrowsers(flavours []string) ([]browsers.BrowserDef, error) {
+	var result []browsers.BrowserDef
+	for _, bd := range browsers.DefinedBrowsers {
+		if bd.Family != browsers.Mozilla && bd.Family != browsers.ChromeBased {
+			continue
+		}
+		if len(flavours) == 0 && bd.Detect() || slices.Contains(flavours, bd.Flavour) {
+			result = append(result, bd)
+		}
+	}
+
+	for _, flavour := range flavours {
+		if !slices.ContainsFunc(result, func(bd browsers.BrowserDef) bool {
+			return bd.Flavour == flavour
+		}) {
+			return nil, fmt.Errorf("unknown browser: %s", flavour)
+		}
+	}
+	if len(result) == 0 {
+		return nil, errors.New("no browser detected")
+	}
+	return result, nil
+}
+
+func nativeHostScriptPath() (string, error) {
+	cfgPath, err := filepath.Abs(config.ConfigFileFlag)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(cfgPath), nativeHostScript), nil
+}
+
+// writeNativeHostScript writes the script starting the native host with the
+// current gosuki executable and config
+func writeNativeHostScript() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	cfgPath, err := filepath.Abs(config.ConfigFileFlag)
+	if err != nil {
+		return "", err
+	}
+	path, err := nativeHostScriptPath()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	script := fmt.Sprintf("#!/bin/sh\nexec %s --config %s native-host\n",
+		shellQuote(exe), shellQuote(cfgPath))
+	return path, os.WriteFile(path, []byte(script), 0o755)
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// nativeStore uses the daemon when it is running, else the database file.
+// The daemon can be started or stopped while the browser keeps the host
+// running.
+type nativeStore struct {
+	cmd      *cli.Command
+	diskOpen bool
+}
+
+func (s *nativeStore) daemon() *ctl.Client {
+	client, err := dialDaemon()
+	if err != nil {
+		return nil
+	}
+	return client
+}
+
+func (s *nativeStore) disk(ctx context.Context) {
+	if !s.diskOpen {
+		db.Init(ctx, s.cmd)
+		s.diskOpen = true
+	}
+}
+
+func (s *nativeStore) Lookup(ctx context.Context, url string) (*gosuki.Bookmark, error) {
+	if client := s.daemon(); client != nil {
+		defer client.Close()
+		return client.Lookup(url)
+	}
+
+	s.disk(ctx)
+	bk, err := db.GetBookmarkByURL(ctx, url)
+	if errors.Is(err, db.ErrBookmarkNotFound) {
+		return nil, nil
+	}
+	return bk, err
+}
+
+func (s *nativeStore) Set(ctx context.Context, bk *gosuki.Bookmark) error {
+	if client := s.daemon(); client != nil {
+		defer client.Close()
+		return client.Set(bk)
+	}
+
+	s.disk(ctx)
+	return db.SetBookmark(ctx, bk)
+}
+
+func (s *nativeStore) Search(ctx context.Context, query string, fuzzy bool) ([]*gosuki.Bookmark, error) {
+	if client := s.daemon(); client != nil {
+		defer client.Close()
+		return client.Query(ctl.QueryArgs{Query: query, Fuzzy: fuzzy})
+	}
+
+	s.disk(ctx)
+	pageParms := &db.PaginationParams{Page: 1, Size: -1}
+	var res *db.QueryResult
+	var err error
+	if query == "" {
+		res, err = db.ListBookmarks(ctx, pageParms)
+	} else {
+		res, err = db.QueryBookmarks(ctx, query, fuzzy, pageParms)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res.Bookmarks, nil
+}
+
+func (s *nativeStore) SuggestTags(ctx context.Context, prefix, url string, limit int) ([]db.TagCount, error) {
+	if client := s.daemon(); client != nil {
+		defer client.Close()
+		return client.SuggestTags(ctl.SuggestArgs{Prefix: prefix, URL: url, Limit: limit})
+	}
+
+	s.disk(ctx)
+	return db.SuggestTags(ctx, prefix, url, limit)
+}
@@ -0,0 +1,103 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
+)
+
+const (
+	PinboardImporterID = "pinboard-import"
+)
+
+var importPinboardCmd = &cli.Command{
+	Name:  "pinboard",
+	Usage: "Import bookmarks from a Pinboard export in JSON format",
+	Description: `Import the bookmarks of a Pinboard JSON export, as downloaded from the
+"backup" settings page or from the posts/all API.
+
+Tags, extended descriptions and creation times are kept. Bookmarks marked as
+"to read" are added to the read later queue.`,
+	Action:    runImport(pinboardImporter{}, ".json"),
+	ArgsUsage: "path/to/pinboard-export.json",
+	Arguments: []cli.Argument{importPathArg("Path to the Pinboard JSON export file")},
+	Flags:     []cli.Flag{dryRunFlag},
+}
+
+type pinboardImporter struct{}
+
+func (pinboardImporter) Module() string { return PinboardImporterID }
+
+func (pinboardImporter) Parse(r io.Reader, _ string) ([]importItem, error) {
+	return parsePinboardJSON(r)
+}
+
+// pinboardPost is a bookmark of a Pinboard export. The title is called
+// description and the description extended.
+type pinboardPost struct {
+	Href        string `json:"href"`
+	Description string `json:"description"`
+	Extended    string `json:"extended"`
+	Time        string `json:"time"`
+	Shared      string `json:"shared"`
+	ToRead      string `json:"toread"`
+	Tags        string `json:"tags"`
+}
+
+// parsePinboardJSON parses the list of posts of a Pinboard export. Tags are
+// separated by spaces and the to read state is either `yes` or `no`.
+func parsePinboardJSON(r io.Reader) ([]importItem, error) {
+	var posts []pinboardPost
+	if err := json.NewDecoder(r).Decode(&posts); err != nil {
+		return nil, fmt.Errorf("failed to read JSON: %w", err)
+	}
+
+	var items []importItem
+	for _, post := range posts {
+		if post.Href == "" {
+			continue
+		}
+
+		toRead := strings.EqualFold(post.ToRead, "yes")
+		items = append(items, importItem{
+			bookmark: &gosuki.Bookmark{
+				URL:   post.Href,
+				Title: post.Description,
+				Tags:  strings.Fields(post.Tags),
+				Desc:  post.Extended,
+			},
+			queued: toRead,
+			unread: toRead,
+			added:  parseTime(post.Time, time.RFC3339),
+		})
+	}
+	return items, nil
+}
@@ -23,18 +23,12 @@
 package cmd
 
 import (
-	"context"
-	"encoding/csv"
-	"errors"
-	"fmt"
-	"os"
+	"io"
 	"strings"
 
 	"github.com/urfave/cli/v3"
 
 	"github.com/blob42/gosuki"
-	db "github.com/blob42/gosuki/internal/database"
-	"github.com/blob42/gosuki/internal/utils"
 )
 
 const (
@@ -51,88 +45,60 @@ preserving their titles, URLs, and tags. The CSV file should be formatted
 according to Pocket's standard export format.
 
 The import will create a new bookmark entry for each URL in the CSV file,
-maintaining the original metadata including tags and read/unread status.`,
-	Action:    importFromPocketCSV,
+maintaining the original metadata including tags and read/unread status.
+Unread items are added to the read later queue, see 'suki --unread'.`,
+	Action:    runImport(pocketImporter{}, ".csv"),
 	ArgsUsage: "path/to/pocket-export.csv",
-	Arguments: []cli.Argument{
-		&cli.StringArg{
-			Name:      "path",
-			UsageText: "Path to the Pocket CSV export file",
-			Config: cli.StringConfig{
-				TrimSpace: true,
-			},
-		},
-	},
+	Arguments: []cli.Argument{importPathArg("Path to the Pocket CSV export file")},
+	Flags:     []cli.Flag{dryRunFlag},
 }
 
-func importFromPocketCSV(ctx context.Context, c *cli.Command) error {
-	path := c.StringArg("path")
-	if c.StringArg("path") == "" {
-		return errors.New("missing path to csv file")
-	}
-	expandedPath, err := utils.ExpandPath(path)
-	if err != nil {
-		return err
-	}
+type pocketImporter struct{}
 
-	if _, err = os.Stat(expandedPath); os.IsNotExist(err) {
-		return err
-	}
-
-	if !strings.HasSuffix(path, ".csv") {
-		return errors.New("file does not end in .csv")
-	}
-	file, err := os.Open(path)
-	if err != nil {
-		return fmt.Errorf("failed to open file: %w", err)
-	}
-	defer file.Close()
+func (pocketImporter) Module() string { return PocketImporterID }
 
-	fmt.Printf("importing from %s\n", path)
+func (pocketImporter) Parse(r io.Reader, _ string) ([]importItem, error) {
+	return parsePocketCSV(r)
+}
 
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+// parsePocketCSV parses the `title,url,time_added,tags,status` export of
+// Pocket. Tags are separated by `|` and the status is either `unread` or
+// `archive`.
+func parsePocketCSV(r io.Reader) ([]importItem, error) {
+	records, columns, err := readCSV(r)
 	if err != nil {
-		return fmt.Errorf("failed to read CSV: %w", err)
+		return nil, err
 	}
 
-	db.Init(ctx, c)
-	DB := db.DiskDB
-	defer db.DiskDB.Close()
-
-	var bkCount int
-	for i, row := range records {
-		// skip header
-		if i == 0 {
-			continue
-		}
+	// older exports without a known header
+	if _, ok := columns["url"]; !ok {
+		columns = map[string]int{"url": 1, "title": 2, "time_added": 3, "tags": 4, "desc": 5}
+	}
 
-		if len(row) < 6 {
+	var items []importItem
+	for _, row := range records {
+		url := csvField(row, columns, "url")
+		if url == "" {
 			continue
 		}
 
-		url := row[1]
-		title := row[2]
-		// timeAdded := row[3]
-		tags := row[4]
-		desc := row[5]
-
-		bookmark := &gosuki.Bookmark{
-			URL:    url,
-			Title:  title,
-			Tags:   strings.Split(tags, ","),
-			Desc:   desc,
-			Module: PocketImporterID,
+		tags := csvField(row, columns, "tags")
+		sep := ","
+		if strings.Contains(tags, "|") {
+			sep = "|"
 		}
 
-		if err = DB.UpsertBookmark(bookmark); err != nil {
-			fmt.Fprintf(os.Stderr, "inserting bookmark %s: %s", bookmark.URL, err)
-			continue
-		} else {
-			bkCount++
-		}
+		items = append(items, importItem{
+			bookmark: &gosuki.Bookmark{
+				URL:   url,
+				Title: csvField(row, columns, "title"),
+				Tags:  splitTags(tags, sep),
+				Desc:  csvField(row, columns, "desc"),
+			},
+			queued: true,
+			unread: csvField(row, columns, "status") != "archive",
+			added:  unixTime(csvField(row, columns, "time_added")),
+		})
 	}
-	fmt.Printf("imported %d bookmarks\n", bkCount)
-
-	return nil
+	return items, nil
 }
@@ -0,0 +1,157 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
+)
+
+const (
+	RaindropImporterID = "raindrop-import"
+
+	// collection of the raindrops without a collection
+	raindropUnsorted = "Unsorted"
+)
+
+var importRaindropCmd = &cli.Command{
+	Name:  "raindrop",
+	Usage: "Import bookmarks from a Raindrop.io export in CSV or HTML format",
+	Description: `Import the bookmarks of a Raindrop.io CSV or HTML export, as downloaded from
+the "Export" menu of a collection or of all bookmarks.
+
+Tags, notes and creation times are kept. Collections are kept as tags and
+favorite bookmarks are tagged with "starred".`,
+	Action:    runImport(raindropImporter{}, ".csv", ".html"),
+	ArgsUsage: "path/to/raindrop-export.csv",
+	Arguments: []cli.Argument{importPathArg("Path to the Raindrop.io CSV or HTML export file")},
+	Flags:     []cli.Flag{dryRunFlag},
+}
+
+type raindropImporter struct{}
+
+func (raindropImporter) Module() string { return RaindropImporterID }
+
+func (raindropImporter) Parse(r io.Reader, ext string) ([]importItem, error) {
+	if ext == ".html" {
+		return parseRaindropHTML(r)
+	}
+	return parseRaindropCSV(r)
+}
+
+// raindropTags returns the tags of a raindrop with its collection
+func raindropTags(tags []string, collection string, favorite bool) []string {
+	if collection != "" && collection != raindropUnsorted {
+		tags = append(tags, collection)
+	}
+	if favorite {
+		tags = append(tags, "starred")
+	}
+	return tags
+}
+
+// parseRaindropCSV parses the
+// `id,title,note,excerpt,url,folder,tags,created,cover,highlights,favorite`
+// export of Raindrop.io. Tags are separated by commas. The excerpt is used as
+// description when there is no note.
+func parseRaindropCSV(r io.Reader) ([]importItem, error) {
+	records, columns, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := columns["url"]; !ok {
+		return nil, fmt.Errorf("missing url column, not a Raindrop.io export")
+	}
+
+	var items []importItem
+	for _, row := range records {
+		url := csvField(row, columns, "url")
+		if url == "" {
+			continue
+		}
+
+		desc := csvField(row, columns, "note")
+		if desc == "" {
+			desc = csvField(row, columns, "excerpt")
+		}
+
+		items = append(items, importItem{
+			bookmark: &gosuki.Bookmark{
+				URL:   url,
+				Title: csvField(row, columns, "title"),
+				Tags: raindropTags(
+					splitTags(csvField(row, columns, "tags"), ","),
+					csvField(row, columns, "folder"),
+					csvField(row, columns, "favorite") == "true",
+				),
+				Desc: desc,
+			},
+			added: parseTime(csvField(row, columns, "created"), time.RFC3339),
+		})
+	}
+	return items, nil
+}
+
+// parseRaindropHTML parses the Netscape bookmark file exported by
+// Raindrop.io. Tags are in the TAGS attribute of the links and notes in the
+// following DD element. Collections are H3 headings.
+func parseRaindropHTML(r io.Reader) ([]importItem, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	var items []importItem
+	doc.Find("dt>a").Each(func(_ int, a *goquery.Selection) {
+		url := a.AttrOr("href", "")
+		if url == "" {
+			return
+		}
+
+		dt := a.Parent()
+		collection := strings.TrimSpace(dt.Parent().Parent().ChildrenFiltered("h3").First().Text())
+
+		var desc string
+		if dd := dt.Next(); dd.Is("dd") {
+			desc = strings.TrimSpace(dd.Text())
+		}
+
+		items = append(items, importItem{
+			bookmark: &gosuki.Bookmark{
+				URL:   url,
+				Title: strings.TrimSpace(a.Text()),
+				Tags:  raindropTags(splitTags(a.AttrOr("tags", ""), ","), collection, false),
+				Desc:  desc,
+			},
+			added: unixTime(a.AttrOr("add_date", "")),
+		})
+	})
+	return items, nil
+}
@@ -0,0 +1,91 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/browsers/safari"
+)
+
+const SafariImporterID = "safari-import"
+
+var importSafariCmd = &cli.Command{
+	Name:  "safari",
+	Usage: "Import bookmarks from a Safari Bookmarks.plist file or HTML export",
+	Description: `Import the bookmarks of Safari from the Bookmarks.plist file found in
+~/Library/Safari on macOS, or from the HTML file of the "File > Export >
+Bookmarks" menu. macOS is not needed, the files can be copied from another
+machine.
+
+Folders are kept as tags. Reading List items are added to the read later
+queue, unread unless they were viewed in Safari, with their preview text as
+description.`,
+	Action:    runImport(safariImporter{}, ".plist", ".html"),
+	ArgsUsage: "path/to/Bookmarks.plist",
+	Arguments: []cli.Argument{importPathArg("Path to the Safari Bookmarks.plist or HTML export file")},
+	Flags:     []cli.Flag{dryRunFlag},
+}
+
+type safariImporter struct{}
+
+func (safariImporter) Module() string { return SafariImporterID }
+
+func (safariImporter) Parse(r io.Reader, ext string) ([]importItem, error) {
+	var bookmarks *safari.Bookmarks
+	if ext == ".html" {
+		var err error
+		if bookmarks, err = safari.ParseHTML(r); err != nil {
+			return nil, err
+		}
+	} else {
+		// plists need to be read from a seeker
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		if bookmarks, err = safari.ParsePlist(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	items := treeItems(bookmarks.Tree)
+	for _, rl := range bookmarks.ReadingList {
+		items = append(items, importItem{
+			bookmark: &gosuki.Bookmark{
+				URL:   rl.URL,
+				Title: rl.Title,
+				Desc:  rl.Preview,
+			},
+			queued: true,
+			unread: rl.Viewed.IsZero(),
+			added:  rl.Added,
+			read:   rl.Viewed,
+		})
+	}
+	return items, nil
+}
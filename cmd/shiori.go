@@ -0,0 +1,122 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
+)
+
+const (
+	ShioriImporterID = "shiori-import"
+
+	shioriTimeLayout = "2006-01-02 15:04:05"
+)
+
+var importShioriCmd = &cli.Command{
+	Name:  "shiori",
+	Usage: "Import bookmarks from Shiori in JSON format",
+	Description: `Import the bookmarks returned by the Shiori bookmarks API, either the
+response object or a list of bookmarks.
+
+Tags, excerpts and creation times are kept.`,
+	Action:    runImport(shioriImporter{}, ".json"),
+	ArgsUsage: "path/to/shiori.json",
+	Arguments: []cli.Argument{importPathArg("Path to the Shiori JSON file")},
+	Flags:     []cli.Flag{dryRunFlag},
+}
+
+type shioriImporter struct{}
+
+func (shioriImporter) Module() string { return ShioriImporterID }
+
+func (shioriImporter) Parse(r io.Reader, _ string) ([]importItem, error) {
+	return parseShioriJSON(r)
+}
+
+type shioriBookmark struct {
+	URL     string `json:"url"`
+	Title   string `json:"title"`
+	Excerpt string `json:"excerpt"`
+	Tags    []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+
+	// older versions only have the modification time
+	CreatedAt string `json:"createdAt"`
+	Modified  string `json:"modified"`
+}
+
+// parseShioriJSON parses the response of the Shiori API, with the bookmarks
+// in its bookmarks field, or a list of bookmarks
+func parseShioriJSON(r io.Reader) ([]importItem, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var bookmarks []shioriBookmark
+	if err = json.Unmarshal(data, &bookmarks); err != nil {
+		var resp struct {
+			Bookmarks []shioriBookmark `json:"bookmarks"`
+		}
+		if json.Unmarshal(data, &resp) != nil {
+			return nil, fmt.Errorf("failed to read JSON: %w", err)
+		}
+		bookmarks = resp.Bookmarks
+	}
+
+	var items []importItem
+	for _, bk := range bookmarks {
+		if bk.URL == "" {
+			continue
+		}
+
+		var tags []string
+		for _, tag := range bk.Tags {
+			tags = append(tags, tag.Name)
+		}
+
+		added := bk.CreatedAt
+		if added == "" {
+			added = bk.Modified
+		}
+
+		items = append(items, importItem{
+			bookmark: &gosuki.Bookmark{
+				URL:   bk.URL,
+				Title: bk.Title,
+				Tags:  tags,
+				Desc:  bk.Excerpt,
+			},
+			added: parseTime(added, shioriTimeLayout, time.RFC3339),
+		})
+	}
+	return items, nil
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/urfave/cli/v3"
 
 	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/ctl"
 	db "github.com/blob42/gosuki/internal/database"
 )
 
@@ -57,6 +58,9 @@ var FuzzySearchCmd = &cli.Command{
 func formatMark(format string) (string, error) {
 	outFormat := strings.Clone(format)
 
+	// bookmark id
+	outFormat = strings.ReplaceAll(outFormat, "%i", `{{.ID}}`)
+
 	// Comma separated list of tags
 	outFormat = strings.ReplaceAll(outFormat, "%T", `{{ join .Tags "," }}`)
 
@@ -69,6 +73,9 @@ func formatMark(format string) (string, error) {
 	// description
 	outFormat = strings.ReplaceAll(outFormat, "%d", `{{.Desc}}`)
 
+	// comma separated list of flags
+	outFormat = strings.ReplaceAll(outFormat, "%f", `{{ flags .Flags }}`)
+
 	r := strings.NewReplacer(`\t`, "\t", `\n`, "\n")
 	outFormat = r.Replace(outFormat)
 
@@ -79,7 +86,10 @@ func formatMark(format string) (string, error) {
 func formatPrint(_ context.Context, cmd *cli.Command, marks []*gosuki.Bookmark) error {
 	for _, mark := range marks {
 		if format := cmd.String("format"); format != "" {
-			funcs := template.FuncMap{"join": strings.Join}
+			funcs := template.FuncMap{
+				"join":  strings.Join,
+				"flags": func(f int) string { return db.Flags(f).String() },
+			}
 			outFormat, err := formatMark(format)
 			if err != nil {
 				return err
@@ -104,26 +114,29 @@ func formatPrint(_ context.Context, cmd *cli.Command, marks []*gosuki.Bookmark)
 }
 
 func listBookmarks(ctx context.Context, cmd *cli.Command) error {
-	pageParms := db.PaginationParams{
-		Page: 1,
-		Size: -1,
-	}
-	result, err := db.ListBookmarks(ctx, &pageParms)
+	marks, err := store.Query(ctx, ctl.QueryArgs{
+		Unread:   cmd.Bool("unread"),
+		Archived: cmd.Bool("archived"),
+	})
 	if err != nil {
 		return err
 	}
 
-	return formatPrint(ctx, cmd, result.Bookmarks)
+	return formatPrint(ctx, cmd, marks)
 }
 
 func searchBookmarks(ctx context.Context, cmd *cli.Command, opts searchOpts, keyword ...string) error {
-	pageParms := db.PaginationParams{
-		Page: 1,
-		Size: -1,
+	// the read later queue and the archive are listed, not searched
+	if cmd.Bool("unread") || cmd.Bool("archived") {
+		return errors.New("--unread and --archived cannot be combined with a search")
 	}
-	result, err := db.QueryBookmarks(ctx, keyword[0], opts.fuzzy, &pageParms)
+
+	marks, err := store.Query(ctx, ctl.QueryArgs{
+		Query: keyword[0],
+		Fuzzy: opts.fuzzy,
+	})
 	if err != nil {
 		return err
 	}
-	return formatPrint(ctx, cmd, result.Bookmarks)
+	return formatPrint(ctx, cmd, marks)
 }
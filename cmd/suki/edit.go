@@ -0,0 +1,412 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+// Module name used for bookmarks created with suki
+const sukiModule = "suki"
+
+const editNote = `
+Changes are sent to the gosuki daemon if it is running, otherwise they are
+written to the database. Bookmarks that still exist in a watched browser or module may be
+added back or retagged the next time that source is synced.`
+
+var AddCmd = &cli.Command{
+	Name:        "add",
+	Aliases:     []string{"a"},
+	Usage:       "add a bookmark",
+	UsageText:   "suki add [options] URL",
+	Description: "Add a new bookmark or replace an existing one with the same URL." + editNote,
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "tag",
+			Aliases: []string{"t"},
+			Usage:   "add `TAG` to the bookmark (can be repeated)",
+		},
+		&cli.StringFlag{
+			Name:  "title",
+			Usage: "bookmark `TITLE`",
+		},
+		&cli.StringFlag{
+			Name:    "desc",
+			Aliases: []string{"d"},
+			Usage:   "bookmark `DESCRIPTION`",
+		},
+		&cli.BoolFlag{
+			Name:    "fetch",
+			Aliases: []string{"F"},
+			Usage:   "fetch the page title if no title is given",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if cmd.Args().Len() != 1 {
+			return errors.New("expected exactly one URL")
+		}
+
+		bk := &gosuki.Bookmark{
+			URL:    cmd.Args().First(),
+			Title:  cmd.String("title"),
+			Tags:   cmd.StringSlice("tag"),
+			Desc:   cmd.String("desc"),
+			Module: sukiModule,
+		}
+
+		if bk.Title == "" && cmd.Bool("fetch") {
+			title, err := fetchTitle(ctx, bk.URL)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "could not fetch title: %s\n", err)
+			}
+			bk.Title = title
+		}
+
+		return store.Set(ctx, bk)
+	},
+}
+
+var EditCmd = &cli.Command{
+	Name:      "edit",
+	Aliases:   []string{"e"},
+	Usage:     "edit a bookmark",
+	UsageText: "suki edit [options] ID",
+	Description: `Edit the title, tags or description of the bookmark with the given ID.
+Use the %i format placeholder to display bookmark IDs.
+
+Without options, the bookmark is opened in $EDITOR.` + editNote,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "title",
+			Usage: "set the bookmark `TITLE`",
+		},
+		&cli.StringFlag{
+			Name:    "desc",
+			Aliases: []string{"d"},
+			Usage:   "set the bookmark `DESCRIPTION`",
+		},
+		&cli.StringFlag{
+			Name:    "tags",
+			Aliases: []string{"t"},
+			Usage:   "replace the tags with a comma separated list of `TAGS`",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if cmd.Args().Len() != 1 {
+			return errors.New("expected exactly one bookmark ID")
+		}
+
+		bk, err := bookmarkByID(ctx, cmd.Args().First())
+		if err != nil {
+			return err
+		}
+
+		if !cmd.IsSet("title") && !cmd.IsSet("desc") && !cmd.IsSet("tags") {
+			if err = editInEditor(bk); err != nil {
+				return err
+			}
+			return store.Set(ctx, bk)
+		}
+
+		if cmd.IsSet("title") {
+			bk.Title = cmd.String("title")
+		}
+		if cmd.IsSet("desc") {
+			bk.Desc = cmd.String("desc")
+		}
+		if cmd.IsSet("tags") {
+			bk.Tags = splitTags(cmd.String("tags"))
+		}
+
+		return store.Set(ctx, bk)
+	},
+}
+
+var RemoveCmd = &cli.Command{
+	Name:        "rm",
+	Usage:       "remove bookmarks",
+	UsageText:   "suki rm ID [ID...]",
+	Description: "Remove the bookmarks with the given IDs." + editNote,
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if !cmd.Args().Present() {
+			return errors.New("missing bookmark ID")
+		}
+
+		for _, arg := range cmd.Args().Slice() {
+			bk, err := bookmarkByID(ctx, arg)
+			if err != nil {
+				return err
+			}
+
+			if err = store.Remove(ctx, bk.URL); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
+var TagCmd = &cli.Command{
+	Name:      "tag",
+	Usage:     "add or remove tags of a bookmark",
+	UsageText: "suki tag ID [+]TAG|-TAG [TAG...]",
+	Description: `Add or remove tags of the bookmark with the given ID.
+Tags prefixed with '-' are removed, other tags are added.
+
+Example:
+  suki tag 42 +golang -todo cli` + editNote,
+	// tags to remove are prefixed with '-'
+	SkipFlagParsing: true,
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if cmd.Args().Len() < 2 {
+			return errors.New("expected a bookmark ID and at least one tag")
+		}
+
+		bk, err := bookmarkByID(ctx, cmd.Args().First())
+		if err != nil {
+			return err
+		}
+
+		bk.Tags = applyTagOps(bk.Tags, cmd.Args().Tail())
+
+		return store.Set(ctx, bk)
+	},
+}
+
+var FlagCmd = &cli.Command{
+	Name:      "flag",
+	Usage:     "set or clear flags of a bookmark",
+	UsageText: "suki flag ID [+]FLAG|-FLAG [FLAG...]",
+	Description: `Set or clear flags of the bookmark with the given ID.
+Flags prefixed with '-' are cleared, other flags are set. Without flags, the
+flags of the bookmark are printed.
+
+Available flags: ` + strings.Join(gosuki.FlagNames(), ", ") + `
+
+Example:
+  suki flag 42 +pinned -read-later` + editNote,
+	// flags to clear are prefixed with '-'
+	SkipFlagParsing: true,
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if !cmd.Args().Present() {
+			return errors.New("missing bookmark ID")
+		}
+
+		bk, err := bookmarkByID(ctx, cmd.Args().First())
+		if err != nil {
+			return err
+		}
+
+		if cmd.Args().Len() == 1 {
+			fmt.Println(db.Flags(bk.Flags))
+			return nil
+		}
+
+		set, clear, err := parseFlagOps(cmd.Args().Tail())
+		if err != nil {
+			return err
+		}
+
+		return store.Flag(ctx, bk.URL, set, clear)
+	},
+}
+
+func bookmarkByID(ctx context.Context, arg string) (*gosuki.Bookmark, error) {
+	id, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bookmark ID: %s", arg)
+	}
+
+	bk, err := store.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("bookmark %d: %w", id, err)
+	}
+
+	return bk, nil
+}
+
+func splitTags(s string) []string {
+	var tags []string
+	for tag := range strings.SplitSeq(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// applyTagOps adds tags prefixed with '+' or without prefix and removes tags
+// prefixed with '-'
+func applyTagOps(tags []string, ops []string) []string {
+	for _, op := range ops {
+		switch {
+		case strings.HasPrefix(op, "-"):
+			tag := op[1:]
+			res := tags[:0]
+			for _, t := range tags {
+				if t != tag {
+					res = append(res, t)
+				}
+			}
+			tags = res
+		default:
+			tag := strings.TrimPrefix(op, "+")
+			if tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	return tags
+}
+
+// parseFlagOps returns the flags to set and to clear. Flags prefixed with '-'
+// are cleared.
+func parseFlagOps(ops []string) (set, clear db.Flags, err error) {
+	for _, op := range ops {
+		name, remove := strings.CutPrefix(op, "-")
+		flag, err := gosuki.ParseFlag(strings.TrimPrefix(name, "+"))
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if remove {
+			clear |= flag
+			set &^= flag
+		} else {
+			set |= flag
+			clear &^= flag
+		}
+	}
+	return set, clear, nil
+}
+
+// descDelim starts the description in the editor, the description is
+// everything below it and can span several lines
+const descDelim = "---"
+
+const editTemplate = `# Edit the bookmark then save and quit. Lines starting with '#' are ignored.
+# The description is everything below the ` + descDelim + ` line.
+# URL: %s
+title: %s
+tags: %s
+` + descDelim + `
+%s
+`
+
+// editInEditor opens the bookmark in $EDITOR and updates it with the result
+func editInEditor(bk *gosuki.Bookmark) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	f, err := os.CreateTemp("", "suki-*.txt")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = fmt.Fprintf(f, editTemplate,
+		bk.URL,
+		bk.Title,
+		strings.Join(bk.Tags, ","),
+		bk.Desc,
+	)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	editCmd := exec.Command("sh", "-c", editor+` "$1"`, "--", f.Name())
+	editCmd.Stdin = os.Stdin
+	editCmd.Stdout = os.Stdout
+	editCmd.Stderr = os.Stderr
+	if err = editCmd.Run(); err != nil {
+		return fmt.Errorf("running editor: %w", err)
+	}
+
+	f, err = os.Open(f.Name())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return parseEdited(f, bk)
+}
+
+// parseEdited updates bk with the fields of the edited file. The lines below
+// the description delimiter are kept as is.
+func parseEdited(r io.Reader, bk *gosuki.Bookmark) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	header, desc, hasDesc := strings.Cut(string(data), "\n"+descDelim+"\n")
+	if hasDesc {
+		// the template ends the description with a newline
+		bk.Desc = strings.TrimSuffix(desc, "\n")
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(header))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return fmt.Errorf("invalid line: %q", line)
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.TrimSpace(key) {
+		case "title":
+			bk.Title = value
+		case "tags":
+			bk.Tags = splitTags(value)
+		case "desc":
+			bk.Desc = value
+		default:
+			return fmt.Errorf("unknown field: %q", key)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+func TestParseEdited(t *testing.T) {
+	desc := "first line\n\n# not a comment\n---\nlast line"
+
+	t.Run("unchanged file keeps the description", func(t *testing.T) {
+		bk := &gosuki.Bookmark{URL: "https://example.com", Title: "Example", Tags: []string{"a", "b"}, Desc: desc}
+		edited := fmt.Sprintf(editTemplate, bk.URL, bk.Title, strings.Join(bk.Tags, ","), bk.Desc)
+
+		require.NoError(t, parseEdited(strings.NewReader(edited), bk))
+		assert.Equal(t, "Example", bk.Title)
+		assert.Equal(t, []string{"a", "b"}, bk.Tags)
+		assert.Equal(t, desc, bk.Desc)
+	})
+
+	tests := []struct {
+		name   string
+		edited string
+		want   gosuki.Bookmark
+	}{
+		{
+			name:   "edited fields",
+			edited: "# comment\ntitle: New title\ntags: x, y\n---\nnew\ndescription\n",
+			want:   gosuki.Bookmark{Title: "New title", Tags: []string{"x", "y"}, Desc: "new\ndescription"},
+		},
+		{
+			name:   "empty description",
+			edited: "title: t\ntags:\n---\n\n",
+			want:   gosuki.Bookmark{Title: "t", Tags: []string{}, Desc: ""},
+		},
+		{
+			name:   "single line desc field",
+			edited: "title: t\ntags: a\ndesc: one line\n",
+			want:   gosuki.Bookmark{Title: "t", Tags: []string{"a"}, Desc: "one line"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bk := &gosuki.Bookmark{Desc: "old"}
+			require.NoError(t, parseEdited(strings.NewReader(tt.edited), bk))
+			assert.Equal(t, tt.want.Title, bk.Title)
+			assert.ElementsMatch(t, tt.want.Tags, bk.Tags)
+			assert.Equal(t, tt.want.Desc, bk.Desc)
+		})
+	}
+
+	bk := &gosuki.Bookmark{}
+	assert.Error(t, parseEdited(strings.NewReader("title: t\nnot a field\n"), bk))
+}
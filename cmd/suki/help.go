@@ -44,10 +44,12 @@ GLOBAL OPTIONS:{{template "visibleFlagTemplate" .}}{{end}}
 OUTPUT FORMATTING:
    You can customize the output format using the following placeholders:
 
+   %i - Bookmark ID
    %T - Comma separated list of tags
    %u - URL
    %t - Title
    %d - Description
+   %f - Comma separated list of flags
 
 You can combine these placeholders to create a custom output format. For example: "--format "%T, %u: %t"
 
@@ -0,0 +1,93 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package main
+
+import (
+	"context"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/ctl"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+// bookmarkStore is the backend used by suki to read and write bookmarks. When
+// the daemon is running, suki talks to it through the control socket,
+// otherwise it uses the database file directly.
+type bookmarkStore interface {
+	Query(ctx context.Context, args ctl.QueryArgs) ([]*gosuki.Bookmark, error)
+	Get(ctx context.Context, id uint64) (*gosuki.Bookmark, error)
+	Set(ctx context.Context, bk *gosuki.Bookmark) error
+	Remove(ctx context.Context, url string) error
+	Flag(ctx context.Context, url string, set, clear db.Flags) error
+}
+
+var store bookmarkStore
+
+// daemonStore talks to the running daemon
+type daemonStore struct {
+	client *ctl.Client
+}
+
+func (s daemonStore) Query(_ context.Context, args ctl.QueryArgs) ([]*gosuki.Bookmark, error) {
+	return s.client.Query(args)
+}
+
+func (s daemonStore) Get(_ context.Context, id uint64) (*gosuki.Bookmark, error) {
+	return s.client.Get(id)
+}
+
+func (s daemonStore) Set(_ context.Context, bk *gosuki.Bookmark) error {
+	return s.client.Set(bk)
+}
+
+func (s daemonStore) Remove(_ context.Context, url string) error {
+	return s.client.Remove(url)
+}
+
+func (s daemonStore) Flag(_ context.Context, url string, set, clear db.Flags) error {
+	_, err := s.client.Flag(url, set, clear)
+	return err
+}
+
+// diskStore uses the on-disk database
+type diskStore struct{}
+
+func (diskStore) Query(ctx context.Context, args ctl.QueryArgs) ([]*gosuki.Bookmark, error) {
+	return ctl.QueryDB(ctx, db.DiskDB, args)
+}
+
+func (diskStore) Get(ctx context.Context, id uint64) (*gosuki.Bookmark, error) {
+	return db.GetBookmarkByID(ctx, id)
+}
+
+func (diskStore) Set(ctx context.Context, bk *gosuki.Bookmark) error {
+	return db.SetBookmark(ctx, bk)
+}
+
+func (diskStore) Remove(ctx context.Context, url string) error {
+	return db.RemoveBookmark(ctx, url)
+}
+
+func (diskStore) Flag(ctx context.Context, url string, set, clear db.Flags) error {
+	return db.SetFlags(ctx, url, set, clear)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/urfave/cli/v3"
 
 	"github.com/blob42/gosuki/cmd"
+	"github.com/blob42/gosuki/internal/ctl"
 	db "github.com/blob42/gosuki/internal/database"
 	"github.com/blob42/gosuki/pkg/build"
 	"github.com/blob42/gosuki/pkg/config"
@@ -50,11 +51,19 @@ The default output format is fully compatible with dmenu and other dmenu-compati
 enabling seamless integration into your workflow through piping. Additionally, suki supports
 customizable output formatting through the -F flag, allowing you to tailor the display to your specific needs.
 
+When the gosuki daemon is running, suki talks to it through its control socket and sees the
+latest bookmarks before they are written to disk. Otherwise the database file is used directly.
+
 Usage examples:
   suki                    # Display all bookmarks in dmenu-compatible format
   suki -f "%u | %t"       # Show only bookmark urls 
   suki "search term"      # Search for specific bookmarks
-  suki | dmenu            # Pipe output to dmenu for interactive selection`
+  suki --unread           # List the read later queue
+  suki --archived         # List the archived bookmarks
+  suki | dmenu            # Pipe output to dmenu for interactive selection
+  suki add -t go URL      # Add a bookmark tagged with "go"
+  suki tag 42 +go -todo   # Add and remove tags of the bookmark with ID 42
+  suki flag 7 -read-later # Mark the bookmark with ID 7 as read`
 	app.UsageText = "suki [OPTIONS] [KEYWORD [KEYWORD...]] "
 	app.HideVersion = true
 	app.CustomRootCommandHelpTemplate = AppHelpTemplate
@@ -66,24 +75,51 @@ Usage examples:
 			Usage:   "Format output using a custom template",
 			Aliases: []string{"f"},
 		},
+
+		&cli.BoolFlag{
+			Name:    "unread",
+			Usage:   "List the unread bookmarks of the read later queue",
+			Aliases: []string{"u"},
+		},
+
+		&cli.BoolFlag{
+			Name:  "archived",
+			Usage: "List the archived bookmarks, hidden from the default list",
+		},
 	}
 	app.Flags = append(app.Flags, cmd.MainFlags...)
 
 	app.Before = func(ctx context.Context, c *cli.Command) (context.Context, error) {
 		config.Init(c.String("config"))
+
+		// prefer the running daemon
+		client, err := ctl.Dial(ctl.SocketPath(config.DBPath))
+		if err == nil {
+			store = daemonStore{client}
+			return ctx, nil
+		} else if err != ctl.ErrNotRunning {
+			fmt.Fprintln(os.Stderr, "could not connect to gosuki daemon:", err)
+		}
+
 		db.RegisterSqliteHooks()
-		err := db.InitDiskConn(config.DBPath)
+		err = db.InitDiskConn(config.DBPath)
 		if _, isDBErr := err.(db.DBError); isDBErr {
 			fmt.Fprintln(os.Stderr, "Database initialization failed:", err)
 			fmt.Fprintln(os.Stderr, "Please ensure you have run `gosuki start` to initialize the database")
 			os.Exit(10)
 		}
+		store = diskStore{}
 
 		return ctx, err
 	}
 
 	app.Commands = []*cli.Command{
 		FuzzySearchCmd,
+		AddCmd,
+		EditCmd,
+		RemoveCmd,
+		TagCmd,
+		FlagCmd,
 	}
 
 	app.ExitErrHandler = func(ctx context.Context, cli *cli.Command, err error) {
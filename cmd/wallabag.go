@@ -0,0 +1,118 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
+)
+
+const (
+	WallabagImporterID = "wallabag-import"
+)
+
+var importWallabagCmd = &cli.Command{
+	Name:  "wallabag",
+	Usage: "Import bookmarks from a wallabag export in JSON format",
+	Description: `Import the entries of a wallabag JSON export, as downloaded from the
+"Export" menu of the unread, archived or all entries lists.
+
+Unread entries are added to the read later queue, archived ones are marked as
+read. Tags are kept and starred entries are tagged with "starred".`,
+	Action:    runImport(wallabagImporter{}, ".json"),
+	ArgsUsage: "path/to/wallabag-export.json",
+	Arguments: []cli.Argument{importPathArg("Path to the wallabag JSON export file")},
+	Flags:     []cli.Flag{dryRunFlag},
+}
+
+type wallabagImporter struct{}
+
+func (wallabagImporter) Module() string { return WallabagImporterID }
+
+func (wallabagImporter) Parse(r io.Reader, _ string) ([]importItem, error) {
+	return parseWallabagJSON(r)
+}
+
+// wallabagBool decodes the booleans of wallabag exports which are 0 and 1 in
+// older versions
+type wallabagBool bool
+
+func (b *wallabagBool) UnmarshalJSON(data []byte) error {
+	*b = wallabagBool(bytes.Equal(data, []byte("true")) || bytes.Equal(data, []byte("1")))
+	return nil
+}
+
+type wallabagEntry struct {
+	URL        string       `json:"url"`
+	Title      string       `json:"title"`
+	Tags       []string     `json:"tags"`
+	IsArchived wallabagBool `json:"is_archived"`
+	IsStarred  wallabagBool `json:"is_starred"`
+	CreatedAt  string       `json:"created_at"`
+	ArchivedAt string       `json:"archived_at"`
+}
+
+// wallabagTime parses the dates of wallabag exports, it returns the zero time
+// on error
+func wallabagTime(s string) time.Time {
+	return parseTime(s, time.RFC3339, "2006-01-02T15:04:05-0700")
+}
+
+func parseWallabagJSON(r io.Reader) ([]importItem, error) {
+	var entries []wallabagEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to read JSON: %w", err)
+	}
+
+	var items []importItem
+	for _, entry := range entries {
+		if entry.URL == "" {
+			continue
+		}
+
+		tags := entry.Tags
+		if entry.IsStarred {
+			tags = append(tags, "starred")
+		}
+
+		items = append(items, importItem{
+			bookmark: &gosuki.Bookmark{
+				URL:   entry.URL,
+				Title: entry.Title,
+				Tags:  tags,
+			},
+			queued: true,
+			unread: !bool(entry.IsArchived),
+			added:  wallabagTime(entry.CreatedAt),
+			read:   wallabagTime(entry.ArchivedAt),
+		})
+	}
+	return items, nil
+}
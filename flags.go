@@ -0,0 +1,120 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package gosuki
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Flags is the bitmask stored in the `flags` column of the bookmarks.
+// Flags are set by the user and kept when the bookmarks are synced from the
+// browsers and modules, which can only add the [ModuleFlags].
+type Flags int
+
+const (
+	// The title is not replaced when the bookmark is updated by a module or
+	// with the title of the page
+	FlagImmutableTitle Flags = 1 << iota
+
+	// Pinned bookmarks are listed first
+	FlagPinned
+
+	// Private bookmarks are not exported or sent to webhooks by default
+	FlagPrivate
+
+	// The bookmark is in the read later queue
+	FlagReadLater
+
+	// Archived bookmarks are hidden from the bookmark list, they are still
+	// found by searches
+	FlagArchived
+)
+
+// ModuleFlags are the flags that modules and marktab rules can set on the
+// bookmarks they save
+const ModuleFlags = FlagReadLater | FlagArchived
+
+// Names of the flags used by the cli, the api and the web UI
+var flagNames = []struct {
+	flag Flags
+	name string
+}{
+	{FlagImmutableTitle, "immutable-title"},
+	{FlagPinned, "pinned"},
+	{FlagPrivate, "private"},
+	{FlagReadLater, "read-later"},
+	{FlagArchived, "archived"},
+}
+
+// FlagNames returns the names of all the flags
+func FlagNames() []string {
+	var names []string
+	for _, f := range flagNames {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+// ParseFlag returns the flag with the given name
+func ParseFlag(name string) (Flags, error) {
+	for _, f := range flagNames {
+		if f.name == name {
+			return f.flag, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown flag %q, expected one of: %s",
+		name, strings.Join(FlagNames(), ", "))
+}
+
+// ParseFlags returns the flags with the given names
+func ParseFlags(names []string) (Flags, error) {
+	var flags Flags
+	for _, name := range names {
+		flag, err := ParseFlag(name)
+		if err != nil {
+			return 0, err
+		}
+		flags |= flag
+	}
+	return flags, nil
+}
+
+func (f Flags) Has(flag Flags) bool {
+	return f&flag == flag
+}
+
+// Names returns the names of the flags set in f
+func (f Flags) Names() []string {
+	var names []string
+	for _, n := range flagNames {
+		if f.Has(n.flag) {
+			names = append(names, n.name)
+		}
+	}
+	return names
+}
+
+func (f Flags) String() string {
+	return strings.Join(f.Names(), ",")
+}
@@ -0,0 +1,25 @@
+package gosuki
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFlag(t *testing.T) {
+	for _, name := range FlagNames() {
+		flag, err := ParseFlag(name)
+		require.NoError(t, err)
+		assert.Equal(t, []string{name}, flag.Names())
+	}
+
+	_, err := ParseFlag("starred")
+	assert.ErrorContains(t, err, "pinned")
+
+	flags := FlagPinned | FlagArchived
+	assert.True(t, flags.Has(FlagPinned))
+	assert.False(t, flags.Has(FlagPrivate))
+	assert.Equal(t, "pinned,archived", flags.String())
+	assert.Equal(t, "", Flags(0).String())
+}
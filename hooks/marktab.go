@@ -23,37 +23,25 @@
 package hooks
 
 import (
-	"fmt"
-	"os"
-	"os/exec"
-	"strings"
-
 	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/logging"
 	"github.com/blob42/gosuki/pkg/marktab"
 	"github.com/blob42/gosuki/pkg/tree"
 )
 
-// MarkTab represents a collection of rules defined in the marktab file as lines.
-type MarkTab struct {
-	Rules []Rule // Rules contains all the parsed rules from the marktab file.
-}
-
-type Rule struct {
-	Trigger string // keyword to detect in the bookmark tags
-	Pattern string // regular expression used for matching against the bookmark URL or title.
-	Command string // shell command to execute when both the trigger and pattern match the bookmark tags.
+var log = logging.GetLogger("hooks")
 
-	empty bool // empty is an unexported field indicating whether the rule is empty.
-}
-
-// When a rule matches this bookmark, execute the action in the rule.Command
-// field. A new shell subprocess is spawned.
+// When a rule matches this bookmark, run the rule action. Mutating actions
+// (tag, desc, archive) are applied to the bookmark in order, so later rules see
+// the changes made by earlier ones.
 //
-// The child process receives the following exported fields:
-// - $URL
-// - $TITLE
-// - $TAGS
-// - $MODULE
+// The other actions are queued to the marktab runner which records their
+// result in the run history. Shell commands are run in a new shell subprocess
+// which receives the following exported fields:
+// - $GOSUKI_URL
+// - $GOSUKI_TITLE
+// - $GOSUKI_TAGS
+// - $GOSUKI_MODULE
 func marktabHook(item any) error {
 	err := marktab.PreloadRules()
 	if err != nil {
@@ -66,38 +54,47 @@ func marktabHook(item any) error {
 		if bk == nil {
 			panic("unexpected nil bookmark")
 		}
-		return processMtabHook(bk)
+
+		var folders []string
+		for _, f := range v.GetFolderParents() {
+			folders = append(folders, f.Title)
+		}
+
+		if processMtabHook(bk, folders) {
+			v.Tags = bk.Tags
+			v.Desc = bk.Desc
+			v.Flags = bk.Flags
+		}
+		return nil
 	case *gosuki.Bookmark:
 		if v == nil {
 			return nil
 		}
-		return processMtabHook(v)
+		processMtabHook(v, nil)
+		return nil
 	default:
 		panic("hook: unknown type")
 	}
 }
 
-func processMtabHook(bk *gosuki.Bookmark) error {
-	for _, rule := range marktab.CachedRules.Rules {
+// processMtabHook runs the matching rules on bk and reports whether bk was
+// modified by a rule action.
+func processMtabHook(bk *gosuki.Bookmark, folders []string) bool {
+	var changed bool
+	for _, rule := range marktab.GetRules() {
+		if !rule.Match(bk, folders...) {
+			continue
+		}
 
-		// Spawn a new shell subprocess with the rule's command, passing in
-		// the bookmark details.
-		if rule.Match(bk) {
-			cmd := exec.Command("sh", "-c", rule.Command)
-			cmd.Env = append(
-				os.Environ(),
-				"GOSUKI_URL="+bk.URL,
-				"GOSUKI_TITLE="+bk.Title,
-				"GOSUKI_TAGS="+strings.Join(bk.Tags, ","),
-				"GOSUKI_MODULE="+bk.Module,
-			)
-			if err := cmd.Start(); err != nil {
-				return fmt.Errorf("failed to start command: %w", err)
-			}
+		if rule.Action.Mutates() {
+			changed = rule.Action.Apply(bk) || changed
+			continue
 		}
+
+		mtabRunner.submit(rule, bk)
 	}
 
-	return nil
+	return changed
 }
 
 func NodeMktabHook(n *tree.Node) error {
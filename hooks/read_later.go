@@ -0,0 +1,94 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package hooks
+
+import (
+	"slices"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/tree"
+)
+
+type readLaterConfig struct {
+	// Bookmarks saved in the browser with one of these tags, as in
+	// `#toread`, are added to the read later queue
+	Tags []string `toml:"tags" mapstructure:"tags"`
+}
+
+var ReadLaterConfig = &readLaterConfig{
+	Tags: []string{"toread"},
+}
+
+func isReadLater(tags []string) bool {
+	return slices.ContainsFunc(tags, func(tag string) bool {
+		return slices.Contains(ReadLaterConfig.Tags, tag)
+	})
+}
+
+// Flags the bookmarks having one of the read later tags with
+// [db.FlagReadLater]. The tags are parsed from the title by the tag hooks
+// which must run first.
+func readLaterHook(item any) error {
+	switch v := item.(type) {
+	case *tree.Node:
+		if v.Type == tree.URLNode && isReadLater(v.Tags) {
+			v.Flags |= int(db.FlagReadLater)
+		}
+	case *gosuki.Bookmark:
+		if v != nil && isReadLater(v.Tags) {
+			v.Flags |= int(db.FlagReadLater)
+		}
+	default:
+		panic("hook: unknown type")
+	}
+	return nil
+}
+
+func NodeReadLaterHook(n *tree.Node) error {
+	return readLaterHook(n)
+}
+
+func BkReadLaterHook(b *gosuki.Bookmark) error {
+	return readLaterHook(b)
+}
+
+func init() {
+	regHook(
+		Hook[*tree.Node]{
+			name:     "node_read_later",
+			Func:     NodeReadLaterHook,
+			priority: 5,
+		},
+	)
+	regHook(
+		Hook[*gosuki.Bookmark]{
+			name:     "bk_read_later",
+			Func:     BkReadLaterHook,
+			priority: 5,
+		},
+	)
+
+	config.RegisterConfigurator("read-later", config.AsConfigurator(ReadLaterConfig))
+}
@@ -0,0 +1,33 @@
+package hooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/parsing"
+	"github.com/blob42/gosuki/pkg/tree"
+)
+
+func TestReadLaterHook(t *testing.T) {
+	node := &tree.Node{Title: "an article #toread", Type: tree.URLNode, URL: "https://a.com"}
+	require.NoError(t, parsing.ParseNodeTags(node))
+	require.NoError(t, NodeReadLaterHook(node))
+	assert.Equal(t, int(db.FlagReadLater), node.GetBookmark().Flags)
+
+	bk := &gosuki.Bookmark{URL: "https://b.com", Tags: []string{"go"}, Flags: int(db.FlagPinned)}
+	require.NoError(t, BkReadLaterHook(bk))
+	assert.Equal(t, int(db.FlagPinned), bk.Flags)
+
+	bk.Tags = append(bk.Tags, "toread")
+	require.NoError(t, BkReadLaterHook(bk))
+	assert.Equal(t, int(db.FlagPinned|db.FlagReadLater), bk.Flags)
+
+	// folders are not queued
+	folder := &tree.Node{Title: "folder", Type: tree.FolderNode, Tags: []string{"toread"}}
+	require.NoError(t, NodeReadLaterHook(folder))
+	assert.Zero(t, folder.Flags)
+}
@@ -0,0 +1,331 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xAX/notificator"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/marktab"
+)
+
+const (
+	// Maximum size of the command output kept in the run history
+	maxRunOutput = 4096
+
+	// Number of queued jobs. Jobs submitted while the queue is full are
+	// dropped and submitted again on the next change of the bookmark.
+	runnerQueueSize = 64
+
+	// Delay before running again an action that failed, doubled on each
+	// failed attempt up to maxRequeueDelay
+	requeueDelay    = time.Hour
+	maxRequeueDelay = 24 * time.Hour
+)
+
+var runsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gosuki_marktab_runs_total",
+	Help: "Number of marktab action attempts by status.",
+}, []string{"status"})
+
+type runnerConfig struct {
+	// Number of marktab actions running concurrently. Changes require a
+	// restart.
+	Workers int `toml:"workers" mapstructure:"workers"`
+
+	// Default timeout of shell and post actions
+	Timeout time.Duration `toml:"timeout" mapstructure:"timeout"`
+
+	// Default number of retries of failed actions
+	Retries int `toml:"retries" mapstructure:"retries"`
+
+	// Delay before the first retry, doubled on each attempt
+	RetryDelay time.Duration `toml:"retry-delay" mapstructure:"retry-delay"`
+
+	// Number of runs kept in the run history. The last successful run of a
+	// rule for a bookmark is always kept. 0 keeps all the runs.
+	History int `toml:"history" mapstructure:"history"`
+}
+
+var RunnerConfig = &runnerConfig{
+	Workers:    4,
+	Timeout:    time.Minute,
+	Retries:    0,
+	RetryDelay: 10 * time.Second,
+	History:    1000,
+}
+
+// job is the side effect action of a marktab rule to run for a bookmark
+type job struct {
+	rule marktab.Rule
+	bk   gosuki.Bookmark
+}
+
+func (j job) key() string {
+	return j.rule.Hash() + " " + j.bk.URL
+}
+
+// runner executes the marktab actions on a bounded pool of workers. Each
+// attempt is recorded in the run history and a rule runs successfully at most
+// once per bookmark. Failed actions are run again with a backoff, see
+// [requeueBackoff].
+type runner struct {
+	jobs  chan job
+	start sync.Once
+
+	mu      sync.Mutex
+	pending map[string]bool // jobs queued or running
+}
+
+var mtabRunner = &runner{
+	jobs:    make(chan job, runnerQueueSize),
+	pending: make(map[string]bool),
+}
+
+// submit queues the rule action for bk unless it is already queued, already
+// ran successfully or failed recently. The job is dropped if the queue is
+// full.
+func (r *runner) submit(rule marktab.Rule, bk *gosuki.Bookmark) {
+	r.start.Do(func() {
+		workers := max(RunnerConfig.Workers, 1)
+		for range workers {
+			go r.work()
+		}
+	})
+
+	j := job{rule: rule, bk: *bk}
+	j.bk.Tags = append([]string(nil), bk.Tags...)
+
+	key := j.key()
+	r.mu.Lock()
+	if r.pending[key] {
+		r.mu.Unlock()
+		return
+	}
+	r.pending[key] = true
+	r.mu.Unlock()
+
+	state := db.CachedMarktabRunState(context.Background(), rule.Hash(), bk.URL)
+	if state.Succeeded ||
+		time.Since(time.Unix(state.LastStarted, 0)) < requeueBackoff(state.Failures) {
+		r.done(key)
+		return
+	}
+
+	select {
+	case r.jobs <- j:
+	default:
+		log.Warn("marktab queue full, dropping action", "line", rule.Line, "url", bk.URL)
+		r.done(key)
+	}
+}
+
+// requeueBackoff returns the delay before running again an action after the
+// given number of failed attempts
+func requeueBackoff(failures int) time.Duration {
+	if failures == 0 {
+		return 0
+	}
+	return min(requeueDelay<<min(failures-1, 8), maxRequeueDelay)
+}
+
+func (r *runner) done(key string) {
+	r.mu.Lock()
+	delete(r.pending, key)
+	r.mu.Unlock()
+}
+
+func (r *runner) work() {
+	for j := range r.jobs {
+		r.run(j)
+		r.done(j.key())
+	}
+}
+
+// run executes the job, retrying failed attempts
+func (r *runner) run(j job) {
+	action := j.rule.Action
+	retries := action.Retries
+	if retries < 0 {
+		retries = RunnerConfig.Retries
+	}
+	timeout := action.Timeout
+	if timeout == 0 {
+		timeout = RunnerConfig.Timeout
+	}
+
+	delay := RunnerConfig.RetryDelay
+	for attempt := 1; attempt <= retries+1; attempt++ {
+		run := &db.MarktabRun{
+			Rule:     j.rule.Source,
+			RuleHash: j.rule.Hash(),
+			Line:     j.rule.Line,
+			URL:      j.bk.URL,
+			Action:   action.Type.String(),
+			Attempt:  attempt,
+			Started:  time.Now().Unix(),
+		}
+
+		start := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		output, exitCode, err := runAction(ctx, action, &j.bk)
+		timedOut := errors.Is(ctx.Err(), context.DeadlineExceeded)
+		cancel()
+
+		run.Duration = time.Since(start).Milliseconds()
+		run.ExitCode = exitCode
+		run.Output = truncate(output, maxRunOutput)
+		switch {
+		case err == nil:
+			run.Status = db.RunSuccess
+		case timedOut:
+			run.Status = db.RunTimeout
+		default:
+			run.Status = db.RunFailed
+			if run.Output == "" {
+				run.Output = err.Error()
+			}
+		}
+
+		runsTotal.WithLabelValues(string(run.Status)).Inc()
+		if err := db.RecordMarktabRun(context.Background(), run, RunnerConfig.History); err != nil {
+			log.Error("recording marktab run", "err", err)
+		}
+
+		if run.Status == db.RunSuccess {
+			return
+		}
+
+		log.Warn("marktab action failed",
+			"line", j.rule.Line,
+			"url", j.bk.URL,
+			"status", run.Status,
+			"attempt", attempt,
+			"err", err,
+		)
+
+		if attempt <= retries {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+}
+
+// runAction runs the side effect of a non mutating action. It returns the
+// output of the action and the exit code of shell commands.
+func runAction(ctx context.Context, action marktab.Action, bk *gosuki.Bookmark) (string, int, error) {
+	switch action.Type {
+	case marktab.ActionShell:
+		// Spawn a new shell subprocess with the rule's command, passing in
+		// the bookmark details.
+		cmd := exec.CommandContext(ctx, "sh", "-c", action.Arg)
+		cmd.Env = append(
+			os.Environ(),
+			"GOSUKI_URL="+bk.URL,
+			"GOSUKI_TITLE="+bk.Title,
+			"GOSUKI_TAGS="+strings.Join(bk.Tags, ","),
+			"GOSUKI_MODULE="+bk.Module,
+		)
+		// do not wait for background processes keeping the output open
+		cmd.WaitDelay = time.Second
+
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		err := cmd.Run()
+		exitCode := -1
+		if cmd.ProcessState != nil {
+			exitCode = cmd.ProcessState.ExitCode()
+		}
+		return out.String(), exitCode, err
+
+	case marktab.ActionNotify:
+		msg := action.Arg
+		if msg == "" {
+			msg = bk.URL
+		}
+		notify := notificator.New(notificator.Options{
+			AppName: "gosuki",
+		})
+		return "", 0, notify.Push(bk.Title, msg, "", notificator.UR_NORMAL)
+
+	case marktab.ActionPost:
+		return postBookmark(ctx, action.Arg, bk)
+	}
+
+	return "", 0, fmt.Errorf("unexpected action %s", action.Type)
+}
+
+func postBookmark(ctx context.Context, url string, bk *gosuki.Bookmark) (string, int, error) {
+	data, err := json.Marshal(bk)
+	if err != nil {
+		return "", 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxRunOutput))
+	output := resp.Status + "\n" + string(body)
+	if resp.StatusCode >= 300 {
+		return output, 0, fmt.Errorf("post %s: %s", url, resp.Status)
+	}
+	return output, 0, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "\n[truncated]"
+}
+
+func init() {
+	config.RegisterConfigurator("marktab", config.AsConfigurator(RunnerConfig))
+}
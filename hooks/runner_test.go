@@ -0,0 +1,154 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/marktab"
+)
+
+func TestRunShellAction(t *testing.T) {
+	bk := &gosuki.Bookmark{URL: "https://example.com", Tags: []string{"a", "b"}}
+	action := marktab.Action{
+		Type: marktab.ActionShell,
+		Arg:  `echo "$GOSUKI_URL $GOSUKI_TAGS"; echo oops >&2; exit 3`,
+	}
+
+	out, code, err := runAction(context.Background(), action, bk)
+	require.Error(t, err)
+	assert.Equal(t, 3, code)
+	assert.Contains(t, out, "https://example.com a,b")
+	assert.Contains(t, out, "oops")
+}
+
+func TestRunShellTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := runAction(ctx,
+		marktab.Action{Type: marktab.ActionShell, Arg: "sleep 5"},
+		&gosuki.Bookmark{},
+	)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 3*time.Second)
+}
+
+func TestRunPostAction(t *testing.T) {
+	var got gosuki.Bookmark
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		if strings.HasSuffix(r.URL.Path, "/fail") {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	bk := &gosuki.Bookmark{URL: "https://example.com", Title: "Example"}
+	_, _, err := runAction(context.Background(),
+		marktab.Action{Type: marktab.ActionPost, Arg: srv.URL + "/ok"}, bk)
+	require.NoError(t, err)
+	assert.Equal(t, "Example", got.Title)
+
+	out, _, err := runAction(context.Background(),
+		marktab.Action{Type: marktab.ActionPost, Arg: srv.URL + "/fail"}, bk)
+	require.Error(t, err)
+	assert.Contains(t, out, "500")
+}
+
+func TestRunnerRetries(t *testing.T) {
+	defer func(delay time.Duration) { RunnerConfig.RetryDelay = delay }(RunnerConfig.RetryDelay)
+	RunnerConfig.RetryDelay = time.Millisecond
+
+	counter := filepath.Join(t.TempDir(), "attempts")
+	rule := marktab.Rule{
+		Source: "tag=x -> shell[retry=2] ...",
+		Action: marktab.Action{
+			Type:    marktab.ActionShell,
+			Arg:     "echo x >> " + counter + "; exit 1",
+			Retries: 2,
+		},
+	}
+
+	r := &runner{pending: make(map[string]bool)}
+	r.run(job{rule: rule, bk: gosuki.Bookmark{URL: "https://example.com"}})
+
+	data, err := os.ReadFile(counter)
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(data), "x"))
+}
+
+func TestRunnerDedupe(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "runs")
+	rule := marktab.Rule{
+		Source: "tag=x -> shell ...",
+		Action: marktab.Action{
+			Type: marktab.ActionShell,
+			Arg:  "sleep 0.2; echo x >> " + counter,
+		},
+	}
+	bk := &gosuki.Bookmark{URL: "https://example.com"}
+
+	r := &runner{jobs: make(chan job, 4), pending: make(map[string]bool)}
+	for range 3 {
+		r.submit(rule, bk)
+	}
+
+	require.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return len(r.pending) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	data, err := os.ReadFile(counter)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "x"))
+}
+
+func TestRunnerQueueFull(t *testing.T) {
+	rule := marktab.Rule{
+		Source: "tag=x -> shell ...",
+		Action: marktab.Action{Type: marktab.ActionShell, Arg: "true"},
+	}
+
+	// no workers read the queue
+	r := &runner{jobs: make(chan job, 1), pending: make(map[string]bool)}
+	r.start.Do(func() {})
+
+	submitted := make(chan bool)
+	go func() {
+		r.submit(rule, &gosuki.Bookmark{URL: "https://a.com"})
+		r.submit(rule, &gosuki.Bookmark{URL: "https://b.com"})
+		submitted <- true
+	}()
+
+	select {
+	case <-submitted:
+	case <-time.After(time.Second):
+		t.Fatal("submit blocked on a full queue")
+	}
+
+	assert.Len(t, r.jobs, 1)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	assert.Len(t, r.pending, 1, "dropped job is not pending")
+}
+
+func TestRequeueBackoff(t *testing.T) {
+	assert.Zero(t, requeueBackoff(0))
+	assert.Equal(t, requeueDelay, requeueBackoff(1))
+	assert.Equal(t, 2*requeueDelay, requeueBackoff(2))
+	assert.Equal(t, maxRequeueDelay, requeueBackoff(100))
+}
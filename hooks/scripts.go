@@ -0,0 +1,504 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/tree"
+)
+
+// ErrDrop is returned by a hook to drop a bookmark. Dropped bookmarks are not
+// saved.
+var ErrDrop = errors.New("bookmark dropped by hook")
+
+// Scripts run before the read later and marktab hooks by default, so they
+// can add the tags matched by these hooks.
+const DefaultScriptPriority = 3
+
+type scriptsConfig struct {
+	// Directory holding the `*.star` hook scripts
+	Dir string `toml:"dir" mapstructure:"dir"`
+
+	// Maximum run time of a script on a bookmark
+	Timeout time.Duration `toml:"timeout" mapstructure:"timeout"`
+
+	// Maximum number of Starlark computation steps of a script on a
+	// bookmark
+	MaxSteps uint64 `toml:"max-steps" mapstructure:"max-steps"`
+}
+
+var ScriptsConfig = &scriptsConfig{
+	Dir:      "~/.config/gosuki/hooks",
+	Timeout:  100 * time.Millisecond,
+	MaxSteps: 1_000_000,
+}
+
+// Script is a user hook written in Starlark. A script is a `.star` file in
+// the scripts directory defining a `hook(bk)` function called on each
+// bookmark:
+//
+//	PRIORITY = 3  # optional
+//
+//	def hook(bk):
+//	    if "youtube.com" in bk.url:
+//	        bk.title = bk.title.removesuffix(" - YouTube")
+//	        return ["video"]
+//	    if re_match(r"^https?://ads\.", bk.url):
+//	        return DROP
+//
+// The bookmark has the `url`, `title`, `desc`, `tags`, `module` and `folders`
+// attributes. The title, description and tags can be changed. The hook may
+// return `None`, a list of tags to add or `DROP` to drop the bookmark.
+//
+// Scripts can not access the file system or the network and are stopped after
+// [scriptsConfig.Timeout] or [scriptsConfig.MaxSteps].
+type Script struct {
+	Name     string
+	Path     string
+	Priority uint
+
+	hook *starlark.Function
+}
+
+var (
+	scriptsMu sync.RWMutex
+	scripts   map[string]*Script
+)
+
+// dropValue is the value of DROP
+type dropValue struct{}
+
+func (dropValue) String() string        { return "DROP" }
+func (dropValue) Type() string          { return "drop" }
+func (dropValue) Freeze()               {}
+func (dropValue) Truth() starlark.Bool  { return starlark.True }
+func (dropValue) Hash() (uint32, error) { return 0, nil }
+
+var scriptPredeclared = starlark.StringDict{
+	"DROP":     dropValue{},
+	"json":     json.Module,
+	"re_match": starlark.NewBuiltin("re_match", reMatch),
+	"re_sub":   starlark.NewBuiltin("re_sub", reSub),
+}
+
+// re_match(pattern, s) reports whether s contains a match of pattern
+func reMatch(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var pattern, s string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 2, &pattern, &s); err != nil {
+		return nil, err
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	return starlark.Bool(re.MatchString(s)), nil
+}
+
+// re_sub(pattern, repl, s) replaces the matches of pattern in s with repl
+func reSub(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var pattern, repl, s string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 3, &pattern, &repl, &s); err != nil {
+		return nil, err
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	return starlark.String(re.ReplaceAllString(s, repl)), nil
+}
+
+// scriptBookmark is the bookmark passed to the hook function
+type scriptBookmark struct {
+	url     string
+	module  string
+	title   starlark.String
+	desc    starlark.String
+	tags    *starlark.List
+	folders starlark.Tuple
+	frozen  bool
+}
+
+func newScriptBookmark(bk *gosuki.Bookmark, folders []string) *scriptBookmark {
+	sb := &scriptBookmark{
+		url:    bk.URL,
+		module: bk.Module,
+		title:  starlark.String(bk.Title),
+		desc:   starlark.String(bk.Desc),
+		tags:   starlark.NewList(nil),
+	}
+	for _, tag := range bk.Tags {
+		sb.tags.Append(starlark.String(tag))
+	}
+	for _, folder := range folders {
+		sb.folders = append(sb.folders, starlark.String(folder))
+	}
+	return sb
+}
+
+func (b *scriptBookmark) String() string        { return fmt.Sprintf("<bookmark %s>", b.url) }
+func (b *scriptBookmark) Type() string          { return "bookmark" }
+func (b *scriptBookmark) Truth() starlark.Bool  { return starlark.True }
+func (b *scriptBookmark) Hash() (uint32, error) { return 0, errors.New("unhashable type: bookmark") }
+
+func (b *scriptBookmark) Freeze() {
+	b.frozen = true
+	b.tags.Freeze()
+}
+
+func (b *scriptBookmark) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "url":
+		return starlark.String(b.url), nil
+	case "module":
+		return starlark.String(b.module), nil
+	case "title":
+		return b.title, nil
+	case "desc":
+		return b.desc, nil
+	case "tags":
+		return b.tags, nil
+	case "folders":
+		return b.folders, nil
+	}
+	return nil, nil
+}
+
+func (b *scriptBookmark) AttrNames() []string {
+	return []string{"desc", "folders", "module", "tags", "title", "url"}
+}
+
+func (b *scriptBookmark) SetField(name string, val starlark.Value) error {
+	if b.frozen {
+		return errors.New("bookmark is frozen")
+	}
+
+	switch name {
+	case "title", "desc":
+		s, ok := val.(starlark.String)
+		if !ok {
+			return fmt.Errorf("bookmark.%s must be a string, got %s", name, val.Type())
+		}
+		if name == "title" {
+			b.title = s
+		} else {
+			b.desc = s
+		}
+	case "tags":
+		tags, err := stringList(val)
+		if err != nil {
+			return fmt.Errorf("bookmark.tags: %w", err)
+		}
+		b.tags = starlark.NewList(nil)
+		for _, tag := range tags {
+			b.tags.Append(starlark.String(tag))
+		}
+	default:
+		return fmt.Errorf("bookmark.%s can not be changed", name)
+	}
+	return nil
+}
+
+// stringList converts a list or tuple of strings
+func stringList(val starlark.Value) ([]string, error) {
+	iterable, ok := val.(starlark.Indexable)
+	if !ok {
+		return nil, fmt.Errorf("expected a list of strings, got %s", val.Type())
+	}
+
+	var result []string
+	for i := range iterable.Len() {
+		s, ok := starlark.AsString(iterable.Index(i))
+		if !ok {
+			return nil, fmt.Errorf("expected a list of strings, got %s", iterable.Index(i).Type())
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+func newScriptThread(name string) (*starlark.Thread, func()) {
+	thread := &starlark.Thread{
+		Name: name,
+		Print: func(_ *starlark.Thread, msg string) {
+			log.Debug(msg, "script", name)
+		},
+	}
+	thread.SetMaxExecutionSteps(ScriptsConfig.MaxSteps)
+	timer := time.AfterFunc(ScriptsConfig.Timeout, func() {
+		thread.Cancel("timeout")
+	})
+	return thread, func() { timer.Stop() }
+}
+
+// LoadScript compiles the script at path
+func LoadScript(path string, src any) (*Script, error) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	thread, stop := newScriptThread(name)
+	defer stop()
+
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, path, src, scriptPredeclared)
+	if err != nil {
+		return nil, err
+	}
+	globals.Freeze()
+
+	hook, ok := globals["hook"].(*starlark.Function)
+	if !ok {
+		return nil, fmt.Errorf("%s: missing hook(bk) function", path)
+	}
+	if hook.NumParams() != 1 {
+		return nil, fmt.Errorf("%s: hook must take a single bookmark argument", path)
+	}
+
+	script := &Script{
+		Name:     name,
+		Path:     path,
+		Priority: DefaultScriptPriority,
+		hook:     hook,
+	}
+
+	if val, ok := globals["PRIORITY"]; ok {
+		var priority int
+		if err := starlark.AsInt(val, &priority); err != nil || priority < 0 {
+			return nil, fmt.Errorf("%s: PRIORITY must be a positive integer", path)
+		}
+		script.Priority = uint(priority)
+	}
+
+	return script, nil
+}
+
+// Run calls the hook function of the script on bk. It reports whether the
+// bookmark is dropped.
+func (s *Script) Run(bk *gosuki.Bookmark, folders []string) (bool, error) {
+	thread, stop := newScriptThread(s.Name)
+	defer stop()
+
+	sb := newScriptBookmark(bk, folders)
+	res, err := starlark.Call(thread, s.hook, starlark.Tuple{sb}, nil)
+	if err != nil {
+		return false, err
+	}
+
+	var addTags []string
+	switch res := res.(type) {
+	case starlark.NoneType:
+	case dropValue:
+		return true, nil
+	default:
+		if addTags, err = stringList(res); err != nil {
+			return false, fmt.Errorf("hook must return None, DROP or a list of tags: %w", err)
+		}
+	}
+
+	tags, err := stringList(sb.tags)
+	if err != nil {
+		return false, err
+	}
+
+	bk.Title = string(sb.title)
+	bk.Desc = string(sb.desc)
+	bk.Tags = utils.Extends(tags, addTags...)
+	return false, nil
+}
+
+// loadScripts compiles the scripts of the scripts directory. Scripts which
+// fail to compile are skipped.
+func loadScripts() (map[string]*Script, error) {
+	result := map[string]*Script{}
+
+	dir, err := utils.ExpandOnly(ScriptsConfig.Dir)
+	if err != nil {
+		return result, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.star"))
+	if err != nil {
+		return result, err
+	}
+
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			log.Error("reading script", "path", path, "err", err)
+			continue
+		}
+		script, err := LoadScript(path, src)
+		if err != nil {
+			log.Error("loading script", "err", err)
+			continue
+		}
+		result[script.Name] = script
+	}
+
+	return result, nil
+}
+
+// PreloadScripts loads the hook scripts the first time it is called
+func PreloadScripts() error {
+	scriptsMu.Lock()
+	defer scriptsMu.Unlock()
+	if scripts != nil {
+		return nil
+	}
+
+	loaded, err := loadScripts()
+	scripts = loaded
+	return err
+}
+
+// ReloadScripts loads the hook scripts again. Changes to the code of the
+// existing scripts apply immediately. New scripts and changes of their
+// PRIORITY are used by the browsers started afterwards, which sort their hooks
+// once.
+func ReloadScripts() error {
+	loaded, err := loadScripts()
+	if err != nil {
+		return err
+	}
+
+	scriptsMu.Lock()
+	scripts = loaded
+	scriptsMu.Unlock()
+
+	log.Info("reloaded hook scripts", "scripts", len(loaded))
+	return nil
+}
+
+func getScript(name string) *Script {
+	scriptsMu.RLock()
+	defer scriptsMu.RUnlock()
+	return scripts[name]
+}
+
+// scriptHook runs the script called name on a node or bookmark. Script errors
+// are logged and leave the bookmark unchanged.
+func scriptHook(name string, item any) error {
+	script := getScript(name)
+	if script == nil {
+		return nil
+	}
+
+	var (
+		dropped bool
+		err     error
+	)
+
+	switch v := item.(type) {
+	case *tree.Node:
+		bk := v.GetBookmark()
+		if bk == nil {
+			return nil
+		}
+
+		var folders []string
+		for _, f := range v.GetFolderParents() {
+			folders = append(folders, f.Title)
+		}
+
+		if dropped, err = script.Run(bk, folders); err == nil {
+			v.Title = bk.Title
+			v.Desc = bk.Desc
+			v.Tags = bk.Tags
+		}
+	case *gosuki.Bookmark:
+		if v == nil {
+			return nil
+		}
+		dropped, err = script.Run(v, nil)
+	default:
+		panic("hook: unknown type")
+	}
+
+	if err != nil {
+		log.Warn("running script", "script", name, "err", err)
+		return nil
+	}
+	if dropped {
+		return ErrDrop
+	}
+	return nil
+}
+
+// scriptHooks returns the node and bookmark hooks of a script, named
+// `node_script_<name>` and `bk_script_<name>`. The hooks run the script
+// currently loaded with the same name. They are not registered in [Defined]
+// which is not safe to modify while the browsers are running.
+func scriptHooks(script *Script) []NamedHook {
+	name := script.Name
+	return []NamedHook{
+		Hook[*tree.Node]{
+			name:     "node_script_" + name,
+			Func:     func(n *tree.Node) error { return scriptHook(name, n) },
+			priority: script.Priority,
+		},
+		Hook[*gosuki.Bookmark]{
+			name:     "bk_script_" + name,
+			Func:     func(b *gosuki.Bookmark) error { return scriptHook(name, b) },
+			priority: script.Priority,
+		},
+	}
+}
+
+// ScriptHooks returns the hooks of the loaded scripts. They are used by all
+// the browsers besides the hooks of their config.
+func ScriptHooks() []NamedHook {
+	if err := PreloadScripts(); err != nil {
+		log.Error("loading hook scripts", "err", err)
+	}
+
+	scriptsMu.RLock()
+	defer scriptsMu.RUnlock()
+
+	names := make([]string, 0, len(scripts))
+	for name := range scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result []NamedHook
+	for _, name := range names {
+		result = append(result, scriptHooks(scripts[name])...)
+	}
+	return result
+}
+
+func init() {
+	config.RegisterConfigurator("scripts", config.AsConfigurator(ScriptsConfig))
+}
@@ -0,0 +1,167 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/tree"
+)
+
+const testScript = `
+PRIORITY = 7
+
+def hook(bk):
+    if "ads." in bk.url:
+        return DROP
+    if bk.module == "qute":
+        bk.tags = [t for t in bk.tags if t != "old"]
+    bk.title = re_sub(r"\s+-\s+YouTube$", "", bk.title)
+    if "work" in bk.folders:
+        return ["work", "video"]
+`
+
+func TestScript(t *testing.T) {
+	script, err := LoadScript("yt.star", testScript)
+	require.NoError(t, err)
+	assert.Equal(t, "yt", script.Name)
+	assert.Equal(t, uint(7), script.Priority)
+
+	bk := &gosuki.Bookmark{
+		URL:    "https://youtube.com/watch?v=1",
+		Title:  "A talk - YouTube",
+		Tags:   []string{"old", "video"},
+		Module: "qute",
+	}
+	dropped, err := script.Run(bk, []string{"work"})
+	require.NoError(t, err)
+	assert.False(t, dropped)
+	assert.Equal(t, "A talk", bk.Title)
+	assert.Equal(t, []string{"video", "work"}, bk.Tags)
+
+	dropped, err = script.Run(&gosuki.Bookmark{URL: "https://ads.example.com"}, nil)
+	require.NoError(t, err)
+	assert.True(t, dropped)
+}
+
+func TestScriptErrors(t *testing.T) {
+	_, err := LoadScript("empty.star", "x = 1")
+	assert.ErrorContains(t, err, "missing hook")
+
+	_, err = LoadScript("prio.star", "PRIORITY = -1\ndef hook(bk): pass")
+	assert.ErrorContains(t, err, "PRIORITY")
+
+	// no file system access
+	_, err = LoadScript("load.star", `load("other.star", "x")`+"\ndef hook(bk): pass")
+	assert.Error(t, err)
+
+	script, err := LoadScript("ro.star", `def hook(bk): bk.url = "x"`)
+	require.NoError(t, err)
+	bk := &gosuki.Bookmark{URL: "https://example.com", Title: "title"}
+	_, err = script.Run(bk, nil)
+	assert.ErrorContains(t, err, "can not be changed")
+
+	script, err = LoadScript("ret.star", `def hook(bk): return 1`)
+	require.NoError(t, err)
+	_, err = script.Run(bk, nil)
+	assert.ErrorContains(t, err, "list of tags")
+	assert.Equal(t, "title", bk.Title)
+}
+
+func TestScriptBudget(t *testing.T) {
+	script, err := LoadScript("loop.star", `
+def hook(bk):
+    for i in range(100000000):
+        pass
+`)
+	require.NoError(t, err)
+
+	steps := ScriptsConfig.MaxSteps
+	timeout := ScriptsConfig.Timeout
+	t.Cleanup(func() {
+		ScriptsConfig.MaxSteps = steps
+		ScriptsConfig.Timeout = timeout
+	})
+
+	start := time.Now()
+	_, err = script.Run(&gosuki.Bookmark{URL: "https://example.com"}, nil)
+	assert.ErrorContains(t, err, "too many steps")
+
+	ScriptsConfig.MaxSteps = 0
+	ScriptsConfig.Timeout = 20 * time.Millisecond
+	_, err = script.Run(&gosuki.Bookmark{URL: "https://example.com"}, nil)
+	assert.ErrorContains(t, err, "timeout")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestScriptHooks(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "yt.star"), []byte(testScript), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.star"), []byte("def hook("), 0o644))
+
+	prevDir := ScriptsConfig.Dir
+	ScriptsConfig.Dir = dir
+	t.Cleanup(func() {
+		ScriptsConfig.Dir = prevDir
+		scripts = nil
+	})
+
+	require.NoError(t, ReloadScripts())
+	scriptHooks := ScriptHooks()
+	require.Len(t, scriptHooks, 2)
+	assert.Equal(t, "node_script_yt", scriptHooks[0].Name())
+
+	assert.NotContains(t, Defined, "node_script_yt")
+
+	nodeHook := scriptHooks[0].(Hook[*tree.Node])
+	assert.Equal(t, uint(7), nodeHook.priority)
+
+	folder := &tree.Node{Title: "work", Type: tree.FolderNode}
+	node := &tree.Node{
+		URL:   "https://youtube.com/watch?v=1",
+		Title: "A talk - YouTube",
+		Type:  tree.URLNode,
+	}
+	tree.AddChild(folder, node)
+	require.NoError(t, nodeHook.Func(node))
+	assert.Equal(t, "A talk", node.Title)
+	assert.Equal(t, []string{"work", "video"}, node.Tags)
+
+	bkHook := scriptHooks[1].(Hook[*gosuki.Bookmark])
+	assert.ErrorIs(t, bkHook.Func(&gosuki.Bookmark{URL: "https://ads.example.com"}), ErrDrop)
+}
+
+func TestReloadScriptsWhileSettingUp(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "yt.star"), []byte(testScript), 0o644))
+
+	prevDir := ScriptsConfig.Dir
+	ScriptsConfig.Dir = dir
+	t.Cleanup(func() {
+		ScriptsConfig.Dir = prevDir
+		scripts = nil
+	})
+
+	defined := len(Defined)
+	done := make(chan bool)
+	go func() {
+		for range 20 {
+			assert.NoError(t, ReloadScripts())
+		}
+		done <- true
+	}()
+	for range 20 {
+		for _, hook := range ScriptHooks() {
+			_ = Defined[hook.Name()]
+		}
+	}
+	<-done
+
+	assert.Len(t, Defined, defined)
+	assert.Len(t, ScriptHooks(), 2)
+}
@@ -0,0 +1,423 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/bus"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/manager"
+)
+
+const (
+	WebhooksUnitID = "webhooks"
+
+	// Default header holding the HMAC-SHA256 signature of the payload
+	DefaultSignatureHeader = "X-Gosuki-Signature"
+
+	defaultWebhookTimeout = 10 * time.Second
+
+	// Interval at which the queue is checked for deliveries to retry
+	webhookPollInterval = 5 * time.Second
+
+	// Maximum number of deliveries sent on each check of the queue
+	webhookBatchSize = 16
+
+	maxWebhookRetryDelay = time.Hour
+)
+
+var webhookDeliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gosuki_webhook_deliveries_total",
+	Help: "Number of webhook delivery attempts by webhook and status.",
+}, []string{"webhook", "status"})
+
+// Webhook is an endpoint receiving a POST request when a bookmark matching
+// its filters is added, updated or deleted.
+//
+//	[[webhooks.endpoints]]
+//	name = "archiver"
+//	url = "https://example.com/hook"
+//	events = ["added"]
+//	tags = ["archive"]
+//	secret = "s3cr3t"
+//	template = '{"url": {{ json .Bookmark.URL }}}'
+type Webhook struct {
+	// Name used in the logs and the delivery queue. Defaults to the url.
+	Name string `toml:"name" mapstructure:"name"`
+
+	URL string `toml:"url" mapstructure:"url"`
+
+	// Event types sent to the endpoint: added, updated or deleted. All the
+	// events are sent when empty.
+	Events []string `toml:"events" mapstructure:"events"`
+
+	// Only send the bookmarks having one of these tags
+	Tags []string `toml:"tags" mapstructure:"tags"`
+
+	// Only send the bookmarks coming from one of these modules
+	Modules []string `toml:"modules" mapstructure:"modules"`
+
+	// Also send the bookmarks flagged as private
+	Private bool `toml:"private" mapstructure:"private"`
+
+	// Go text/template rendering the JSON payload. It receives a
+	// [WebhookEvent] and may use the `json` and `join` functions. The event
+	// is encoded as JSON when empty.
+	Template string `toml:"template" mapstructure:"template"`
+
+	// Key used to sign the payload with HMAC-SHA256. The signature is sent
+	// as `sha256=<hex>` in the signature header.
+	Secret string `toml:"secret" mapstructure:"secret"`
+
+	SignatureHeader string `toml:"signature-header" mapstructure:"signature-header"`
+
+	// Extra headers sent with the request
+	Headers map[string]string `toml:"headers" mapstructure:"headers"`
+
+	Timeout time.Duration `toml:"timeout" mapstructure:"timeout"`
+}
+
+func (w *Webhook) id() string {
+	if w.Name != "" {
+		return w.Name
+	}
+	return w.URL
+}
+
+// Match reports whether the event passes the filters of the webhook
+func (w *Webhook) Match(ev bus.BookmarkEvent) bool {
+	if ev.Bookmark == nil {
+		return false
+	}
+	if !w.Private && db.Flags(ev.Bookmark.Flags).Has(db.FlagPrivate) {
+		return false
+	}
+	if len(w.Events) > 0 && !slices.Contains(w.Events, string(ev.Type)) {
+		return false
+	}
+	if len(w.Modules) > 0 && !slices.Contains(w.Modules, ev.Bookmark.Module) {
+		return false
+	}
+	if len(w.Tags) > 0 && !slices.ContainsFunc(w.Tags, func(tag string) bool {
+		return slices.Contains(ev.Bookmark.Tags, tag)
+	}) {
+		return false
+	}
+	return true
+}
+
+// WebhookEvent is the data sent to webhooks
+type WebhookEvent struct {
+	Event    string           `json:"event"`
+	Time     int64            `json:"time"`
+	Bookmark *gosuki.Bookmark `json:"bookmark"`
+}
+
+var webhookFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join": strings.Join,
+}
+
+// Render returns the JSON payload of the event
+func (w *Webhook) Render(ev WebhookEvent) ([]byte, error) {
+	if w.Template == "" {
+		return json.Marshal(ev)
+	}
+
+	tmpl, err := template.New(w.id()).Funcs(webhookFuncs).Parse(w.Template)
+	if err != nil {
+		return nil, fmt.Errorf("webhook %s: %w", w.id(), err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, ev); err != nil {
+		return nil, fmt.Errorf("webhook %s: %w", w.id(), err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("webhook %s: template output is not valid JSON", w.id())
+	}
+	return buf.Bytes(), nil
+}
+
+// Sign returns the value of the signature header for payload
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send posts payload to the webhook endpoint
+func (w *Webhook) Send(ctx context.Context, event string, deliveryID uint64, payload []byte) error {
+	timeout := w.Timeout
+	if timeout == 0 {
+		timeout = defaultWebhookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gosuki-webhook")
+	req.Header.Set("X-Gosuki-Event", event)
+	req.Header.Set("X-Gosuki-Delivery", strconv.FormatUint(deliveryID, 10))
+	if w.Secret != "" {
+		header := w.SignatureHeader
+		if header == "" {
+			header = DefaultSignatureHeader
+		}
+		req.Header.Set(header, Sign(w.Secret, payload))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxRunOutput))
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("post %s: %s", w.URL, resp.Status)
+	}
+	return nil
+}
+
+type webhooksConfig struct {
+	// Number of retries of failed deliveries
+	Retries int `toml:"retries" mapstructure:"retries"`
+
+	// Delay before the first retry, doubled on each attempt up to one hour
+	RetryDelay time.Duration `toml:"retry-delay" mapstructure:"retry-delay"`
+
+	// Number of failed deliveries kept in the database after the retries
+	// are exhausted, 0 keeps all of them
+	History int `toml:"history" mapstructure:"history"`
+
+	Endpoints []Webhook `toml:"endpoints,omitempty" mapstructure:"endpoints"`
+}
+
+var WebhooksConfig = &webhooksConfig{
+	Retries:    5,
+	RetryDelay: 30 * time.Second,
+	History:    1000,
+	Endpoints:  []Webhook{},
+}
+
+func getWebhook(id string) (*Webhook, bool) {
+	for i := range WebhooksConfig.Endpoints {
+		if WebhooksConfig.Endpoints[i].id() == id {
+			return &WebhooksConfig.Endpoints[i], true
+		}
+	}
+	return nil, false
+}
+
+// retryDelay returns the delay before the next attempt of a delivery that
+// failed attempts times
+func retryDelay(attempts int) time.Duration {
+	delay := WebhooksConfig.RetryDelay
+	for i := 1; i < attempts && delay < maxWebhookRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxWebhookRetryDelay)
+}
+
+// WebhookDispatcher is the unit sending the bookmark events to the configured
+// webhooks. Events are queued in the database before being sent, deliveries
+// that fail are retried with an exponential backoff, including after a
+// restart of the daemon.
+type WebhookDispatcher struct {
+	// used by tests
+	now func() time.Time
+}
+
+func NewWebhookDispatcher() *WebhookDispatcher {
+	return &WebhookDispatcher{now: time.Now}
+}
+
+func (d *WebhookDispatcher) Run(m manager.UnitManager) {
+	events := bus.Bookmarks.Subscribe(bus.Options{
+		Name:   WebhooksUnitID,
+		Buffer: 1024,
+	})
+	defer events.Close()
+
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	ctx := context.Background()
+	d.deliver(ctx)
+	for {
+		select {
+		case ev := <-events.C:
+			if d.enqueue(ctx, ev) > 0 {
+				d.deliver(ctx)
+			}
+		case <-ticker.C:
+			d.deliver(ctx)
+		case <-m.ShouldStop():
+			m.Done()
+			return
+		}
+	}
+}
+
+// enqueue queues the payloads of the webhooks matching ev and returns the
+// number of queued deliveries
+func (d *WebhookDispatcher) enqueue(ctx context.Context, ev bus.BookmarkEvent) int {
+	now := d.now()
+	data := WebhookEvent{
+		Event:    string(ev.Type),
+		Time:     now.Unix(),
+		Bookmark: ev.Bookmark,
+	}
+
+	var queued int
+	for _, hook := range WebhooksConfig.Endpoints {
+		if !hook.Match(ev) {
+			continue
+		}
+
+		payload, err := hook.Render(data)
+		if err != nil {
+			log.Error("rendering webhook payload", "err", err)
+			continue
+		}
+
+		delivery := &db.WebhookDelivery{
+			Webhook:     hook.id(),
+			Event:       data.Event,
+			URL:         ev.Bookmark.URL,
+			Payload:     string(payload),
+			NextAttempt: now.Unix(),
+			Created:     now.Unix(),
+		}
+		if err := db.QueueWebhookDelivery(ctx, delivery); err != nil {
+			log.Error("queuing webhook", "webhook", hook.id(), "err", err)
+			continue
+		}
+		queued++
+	}
+	return queued
+}
+
+// deliver sends the deliveries that are due
+func (d *WebhookDispatcher) deliver(ctx context.Context) {
+	deliveries, err := db.DueWebhooks(ctx, d.now().Unix(), webhookBatchSize)
+	if err != nil {
+		log.Error("loading webhook queue", "err", err)
+		return
+	}
+
+	for _, delivery := range deliveries {
+		d.send(ctx, &delivery)
+	}
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, delivery *db.WebhookDelivery) {
+	hook, ok := getWebhook(delivery.Webhook)
+	if !ok {
+		err := errors.New("webhook is not configured")
+		d.fail(ctx, delivery, err, true)
+		return
+	}
+
+	err := hook.Send(ctx, delivery.Event, delivery.ID, []byte(delivery.Payload))
+	if err == nil {
+		webhookDeliveriesTotal.WithLabelValues(delivery.Webhook, "success").Inc()
+		if err := db.AckWebhookDelivery(ctx, delivery.ID); err != nil {
+			log.Error("removing webhook delivery", "err", err)
+		}
+		return
+	}
+
+	d.fail(ctx, delivery, err, delivery.Attempts >= WebhooksConfig.Retries)
+}
+
+// fail records a failed attempt of delivery, it is retried later unless
+// giveUp is true
+func (d *WebhookDispatcher) fail(ctx context.Context, delivery *db.WebhookDelivery, err error, giveUp bool) {
+	delivery.Attempts++
+	delivery.LastError = err.Error()
+
+	if giveUp {
+		delivery.Status = db.DeliveryFailed
+		webhookDeliveriesTotal.WithLabelValues(delivery.Webhook, "failed").Inc()
+		log.Error("webhook delivery failed",
+			"webhook", delivery.Webhook,
+			"url", delivery.URL,
+			"attempts", delivery.Attempts,
+			"err", err,
+		)
+	} else {
+		delay := retryDelay(delivery.Attempts)
+		delivery.NextAttempt = d.now().Add(delay).Unix()
+		webhookDeliveriesTotal.WithLabelValues(delivery.Webhook, "retry").Inc()
+		log.Warn("webhook delivery failed, retrying",
+			"webhook", delivery.Webhook,
+			"url", delivery.URL,
+			"attempt", delivery.Attempts,
+			"retry_in", delay,
+			"err", err,
+		)
+	}
+
+	if err := db.SaveWebhookDelivery(ctx, delivery, WebhooksConfig.History); err != nil {
+		log.Error("updating webhook delivery", "err", err)
+	}
+}
+
+func init() {
+	config.RegisterConfigurator("webhooks", config.AsConfigurator(WebhooksConfig))
+	config.RegisterResetHook(func() {
+		WebhooksConfig.Endpoints = []Webhook{}
+	})
+}
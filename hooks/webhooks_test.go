@@ -0,0 +1,336 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/bus"
+	"github.com/blob42/gosuki/pkg/config"
+)
+
+func TestMain(m *testing.M) {
+	db.RegisterSqliteHooks()
+	os.Exit(m.Run())
+}
+
+func TestWebhookMatch(t *testing.T) {
+	bk := &gosuki.Bookmark{URL: "https://a.com", Tags: []string{"go", "web"}, Module: "firefox"}
+	added := bus.BookmarkEvent{Type: bus.BookmarkAdded, Bookmark: bk}
+	deleted := bus.BookmarkEvent{Type: bus.BookmarkDeleted, Bookmark: bk}
+
+	assert.True(t, (&Webhook{}).Match(added))
+	assert.False(t, (&Webhook{}).Match(bus.BookmarkEvent{Type: bus.BookmarkAdded}))
+
+	hook := &Webhook{Events: []string{"added", "updated"}}
+	assert.True(t, hook.Match(added))
+	assert.False(t, hook.Match(deleted))
+
+	assert.True(t, (&Webhook{Tags: []string{"rust", "go"}}).Match(added))
+	assert.False(t, (&Webhook{Tags: []string{"rust"}}).Match(added))
+
+	assert.True(t, (&Webhook{Modules: []string{"firefox"}}).Match(added))
+	assert.False(t, (&Webhook{Modules: []string{"chrome"}}).Match(added))
+
+	private := &gosuki.Bookmark{URL: "https://b.com", Flags: int(db.FlagPrivate | db.FlagPinned)}
+	privateEv := bus.BookmarkEvent{Type: bus.BookmarkAdded, Bookmark: private}
+	assert.False(t, (&Webhook{}).Match(privateEv))
+	assert.True(t, (&Webhook{Private: true}).Match(privateEv))
+}
+
+func TestWebhookRender(t *testing.T) {
+	ev := WebhookEvent{
+		Event:    "added",
+		Time:     42,
+		Bookmark: &gosuki.Bookmark{URL: "https://a.com", Title: `say "hi"`, Tags: []string{"a", "b"}},
+	}
+
+	payload, err := (&Webhook{}).Render(ev)
+	require.NoError(t, err)
+	var got WebhookEvent
+	require.NoError(t, json.Unmarshal(payload, &got))
+	assert.Equal(t, ev.Event, got.Event)
+	assert.Equal(t, ev.Bookmark.URL, got.Bookmark.URL)
+
+	hook := &Webhook{
+		Template: `{"text": {{ json .Bookmark.Title }}, "tags": "{{ join .Bookmark.Tags "," }}", "event": "{{ .Event }}"}`,
+	}
+	payload, err = hook.Render(ev)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"text": "say \"hi\"", "tags": "a,b", "event": "added"}`, string(payload))
+
+	_, err = (&Webhook{Template: `{"text": {{ .Bookmark.Title }}}`}).Render(ev)
+	assert.ErrorContains(t, err, "not valid JSON")
+
+	_, err = (&Webhook{Template: `{{ .Missing`}).Render(ev)
+	assert.Error(t, err)
+}
+
+func TestWebhookSend(t *testing.T) {
+	payload := []byte(`{"event":"added"}`)
+
+	var req *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	hook := &Webhook{
+		URL:     srv.URL,
+		Secret:  "s3cr3t",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	}
+	require.NoError(t, hook.Send(context.Background(), "added", 7, payload))
+	assert.Equal(t, payload, body)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Equal(t, "added", req.Header.Get("X-Gosuki-Event"))
+	assert.Equal(t, "7", req.Header.Get("X-Gosuki-Delivery"))
+	assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+	assert.Equal(t, Sign("s3cr3t", payload), req.Header.Get(DefaultSignatureHeader))
+
+	hook.SignatureHeader = "X-Hub-Signature-256"
+	require.NoError(t, hook.Send(context.Background(), "added", 8, payload))
+	assert.Equal(t, Sign("s3cr3t", payload), req.Header.Get("X-Hub-Signature-256"))
+	assert.Empty(t, req.Header.Get(DefaultSignatureHeader))
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '{}' | openssl dgst -sha256 -hmac key
+	assert.Equal(t,
+		"sha256=a777724d943eb48dc69bca8a4a6d57a04db3f9ec7e1de4e581e860265bdf3032",
+		Sign("key", []byte(`{}`)))
+	assert.NotEqual(t, Sign("key", []byte(`{}`)), Sign("other", []byte(`{}`)))
+}
+
+func TestWebhooksConfig(t *testing.T) {
+	saved := *WebhooksConfig
+	t.Cleanup(func() { *WebhooksConfig = saved })
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	conf := `
+[webhooks]
+retries = 2
+retry-delay = "1m"
+
+[[webhooks.endpoints]]
+name = "archiver"
+url = "https://example.com/a"
+events = ["added"]
+tags = ["archive"]
+timeout = "3s"
+headers = { Authorization = "Bearer token" }
+
+[[webhooks.endpoints]]
+url = "https://example.com/b"
+`
+	require.NoError(t, os.WriteFile(path, []byte(conf), 0o600))
+	require.NoError(t, config.Reload(path))
+
+	assert.Equal(t, 2, WebhooksConfig.Retries)
+	assert.Equal(t, time.Minute, WebhooksConfig.RetryDelay)
+	require.Len(t, WebhooksConfig.Endpoints, 2)
+	hook, ok := getWebhook("archiver")
+	require.True(t, ok)
+	assert.Equal(t, []string{"added"}, hook.Events)
+	assert.Equal(t, 3*time.Second, hook.Timeout)
+	assert.Equal(t, "Bearer token", hook.Headers["Authorization"])
+	_, ok = getWebhook("https://example.com/b")
+	assert.True(t, ok)
+
+	// removed endpoints are dropped on reload
+	conf = `
+[[webhooks.endpoints]]
+url = "https://example.com/c"
+`
+	require.NoError(t, os.WriteFile(path, []byte(conf), 0o600))
+	require.NoError(t, config.Reload(path))
+	require.Len(t, WebhooksConfig.Endpoints, 1)
+	assert.Equal(t, "https://example.com/c", WebhooksConfig.Endpoints[0].URL)
+
+	// an invalid config is not applied at all
+	global := config.GlobalConfig
+	t.Cleanup(func() { config.GlobalConfig = global })
+	config.GlobalConfig.DisabledModules = []string{"github"}
+	conf = `
+disabled-modules = ["reddit"]
+
+[webhooks]
+retries = "many"
+
+[[webhooks.endpoints]]
+url = "https://example.com/d"
+`
+	require.NoError(t, os.WriteFile(path, []byte(conf), 0o600))
+	require.Error(t, config.Reload(path))
+	assert.Equal(t, []string{"github"}, config.GlobalConfig.DisabledModules)
+	require.Len(t, WebhooksConfig.Endpoints, 1)
+	assert.Equal(t, "https://example.com/c", WebhooksConfig.Endpoints[0].URL)
+}
+
+func TestRetryDelay(t *testing.T) {
+	saved := WebhooksConfig.RetryDelay
+	t.Cleanup(func() { WebhooksConfig.RetryDelay = saved })
+
+	WebhooksConfig.RetryDelay = time.Minute
+	assert.Equal(t, time.Minute, retryDelay(1))
+	assert.Equal(t, 2*time.Minute, retryDelay(2))
+	assert.Equal(t, 8*time.Minute, retryDelay(4))
+	assert.Equal(t, time.Hour, retryDelay(20))
+}
+
+func setupWebhookQueue(t *testing.T) {
+	l2, err := db.NewDB("test_webhooks", "", db.DBTypeInMemoryDSN).Init()
+	require.NoError(t, err)
+	require.NoError(t, l2.InitSchema(context.Background()))
+	db.L2Cache.DB = l2
+
+	saved := *WebhooksConfig
+	t.Cleanup(func() {
+		l2.Close()
+		db.L2Cache.DB = nil
+		*WebhooksConfig = saved
+	})
+}
+
+func TestWebhookDispatcher(t *testing.T) {
+	ctx := context.Background()
+	setupWebhookQueue(t)
+
+	var fail atomic.Bool
+	var received atomic.Int32
+	var lastBody atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		lastBody.Store(string(body))
+		received.Add(1)
+	}))
+	defer srv.Close()
+
+	WebhooksConfig.Retries = 1
+	WebhooksConfig.RetryDelay = time.Minute
+	WebhooksConfig.Endpoints = []Webhook{
+		{Name: "tagged", URL: srv.URL, Tags: []string{"hook"}},
+		{Name: "deleted", URL: srv.URL + "/deleted", Events: []string{"deleted"}},
+	}
+
+	now := time.Unix(1000, 0)
+	d := &WebhookDispatcher{now: func() time.Time { return now }}
+
+	// matching no webhook
+	assert.Zero(t, d.enqueue(ctx, bus.BookmarkEvent{
+		Type:     bus.BookmarkAdded,
+		Bookmark: &gosuki.Bookmark{URL: "https://a.com"},
+	}))
+
+	bk := &gosuki.Bookmark{URL: "https://b.com", Tags: []string{"hook"}}
+	assert.Equal(t, 1, d.enqueue(ctx, bus.BookmarkEvent{Type: bus.BookmarkAdded, Bookmark: bk}))
+	d.deliver(ctx)
+	assert.EqualValues(t, 1, received.Load())
+	var got WebhookEvent
+	require.NoError(t, json.Unmarshal([]byte(lastBody.Load().(string)), &got))
+	assert.Equal(t, "added", got.Event)
+	assert.Equal(t, bk.URL, got.Bookmark.URL)
+
+	queue, err := db.L2Cache.WebhookDeliveries(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, queue, "sent deliveries are removed")
+
+	// failed deliveries stay in the queue and are retried later
+	fail.Store(true)
+	assert.Equal(t, 2, d.enqueue(ctx, bus.BookmarkEvent{Type: bus.BookmarkDeleted, Bookmark: bk}))
+	d.deliver(ctx)
+	queue, err = db.L2Cache.WebhookDeliveries(ctx, db.DeliveryPending)
+	require.NoError(t, err)
+	require.Len(t, queue, 2)
+	for _, q := range queue {
+		assert.Equal(t, 1, q.Attempts)
+		assert.Equal(t, now.Add(time.Minute).Unix(), q.NextAttempt)
+		assert.Contains(t, q.LastError, "503")
+	}
+
+	// not due yet
+	d.deliver(ctx)
+	queue, _ = db.L2Cache.WebhookDeliveries(ctx, db.DeliveryPending)
+	assert.Equal(t, 1, queue[0].Attempts)
+
+	// the delivery to "tagged" succeeds, the one to the removed webhook fails
+	fail.Store(false)
+	WebhooksConfig.Endpoints = WebhooksConfig.Endpoints[:1]
+	now = now.Add(time.Minute)
+	d.deliver(ctx)
+	assert.EqualValues(t, 2, received.Load())
+
+	queue, err = db.L2Cache.WebhookDeliveries(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	assert.Equal(t, "deleted", queue[0].Webhook)
+	assert.Equal(t, db.DeliveryFailed, queue[0].Status)
+	assert.Equal(t, "webhook is not configured", queue[0].LastError)
+}
+
+func TestWebhookGiveUp(t *testing.T) {
+	ctx := context.Background()
+	setupWebhookQueue(t)
+
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	WebhooksConfig.Retries = 2
+	WebhooksConfig.RetryDelay = time.Second
+	WebhooksConfig.Endpoints = []Webhook{{URL: srv.URL}}
+
+	now := time.Unix(1000, 0)
+	d := &WebhookDispatcher{now: func() time.Time { return now }}
+	d.enqueue(ctx, bus.BookmarkEvent{
+		Type:     bus.BookmarkUpdated,
+		Bookmark: &gosuki.Bookmark{URL: "https://a.com"},
+	})
+
+	for range 5 {
+		d.deliver(ctx)
+		now = now.Add(time.Hour)
+	}
+	assert.EqualValues(t, 3, attempts.Load(), "first attempt and two retries")
+
+	failed, err := db.L2Cache.WebhookDeliveries(ctx, db.DeliveryFailed)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, 3, failed[0].Attempts)
+
+	// only the last history failed deliveries are kept
+	WebhooksConfig.History = 1
+	d.enqueue(ctx, bus.BookmarkEvent{
+		Type:     bus.BookmarkUpdated,
+		Bookmark: &gosuki.Bookmark{URL: "https://b.com"},
+	})
+	for range 5 {
+		d.deliver(ctx)
+		now = now.Add(time.Hour)
+	}
+	failed, err = db.L2Cache.WebhookDeliveries(ctx, db.DeliveryFailed)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, "https://b.com", failed[0].URL)
+}
@@ -22,6 +22,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -112,6 +113,78 @@ func GetAPIBookmarks(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// GetAPIReadLater lists the unread bookmarks of the read later queue, or the
+// read ones with `?read=on`
+func GetAPIReadLater(w http.ResponseWriter, r *http.Request) {
+	pageParams := GetPaginationParams(r)
+	res, err := db.ReadLater(r.Context(), r.URL.Query().Get("read") == "on", pageParams)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	db.LoadSources(r.Context(), res.Bookmarks)
+
+	payload := Payload{
+		Total:   res.Total,
+		Page:    pageParams.Page,
+		PerPage: pageParams.Size,
+		Result:  res.Bookmarks,
+	}
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// FlagsRequest is the body of the flags endpoint
+type FlagsRequest struct {
+	URL   string   `json:"url"`
+	Set   []string `json:"set"`
+	Clear []string `json:"clear"`
+}
+
+// PostFlags sets and clears flags of a bookmark and replies with the updated
+// bookmark.
+func PostFlags(w http.ResponseWriter, r *http.Request) {
+	var req FlagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bk, err := SetFlags(req.URL, req.Set, req.Clear)
+	if errors.Is(err, db.ErrBookmarkNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(bk); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// SetFlags sets and clears the named flags of the bookmark with the given url
+// in the daemon caches
+func SetFlags(url string, set, clear []string) (*gosuki.Bookmark, error) {
+	if url == "" {
+		return nil, errors.New("missing url")
+	}
+
+	setFlags, err := gosuki.ParseFlags(set)
+	if err != nil {
+		return nil, err
+	}
+	clearFlags, err := gosuki.ParseFlags(clear)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.SetCachedFlags(url, setFlags, clearFlags)
+}
+
 func GetBookmarks(r *http.Request) ([]*gosuki.Bookmark, uint, error) {
 	var qResult *db.QueryResult
 	var err error
@@ -141,6 +214,8 @@ func GetBookmarks(r *http.Request) ([]*gosuki.Bookmark, uint, error) {
 		qResult, err = db.BookmarksByTag(r.Context(), tag, pageParams)
 	} else if query != "" {
 		qResult, err = db.QueryBookmarks(r.Context(), query, IsFuzzy(r), pageParams)
+	} else if urlQuery.Has("archived") {
+		qResult, err = db.ArchivedBookmarks(r.Context(), pageParams)
 	} else {
 		qResult, err = db.ListBookmarks(r.Context(), pageParams)
 	}
@@ -148,6 +223,7 @@ func GetBookmarks(r *http.Request) ([]*gosuki.Bookmark, uint, error) {
 	if err != nil {
 		return nil, 0, fmt.Errorf("database query failed: %w", err)
 	}
+	db.LoadSources(r.Context(), qResult.Bookmarks)
 
 	return qResult.Bookmarks, qResult.Total, nil
 }
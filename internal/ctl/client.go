@@ -0,0 +1,149 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package ctl
+
+import (
+	"errors"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+const dialTimeout = time.Second
+
+// Client is a client for the control socket of a running daemon
+type Client struct {
+	rpc *rpc.Client
+}
+
+// Dial connects to the daemon control socket at path. It returns
+// [ErrNotRunning] if no daemon is listening.
+func Dial(path string) (*Client, error) {
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, ErrNotRunning
+		}
+		return nil, err
+	}
+
+	return &Client{jsonrpc.NewClient(conn)}, nil
+}
+
+func (c *Client) call(method string, args any, reply any) error {
+	return c.rpc.Call(ServiceName+"."+method, args, reply)
+}
+
+func (c *Client) Close() error {
+	return c.rpc.Close()
+}
+
+func (c *Client) Query(args QueryArgs) ([]*gosuki.Bookmark, error) {
+	var reply []*gosuki.Bookmark
+	err := c.call("Query", args, &reply)
+	return reply, err
+}
+
+func (c *Client) Get(id uint64) (*gosuki.Bookmark, error) {
+	reply := &gosuki.Bookmark{}
+	if err := c.call("Get", id, reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// Lookup returns the bookmark saved at url or nil if there is none
+func (c *Client) Lookup(url string) (*gosuki.Bookmark, error) {
+	reply := &gosuki.Bookmark{}
+	if err := c.call("Lookup", url, reply); err != nil {
+		return nil, err
+	}
+	if reply.URL == "" {
+		return nil, nil
+	}
+	return reply, nil
+}
+
+func (c *Client) SuggestTags(args SuggestArgs) ([]db.TagCount, error) {
+	var reply []db.TagCount
+	err := c.call("SuggestTags", args, &reply)
+	return reply, err
+}
+
+func (c *Client) Set(bk *gosuki.Bookmark) error {
+	return c.call("Set", bk, &Empty{})
+}
+
+func (c *Client) Flag(url string, set, clear db.Flags) (*gosuki.Bookmark, error) {
+	reply := &gosuki.Bookmark{}
+	if err := c.call("Flag", FlagArgs{url, set, clear}, reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *Client) Dedupe(merge bool) ([]db.Duplicates, error) {
+	var reply []db.Duplicates
+	err := c.call("Dedupe", merge, &reply)
+	return reply, err
+}
+
+func (c *Client) Remove(url string) error {
+	return c.call("Remove", url, &Empty{})
+}
+
+func (c *Client) Sync() error {
+	return c.call("Sync", Empty{}, &Empty{})
+}
+
+func (c *Client) ReloadConfig() error {
+	return c.call("ReloadConfig", Empty{}, &Empty{})
+}
+
+func (c *Client) Status() (*Status, error) {
+	reply := &Status{}
+	if err := c.call("Status", Empty{}, reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *Client) StopUnit(name string) error {
+	return c.call("StopUnit", name, &Empty{})
+}
+
+func (c *Client) StartUnit(name string) error {
+	return c.call("StartUnit", name, &Empty{})
+}
+
+func (c *Client) MarktabRuns(filter db.RunFilter) ([]db.MarktabRun, error) {
+	var reply []db.MarktabRun
+	err := c.call("MarktabRuns", filter, &reply)
+	return reply, err
+}
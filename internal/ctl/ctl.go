@@ -0,0 +1,108 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+// Package ctl implements the control socket of the gosuki daemon.
+//
+// When `gosuki start` is running, it listens on a unix domain socket next to
+// the database file and serves a small JSON-RPC API. CLI commands use it to
+// query and edit the bookmarks held in the daemon caches, which are more
+// recent than the on-disk database. When the daemon is not running, commands
+// fall back to direct database access.
+package ctl
+
+import (
+	"errors"
+	"strings"
+
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/logging"
+	"github.com/blob42/gosuki/pkg/manager"
+)
+
+const (
+	// ServiceName is the name under which the RPC service is registered
+	ServiceName = "Gosuki"
+
+	socketExt = ".sock"
+)
+
+var (
+	log = logging.GetLogger("ctl")
+
+	ErrNotRunning = errors.New("gosuki daemon is not running")
+)
+
+// SocketPath returns the path of the control socket for the database at
+// dbpath.
+func SocketPath(dbpath string) string {
+	return strings.TrimSuffix(dbpath, ".db") + socketExt
+}
+
+// QueryArgs are the arguments of the Query method. An empty query and tag
+// lists all bookmarks but the archived ones.
+type QueryArgs struct {
+	Query string
+	Tag   string
+	Fuzzy bool
+
+	// list the unread bookmarks of the read later queue
+	Unread bool
+
+	// list the archived bookmarks
+	Archived bool
+
+	// list all the bookmarks including the archived ones
+	All bool
+}
+
+// SuggestArgs are the arguments of the SuggestTags method
+type SuggestArgs struct {
+	Prefix string
+	URL    string
+	Limit  int
+}
+
+// FlagArgs are the arguments of the Flag method
+type FlagArgs struct {
+	URL   string
+	Set   db.Flags
+	Clear db.Flags
+}
+
+// ModuleStatus describes a registered module
+type ModuleStatus struct {
+	ID      string
+	Browser bool
+	Enabled bool
+}
+
+// Status is the reply of the Status method
+type Status struct {
+	Version string
+	Modules []ModuleStatus
+
+	// units run by the manager
+	Units []manager.UnitStatus
+}
+
+// Empty is used for methods without arguments or reply
+type Empty struct{}
@@ -0,0 +1,139 @@
+package ctl
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki/pkg/manager"
+)
+
+type testUnitManager struct {
+	stop chan bool
+	done chan bool
+}
+
+func newTestUnitManager() *testUnitManager {
+	return &testUnitManager{
+		stop: make(chan bool, 1),
+		done: make(chan bool, 1),
+	}
+}
+
+func (t *testUnitManager) ShouldStop() <-chan bool { return t.stop }
+func (t *testUnitManager) Done()                   { t.done <- true }
+func (t *testUnitManager) Panic(v any)             { panic(v) }
+func (t *testUnitManager) RequestShutdown()        {}
+
+func startServer(t *testing.T, path string) *testUnitManager {
+	return startServerWith(t, path, nil)
+}
+
+func startServerWith(t *testing.T, path string, mngr *manager.Manager) *testUnitManager {
+	srv, err := NewServer(path, mngr)
+	require.NoError(t, err)
+
+	um := newTestUnitManager()
+	go srv.Run(um)
+	t.Cleanup(func() {
+		um.stop <- true
+		<-um.done
+	})
+
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	return um
+}
+
+func TestSocketPath(t *testing.T) {
+	assert.Equal(t, "/data/gosuki.sock", SocketPath("/data/gosuki.db"))
+	assert.Equal(t, "/data/bookmarks.sock", SocketPath("/data/bookmarks"))
+}
+
+func TestDialNotRunning(t *testing.T) {
+	_, err := Dial(filepath.Join(t.TempDir(), "gosuki.sock"))
+	assert.ErrorIs(t, err, ErrNotRunning)
+}
+
+func TestStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gosuki.sock")
+	startServer(t, path)
+
+	client, err := Dial(path)
+	require.NoError(t, err)
+	defer client.Close()
+
+	status, err := client.Status()
+	require.NoError(t, err)
+	assert.NotEmpty(t, status.Version)
+}
+
+type idleUnit struct{}
+
+func (idleUnit) Run(um manager.UnitManager) {
+	<-um.ShouldStop()
+	um.Done()
+}
+
+func unitState(t *testing.T, client *Client, name string) manager.UnitState {
+	t.Helper()
+	status, err := client.Status()
+	require.NoError(t, err)
+	for _, unit := range status.Units {
+		if unit.Name == name {
+			return unit.State
+		}
+	}
+	t.Fatalf("unit %s not found", name)
+	return ""
+}
+
+func TestStopStartUnit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gosuki.sock")
+	mngr := manager.NewManager()
+	mngr.AddUnit(idleUnit{}, "idle")
+	startServerWith(t, path, mngr)
+
+	client, err := Dial(path)
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Equal(t, manager.UnitRunning, unitState(t, client, "idle"))
+
+	require.NoError(t, client.StopUnit("idle"))
+	assert.Equal(t, manager.UnitStopped, unitState(t, client, "idle"))
+
+	require.NoError(t, client.StartUnit("idle"))
+	assert.Equal(t, manager.UnitRunning, unitState(t, client, "idle"))
+
+	err = client.StopUnit("missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unit not found")
+}
+
+func TestStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gosuki.sock")
+
+	// leave a socket file without a listener
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+
+	_, err = Dial(path)
+	require.ErrorIs(t, err, ErrNotRunning)
+
+	startServer(t, path)
+
+	client, err := Dial(path)
+	require.NoError(t, err)
+	client.Close()
+}
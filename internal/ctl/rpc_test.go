@@ -0,0 +1,179 @@
+package ctl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+func TestMain(m *testing.M) {
+	db.RegisterSqliteHooks()
+	os.Exit(m.Run())
+}
+
+func setupCaches(t *testing.T) {
+	db.Clock = &db.LamportClock{}
+	cache, err := db.NewDB("test_ctl", "", db.DBTypeInMemoryDSN).Init()
+	require.NoError(t, err)
+	l2, err := db.NewDB("test_ctl_l2", "", db.DBTypeInMemoryDSN).Init()
+	require.NoError(t, err)
+	for _, c := range []*db.DB{cache, l2} {
+		require.NoError(t, c.InitSchema(context.Background()))
+	}
+	db.Cache.DB, db.L2Cache.DB = cache, l2
+
+	t.Cleanup(func() {
+		cache.Close()
+		l2.Close()
+		db.Cache.DB, db.L2Cache.DB = nil, nil
+	})
+}
+
+func dialServer(t *testing.T) *Client {
+	path := filepath.Join(t.TempDir(), "gosuki.sock")
+	startServer(t, path)
+
+	client, err := Dial(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestSetQueryRemove(t *testing.T) {
+	setupCaches(t)
+	client := dialServer(t)
+
+	require.NoError(t, client.Set(&gosuki.Bookmark{
+		URL:   "https://example.com",
+		Title: "example",
+		Tags:  []string{"foo", "bar"},
+		Desc:  "first line\nsecond line",
+	}))
+	require.NoError(t, client.Set(&gosuki.Bookmark{
+		URL:   "https://other.org",
+		Title: "other",
+		Tags:  []string{"baz"},
+	}))
+	assert.Error(t, client.Set(&gosuki.Bookmark{Title: "no url"}))
+
+	bks, err := client.Query(QueryArgs{})
+	require.NoError(t, err)
+	assert.Len(t, bks, 2)
+
+	bks, err = client.Query(QueryArgs{Tag: "foo"})
+	require.NoError(t, err)
+	require.Len(t, bks, 1)
+	bk := bks[0]
+	assert.Equal(t, "https://example.com", bk.URL)
+	assert.Equal(t, "example", bk.Title)
+	assert.ElementsMatch(t, []string{"foo", "bar"}, bk.Tags)
+	assert.Equal(t, "first line\nsecond line", bk.Desc)
+
+	got, err := client.Get(bk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, bk.URL, got.URL)
+
+	// replacing a bookmark is visible to the next query
+	require.NoError(t, client.Set(&gosuki.Bookmark{
+		URL:   "https://example.com",
+		Title: "renamed",
+		Tags:  []string{"foo"},
+	}))
+	bks, err = client.Query(QueryArgs{Query: "renamed"})
+	require.NoError(t, err)
+	require.Len(t, bks, 1)
+	assert.Equal(t, "https://example.com", bks[0].URL)
+
+	require.NoError(t, client.Remove("https://example.com"))
+	bks, err = client.Query(QueryArgs{})
+	require.NoError(t, err)
+	require.Len(t, bks, 1)
+	assert.Equal(t, "https://other.org", bks[0].URL)
+}
+
+func TestFlag(t *testing.T) {
+	setupCaches(t)
+	client := dialServer(t)
+
+	require.NoError(t, client.Set(&gosuki.Bookmark{URL: "https://example.com", Title: "example"}))
+
+	bk, err := client.Flag("https://example.com", db.FlagReadLater|db.FlagPrivate, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int(db.FlagReadLater|db.FlagPrivate), bk.Flags)
+
+	unread, err := client.Query(QueryArgs{Unread: true})
+	require.NoError(t, err)
+	require.Len(t, unread, 1)
+	assert.Equal(t, "https://example.com", unread[0].URL)
+
+	bk, err = client.Flag("https://example.com", 0, db.FlagReadLater)
+	require.NoError(t, err)
+	assert.Equal(t, int(db.FlagPrivate), bk.Flags)
+
+	unread, err = client.Query(QueryArgs{Unread: true})
+	require.NoError(t, err)
+	assert.Empty(t, unread)
+
+	_, err = client.Flag("https://missing.com", db.FlagPinned, 0)
+	assert.Error(t, err)
+}
+
+func TestQueryArgs(t *testing.T) {
+	setupCaches(t)
+	client := dialServer(t)
+
+	for _, bk := range []*gosuki.Bookmark{
+		{URL: "https://go.dev", Title: "go", Tags: []string{"dev"}},
+		{URL: "https://lwn.net", Title: "lwn", Tags: []string{"news"}},
+		{URL: "https://old.dev", Title: "old go", Tags: []string{"dev"}},
+	} {
+		require.NoError(t, client.Set(bk))
+	}
+	_, err := client.Flag("https://lwn.net", db.FlagReadLater, 0)
+	require.NoError(t, err)
+	_, err = client.Flag("https://old.dev", db.FlagArchived, 0)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		args QueryArgs
+		want []string
+	}{
+		{"list", QueryArgs{}, []string{"https://go.dev", "https://lwn.net"}},
+		{"all", QueryArgs{All: true}, []string{"https://go.dev", "https://lwn.net", "https://old.dev"}},
+		{"unread", QueryArgs{Unread: true}, []string{"https://lwn.net"}},
+		{"archived", QueryArgs{Archived: true}, []string{"https://old.dev"}},
+		{"tag", QueryArgs{Tag: "dev"}, []string{"https://go.dev", "https://old.dev"}},
+		{"query and tag", QueryArgs{Query: "old", Tag: "dev"}, []string{"https://old.dev"}},
+		{"query", QueryArgs{Query: "lwn"}, []string{"https://lwn.net"}},
+	}
+
+	urls := func(bks []*gosuki.Bookmark) []string {
+		var res []string
+		for _, bk := range bks {
+			res = append(res, bk.URL)
+		}
+		return res
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bks, err := client.Query(tt.args)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, urls(bks))
+
+			// the clients run the same query on the on-disk db without
+			// the daemon
+			bks, err = QueryDB(context.Background(), db.L2Cache.DB, tt.args)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, urls(bks))
+		})
+	}
+}
@@ -0,0 +1,324 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package ctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"time"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/build"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/manager"
+	"github.com/blob42/gosuki/pkg/modules"
+)
+
+// Service is the RPC service exposed on the control socket
+type Service struct {
+	mngr *manager.Manager
+}
+
+var allBookmarks = &db.PaginationParams{Page: 1, Size: -1}
+
+// Query searches the bookmarks in the daemon cache
+func (s *Service) Query(args QueryArgs, reply *[]*gosuki.Bookmark) error {
+	// the L2 cache uses the same bookmark ids as the on-disk db
+	db.SyncCachesForRead()
+
+	res, err := QueryDB(context.Background(), db.L2Cache.DB, args)
+	if err != nil {
+		return err
+	}
+
+	*reply = res
+	return nil
+}
+
+// QueryDB runs the query described by args on d. It is used by the daemon on
+// its cache and by the clients on the on-disk db when the daemon is not
+// running.
+func QueryDB(ctx context.Context, d *db.DB, args QueryArgs) ([]*gosuki.Bookmark, error) {
+	var res *db.QueryResult
+	var err error
+
+	switch {
+	case args.Unread:
+		res, err = d.ReadLater(ctx, false, allBookmarks)
+	case args.Archived:
+		res, err = d.ArchivedBookmarks(ctx, allBookmarks)
+	case args.All:
+		res, err = d.AllBookmarks(ctx, allBookmarks)
+	case args.Query != "" && args.Tag != "":
+		res, err = d.QueryBookmarksByTag(ctx, args.Query, args.Tag, args.Fuzzy, allBookmarks)
+	case args.Tag != "":
+		res, err = d.BookmarksByTag(ctx, args.Tag, allBookmarks)
+	case args.Query != "":
+		res, err = d.QueryBookmarks(ctx, args.Query, args.Fuzzy, allBookmarks)
+	default:
+		res, err = d.ListBookmarks(ctx, allBookmarks)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Bookmarks, nil
+}
+
+// Get returns the bookmark with the given id
+func (s *Service) Get(id uint64, reply *gosuki.Bookmark) error {
+	db.SyncCachesForRead()
+	bk, err := db.L2Cache.DB.BookmarkByID(context.Background(), id)
+	if err != nil {
+		return err
+	}
+
+	*reply = *bk
+	return nil
+}
+
+// Lookup returns the bookmark saved at url or at an url with the same
+// canonical form. The reply is empty if there is no such bookmark.
+func (s *Service) Lookup(url string, reply *gosuki.Bookmark) error {
+	bk, err := db.LookupCachedBookmark(context.Background(), url)
+	if errors.Is(err, db.ErrBookmarkNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	*reply = *bk
+	return nil
+}
+
+// SuggestTags returns the tags starting with a prefix, see
+// [db.DB.SuggestTags]
+func (s *Service) SuggestTags(args SuggestArgs, reply *[]db.TagCount) error {
+	tags, err := db.SuggestCachedTags(context.Background(), args.Prefix, args.URL, args.Limit)
+	if err != nil {
+		return err
+	}
+
+	*reply = tags
+	return nil
+}
+
+// Set adds or replaces a bookmark
+func (s *Service) Set(bk gosuki.Bookmark, _ *Empty) error {
+	if bk.URL == "" {
+		return errors.New("empty url")
+	}
+	return db.SetCachedBookmark(&bk)
+}
+
+// Flag sets and clears flags of the bookmark with the given url and returns
+// the updated bookmark
+func (s *Service) Flag(args FlagArgs, reply *gosuki.Bookmark) error {
+	bk, err := db.SetCachedFlags(args.URL, args.Set, args.Clear)
+	if err != nil {
+		return err
+	}
+
+	*reply = *bk
+	return nil
+}
+
+// Dedupe returns the duplicate bookmarks and merges them if merge is true
+func (s *Service) Dedupe(merge bool, reply *[]db.Duplicates) error {
+	dups, err := db.FindCachedDuplicates(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if merge {
+		if err = db.MergeCachedDuplicates(dups); err != nil {
+			return err
+		}
+	}
+
+	*reply = dups
+	return nil
+}
+
+// Remove deletes the bookmark with the given url
+func (s *Service) Remove(url string, _ *Empty) error {
+	return db.RemoveCachedBookmark(url)
+}
+
+// Sync writes the daemon cache to disk immediately
+func (s *Service) Sync(_ Empty, _ *Empty) error {
+	return db.FlushToDisk()
+}
+
+var reloadHandler func() error
+
+// OnReload sets the function called to reload the daemon config
+func OnReload(fn func() error) {
+	reloadHandler = fn
+}
+
+// ReloadConfig reloads the config file
+func (s *Service) ReloadConfig(_ Empty, _ *Empty) error {
+	if reloadHandler != nil {
+		return reloadHandler()
+	}
+
+	log.Info("reloading config", "path", config.ConfigFileFlag)
+	return config.Reload(config.ConfigFileFlag)
+}
+
+// Status returns the status of the daemon and its modules
+func (s *Service) Status(_ Empty, reply *Status) error {
+	reply.Version = build.Version()
+
+	for _, mod := range modules.GetAllModules() {
+		id := mod.ModInfo().ID
+		_, isBrowser := mod.(modules.BrowserModule)
+		reply.Modules = append(reply.Modules, ModuleStatus{
+			ID:      string(id),
+			Browser: isBrowser,
+			Enabled: !modules.Disabled(id),
+		})
+	}
+
+	if s.mngr != nil {
+		reply.Units = s.mngr.Status()
+	}
+
+	return nil
+}
+
+// StopUnit stops the units with the given name. They can be started again
+// with StartUnit.
+func (s *Service) StopUnit(name string, _ *Empty) error {
+	if s.mngr == nil {
+		return errors.New("no unit manager")
+	}
+	return s.mngr.StopUnit(name)
+}
+
+// StartUnit starts the stopped or failed units with the given name
+func (s *Service) StartUnit(name string, _ *Empty) error {
+	if s.mngr == nil {
+		return errors.New("no unit manager")
+	}
+	return s.mngr.StartUnit(name)
+}
+
+// MarktabRuns returns the history of the marktab actions
+func (s *Service) MarktabRuns(args db.RunFilter, reply *[]db.MarktabRun) error {
+	if db.L2Cache.DB == nil {
+		return errors.New("cache is not initialized")
+	}
+
+	runs, err := db.L2Cache.MarktabRuns(context.Background(), args)
+	if err != nil {
+		return err
+	}
+
+	*reply = runs
+	return nil
+}
+
+// Server serves the control socket. It implements [manager.WorkUnit].
+type Server struct {
+	path string
+	rpc  *rpc.Server
+}
+
+func NewServer(path string, mngr *manager.Manager) (*Server, error) {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName(ServiceName, &Service{mngr}); err != nil {
+		return nil, err
+	}
+
+	return &Server{path: path, rpc: srv}, nil
+}
+
+// listen creates the unix socket, removing any stale socket left by a daemon
+// that did not exit cleanly.
+func (s *Server) listen() (net.Listener, error) {
+	if _, err := os.Stat(s.path); err == nil {
+		conn, err := net.DialTimeout("unix", s.path, time.Second)
+		if err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("another daemon is listening on %s", s.path)
+		}
+
+		log.Debug("removing stale socket", "path", s.path)
+		if err = os.Remove(s.path); err != nil {
+			return nil, err
+		}
+	}
+
+	l, err := net.Listen("unix", s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.Chmod(s.path, 0600); err != nil {
+		l.Close()
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func (s *Server) Run(m manager.UnitManager) {
+	l, err := s.listen()
+	if err != nil {
+		// the daemon can run without the control socket, cli commands fall
+		// back to direct db access
+		log.Error("control socket disabled", "err", err)
+		<-m.ShouldStop()
+		m.Done()
+		return
+	}
+	log.Debug("listening", "socket", s.path)
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					log.Error("accept", "err", err)
+				}
+				return
+			}
+			go s.rpc.ServeCodec(jsonrpc.NewServerCodec(conn))
+		}
+	}()
+
+	<-m.ShouldStop()
+	l.Close()
+	os.Remove(s.path)
+	m.Done()
+}
+
+var _ manager.WorkUnit = (*Server)(nil)
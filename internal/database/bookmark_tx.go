@@ -64,9 +64,11 @@ func (db *DB) UpsertBookmark(bk *Bookmark) error {
 				desc,
 				flags,
 				module,
-				xhsum
+				xhsum,
+				canonical,
+				created_at
 			)
-			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 	)
 	if err != nil {
 		log.Errorf("%s: %s", err, bk.URL)
@@ -81,6 +83,8 @@ func (db *DB) UpsertBookmark(bk *Bookmark) error {
 			metadata = CASE WHEN ? != '' THEN ? ELSE metadata END,
 			desc = CASE WHEN ? != '' THEN ? ELSE desc END,
 			tags=?,
+			flags = flags | ?,
+			created_at = CASE WHEN ? > 0 AND ? < created_at THEN ? ELSE created_at END,
 			modified=strftime('%s'),
 			xhsum=?
 		WHERE url=?`,
@@ -109,6 +113,10 @@ func (db *DB) UpsertBookmark(bk *Bookmark) error {
 	// sanitize urls
 	bk.URL = html.UnescapeString(bk.URL)
 
+	// merge into an existing bookmark with the same canonical url
+	canonical := CanonicalURL(bk.URL)
+	bk.URL = canonicalTarget(tx, bk.URL, canonical)
+
 	// unescape unicode
 	bk.Title = utils.DecodeUnicodeEscapes(bk.Title)
 	bk.Desc = utils.DecodeUnicodeEscapes(bk.Desc)
@@ -129,11 +137,13 @@ func (db *DB) UpsertBookmark(bk *Bookmark) error {
 		bk.Title, //metadata
 		tagListText,
 		bk.Desc,
-		0,         // flags
+		bk.Flags,
 		bk.Module, // source module that created this mark
 
 		// empty xhash: it will be calculated in the cache
 		"",
+		canonical,
+		bk.Created,
 	)
 
 	if err != nil {
@@ -149,9 +159,15 @@ func (db *DB) UpsertBookmark(bk *Bookmark) error {
 		return err
 	}
 
+	if err = addSource(tx, bk.URL, bk.Module, bk.Created, 0); err != nil {
+		log.Errorf("%s: %s", err, bk.URL)
+		tx.Rollback()
+		return err
+	}
+
 	// We will handle ErrConstraint: only against URL for now. UPDATE the
 	// bookmark instead IF xhash(url+metadata+tags+desc) changed
-	if err != nil && sqlite3Err.Code == sqlite3.ErrConstraint {
+	if isSqlite3Err && sqlite3Err.Code == sqlite3.ErrConstraint {
 		log.Tracef("Updating bookmark %s", bk.URL)
 
 		// Get existing xhashsum of bookmark
@@ -165,7 +181,7 @@ func (db *DB) UpsertBookmark(bk *Bookmark) error {
 		// We will only update the bookmark if the xhsum changed
 		if targetXHSum == xhsum(bk.URL, bk.Title, tagListText, bk.Desc) {
 			log.Trace("upsert: same hash skipping", "url", bk.URL)
-			return tx.Rollback()
+			return tx.Commit()
 		}
 
 		/////
@@ -204,6 +220,14 @@ func (db *DB) UpsertBookmark(bk *Bookmark) error {
 			bk.Desc,
 			tagListText,
 
+			// flags are only added, see [Flags]
+			bk.Flags,
+
+			// the oldest creation time is kept
+			bk.Created,
+			bk.Created,
+			bk.Created,
+
 			// xhsum calculated in cache
 			"",
 
@@ -80,6 +80,9 @@ func SyncURLIndexToBuffer(urls []string, index Index, buffer *DB) {
 			break
 		}
 		node := iNode.(*Node)
+		if node.Dropped {
+			continue
+		}
 		bk := node.GetBookmark()
 		err := buffer.UpsertBookmark(bk)
 		if err != nil {
@@ -90,7 +93,7 @@ func SyncURLIndexToBuffer(urls []string, index Index, buffer *DB) {
 }
 
 func SyncTreeToBuffer(node *Node, buffer *DB) {
-	if node.Type == tree.URLNode {
+	if node.Type == tree.URLNode && !node.Dropped {
 		bk := node.GetBookmark()
 		err := buffer.UpsertBookmark(bk)
 		if err != nil {
@@ -0,0 +1,221 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/blob42/gosuki/pkg/config"
+)
+
+// Bookmarks are compared by their canonical url, stored in the `canonical`
+// column. A new bookmark with the same canonical url as an existing one is
+// merged into it instead of being added. The url of the bookmark is never
+// changed.
+
+type urlsConfig struct {
+	// Compare the bookmarks by their canonical url. When disabled, only
+	// identical urls are duplicates.
+	Canonicalize bool `toml:"canonicalize" mapstructure:"canonicalize"`
+
+	// http and https urls are the same page
+	IgnoreScheme bool `toml:"ignore-scheme" mapstructure:"ignore-scheme"`
+
+	StripWWW bool `toml:"strip-www" mapstructure:"strip-www"`
+
+	// Fragments used for routing, as in `#/path` or `#!path`, are kept
+	StripFragment bool `toml:"strip-fragment" mapstructure:"strip-fragment"`
+
+	StripTrailingSlash bool `toml:"strip-trailing-slash" mapstructure:"strip-trailing-slash"`
+
+	// Query parameters removed from the urls. Patterns use the shell syntax,
+	// as in `utm_*`.
+	TrackingParams []string `toml:"tracking-params" mapstructure:"tracking-params"`
+}
+
+var URLsConfig = &urlsConfig{
+	Canonicalize:       true,
+	IgnoreScheme:       true,
+	StripWWW:           true,
+	StripFragment:      true,
+	StripTrailingSlash: true,
+	TrackingParams: []string{
+		"utm_*",
+		"fbclid",
+		"gclid",
+		"dclid",
+		"msclkid",
+		"yclid",
+		"twclid",
+		"igshid",
+		"mc_cid",
+		"mc_eid",
+		"_hsenc",
+		"_hsmi",
+	},
+}
+
+// hosts of git forges where the `.git` suffix of clone urls is dropped
+var forgeHosts = []string{"github.com", "gitlab.com", "codeberg.org", "bitbucket.org"}
+
+func isTrackingParam(name string) bool {
+	for _, pattern := range URLsConfig.TrackingParams {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CanonicalURL returns the canonical form of rawURL used to detect duplicate
+// bookmarks. Only http and https urls are changed, following [URLsConfig].
+func CanonicalURL(rawURL string) string {
+	conf := URLsConfig
+	if !conf.Canonicalize {
+		return rawURL
+	}
+
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" || u.Opaque != "" {
+		return rawURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return rawURL
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" &&
+		!(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+	if conf.StripWWW {
+		host = strings.TrimPrefix(host, "www.")
+	}
+	u.Host = host
+
+	if conf.IgnoreScheme {
+		u.Scheme = "https"
+	}
+
+	if conf.StripFragment &&
+		!strings.HasPrefix(u.Fragment, "/") && !strings.HasPrefix(u.Fragment, "!") {
+		u.Fragment = ""
+		u.RawFragment = ""
+	}
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		for name := range query {
+			if isTrackingParam(name) {
+				query.Del(name)
+			}
+		}
+		// sorted by key
+		u.RawQuery = query.Encode()
+	}
+	u.ForceQuery = false
+
+	if conf.StripTrailingSlash {
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = strings.TrimRight(u.RawPath, "/")
+	}
+
+	for _, forge := range forgeHosts {
+		if host == forge {
+			u.Path = strings.TrimSuffix(u.Path, ".git")
+			u.RawPath = strings.TrimSuffix(u.RawPath, ".git")
+		}
+	}
+
+	u.User = nil
+	return u.String()
+}
+
+// canonicalTarget returns the url of the bookmark a bookmark with the given
+// url is merged into: the bookmark itself if it exists, otherwise the oldest
+// bookmark with the same canonical url. It returns url when there is no such
+// bookmark.
+func canonicalTarget(q sqlx.Queryer, url, canonical string) string {
+	var target string
+	err := sqlx.Get(q, &target,
+		`SELECT URL FROM gskbookmarks WHERE canonical = ? OR URL = ?
+		ORDER BY URL = ? DESC, id LIMIT 1`,
+		canonical, url, url,
+	)
+	if err != nil {
+		return url
+	}
+	return target
+}
+
+// updateCanonicalURLs sets the canonical urls of the bookmarks that were
+// added before the canonical column or with another config
+func updateCanonicalURLs(tx *sqlx.Tx) error {
+	var rows []struct {
+		URL       string `db:"URL"`
+		Canonical string
+	}
+	if err := tx.Select(&rows, `SELECT URL, canonical FROM gskbookmarks`); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		canonical := CanonicalURL(row.URL)
+		if canonical == row.Canonical {
+			continue
+		}
+		_, err := tx.Exec(`UPDATE gskbookmarks SET canonical = ? WHERE URL = ?`,
+			canonical, row.URL)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateCanonicalURLs updates the canonical urls of the bookmarks in db
+// after a change of [URLsConfig]
+func (db *DB) UpdateCanonicalURLs() error {
+	tx, err := db.Handle.Beginx()
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	if err = updateCanonicalURLs(tx); err != nil {
+		tx.Rollback()
+		return DBError{DBName: db.Name, Err: err}
+	}
+	if err = tx.Commit(); err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	return nil
+}
+
+func init() {
+	config.RegisterConfigurator("urls", config.AsConfigurator(URLsConfig))
+}
@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		url, want string
+	}{
+		{"https://example.com/page", "https://example.com/page"},
+		{"http://example.com/page", "https://example.com/page"},
+		{"https://www.Example.com/page/", "https://example.com/page"},
+		{"https://example.com:443/page", "https://example.com/page"},
+		{"http://example.com:8080/page", "https://example.com:8080/page"},
+		{"https://example.com/page#section", "https://example.com/page"},
+		{"https://example.com/#/route", "https://example.com#/route"},
+		{"https://example.com/page?utm_source=feed&b=2&a=1&fbclid=x", "https://example.com/page?a=1&b=2"},
+		{"https://example.com/page?", "https://example.com/page"},
+		{"https://github.com/blob42/gosuki.git", "https://github.com/blob42/gosuki"},
+		{"https://example.com/repo.git", "https://example.com/repo.git"},
+		{"ftp://www.example.com/file/", "ftp://www.example.com/file/"},
+		{"mailto:user@example.com", "mailto:user@example.com"},
+		{"not a url", "not a url"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, CanonicalURL(tt.url), tt.url)
+	}
+
+	conf := *URLsConfig
+	t.Cleanup(func() { *URLsConfig = conf })
+
+	URLsConfig.Canonicalize = false
+	assert.Equal(t, "http://www.example.com/", CanonicalURL("http://www.example.com/"))
+}
+
+func TestUpsertMergesCanonical(t *testing.T) {
+	buffer := getBuffer(t)
+	defer buffer.Close()
+
+	require.NoError(t, buffer.UpsertBookmark(&Bookmark{
+		URL:   "https://example.org/article",
+		Title: "Article",
+		Tags:  []string{"foo"},
+	}))
+	require.NoError(t, buffer.UpsertBookmark(&Bookmark{
+		URL:  "http://www.example.org/article/?utm_source=rss",
+		Tags: []string{"bar"},
+	}))
+
+	var got []RawBookmark
+	require.NoError(t, buffer.Handle.Select(&got, `SELECT * FROM gskbookmarks`))
+	require.Len(t, got, 1)
+	assert.Equal(t, "https://example.org/article", got[0].URL)
+	assert.Equal(t, "https://example.org/article", got[0].Canonical)
+	assert.Contains(t, got[0].Tags, "foo")
+	assert.Contains(t, got[0].Tags, "bar")
+}
+
+func TestSyncMergesCanonical(t *testing.T) {
+	setupPendingTest(t)
+
+	buffer := getBuffer(t)
+	defer buffer.Close()
+	require.NoError(t, buffer.UpsertBookmark(&Bookmark{
+		URL:  "https://github.com/blob42/gosuki.git",
+		Tags: []string{"go"},
+	}))
+	buffer.SyncTo(Cache.DB)
+
+	buffer2 := getBuffer(t)
+	defer buffer2.Close()
+	require.NoError(t, buffer2.UpsertBookmark(&Bookmark{
+		URL:  "https://github.com/blob42/gosuki",
+		Tags: []string{"bookmarks"},
+	}))
+	buffer2.SyncTo(Cache.DB)
+
+	var got []RawBookmark
+	require.NoError(t, Cache.Handle.Select(&got,
+		`SELECT * FROM gskbookmarks WHERE canonical = ?`, "https://github.com/blob42/gosuki"))
+	require.Len(t, got, 1)
+	assert.Equal(t, "https://github.com/blob42/gosuki.git", got[0].URL)
+	assert.Contains(t, got[0].Tags, "go")
+	assert.Contains(t, got[0].Tags, "bookmarks")
+}
+
+func TestDedupe(t *testing.T) {
+	ctx := context.Background()
+	setupPendingTest(t)
+
+	// bookmarks added before the canonical column
+	for _, raw := range []RawBookmark{
+		{URL: "http://www.example.org/page/", Metadata: "Page", Tags: ",foo,"},
+		{URL: "https://example.org/page?utm_medium=mail", Tags: ",bar,", Desc: "description", Flags: int(FlagPinned)},
+		{URL: "https://example.org/page#top", Metadata: "Other title", Tags: ",foo,baz,"},
+		{URL: "https://example.org/other"},
+	} {
+		_, err := DiskDB.Handle.Exec(
+			`INSERT INTO gskbookmarks(URL, metadata, tags, desc, flags) VALUES (?, ?, ?, ?, ?)`,
+			raw.URL, raw.Metadata, raw.Tags, raw.Desc, raw.Flags)
+		require.NoError(t, err)
+	}
+
+	dups, err := FindDuplicates(ctx)
+	require.NoError(t, err)
+	require.Len(t, dups, 1)
+	assert.Equal(t, "https://example.org/page", dups[0].Canonical)
+	require.Len(t, dups[0].Bookmarks, 3)
+	assert.Equal(t, "http://www.example.org/page/", dups[0].Bookmarks[0].URL)
+
+	merged := dups[0].Merge()
+	assert.Equal(t, "Page", merged.Title)
+	assert.Equal(t, "description", merged.Desc)
+	assert.ElementsMatch(t, []string{"foo", "bar", "baz"}, merged.Tags)
+	assert.Equal(t, int(FlagPinned), merged.Flags)
+
+	require.NoError(t, MergeDuplicates(ctx, dups))
+
+	dups, err = FindDuplicates(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, dups)
+
+	// the merge is replayed on the caches
+	require.NoError(t, ApplyPendingChanges())
+	for _, db := range []*DB{DiskDB, Cache.DB, L2Cache.DB} {
+		var got []RawBookmark
+		require.NoError(t, db.Handle.Select(&got,
+			`SELECT * FROM gskbookmarks WHERE URL LIKE '%example.org/page%'`))
+		require.Len(t, got, 1, db.Name)
+		assert.Equal(t, "http://www.example.org/page/", got[0].URL)
+		assert.Equal(t, ",bar,baz,foo,", got[0].Tags)
+		assert.Equal(t, "description", got[0].Desc)
+		assert.Equal(t, int(FlagPinned), got[0].Flags)
+	}
+}
@@ -112,6 +112,10 @@ func (e DBError) Error() string {
 	return fmt.Sprintf("<%s>: %s", e.DBName, e.Err)
 }
 
+func (e DBError) Unwrap() error {
+	return e.Err
+}
+
 var (
 	ErrVfsLocked = errors.New("vfs locked")
 )
@@ -0,0 +1,170 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/bus"
+)
+
+// Duplicates is a group of bookmarks with the same canonical url, ordered
+// from the oldest to the newest.
+type Duplicates struct {
+	Canonical string      `json:"canonical"`
+	Bookmarks []*Bookmark `json:"bookmarks"`
+}
+
+// Merge returns the oldest bookmark of the group with the tags, description
+// and flags of the others. Its title is kept unless it is empty. The sources
+// and creation time of the others are moved to it when the duplicates are
+// merged.
+func (d Duplicates) Merge() *Bookmark {
+	merged := *d.Bookmarks[0]
+	merged.Tags = slices.Clone(merged.Tags)
+
+	for _, bk := range d.Bookmarks[1:] {
+		if merged.Title == "" {
+			merged.Title = bk.Title
+		}
+		if merged.Desc == "" {
+			merged.Desc = bk.Desc
+		}
+		merged.Tags = utils.Extends(merged.Tags, bk.Tags...)
+		merged.Flags |= bk.Flags
+	}
+
+	return &merged
+}
+
+// changes returns the changes merging the duplicates into the oldest bookmark
+func (d Duplicates) changes() []*PendingChange {
+	merged := d.Merge()
+	changes := []*PendingChange{newSetChange(merged)}
+	if merged.Flags != 0 {
+		changes = append(changes, newFlagsChange(merged.URL, Flags(merged.Flags), 0))
+	}
+	for _, bk := range d.Bookmarks[1:] {
+		changes = append(changes, &PendingChange{Op: OpMerge, URL: bk.URL, Target: merged.URL})
+	}
+	return changes
+}
+
+// Duplicates returns the groups of bookmarks sharing the same canonical url
+// with the current [URLsConfig].
+func (db *DB) Duplicates(ctx context.Context) ([]Duplicates, error) {
+	raws := RawBookmarks{}
+	err := db.Handle.SelectContext(ctx, &raws, `SELECT * FROM gskbookmarks ORDER BY id`)
+	if err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+
+	var groups []Duplicates
+	index := map[string]int{}
+	for _, bk := range raws.AsBookmarks() {
+		canonical := CanonicalURL(bk.URL)
+		i, ok := index[canonical]
+		if !ok {
+			i = len(groups)
+			index[canonical] = i
+			groups = append(groups, Duplicates{Canonical: canonical})
+		}
+		groups[i].Bookmarks = append(groups[i].Bookmarks, bk)
+	}
+
+	groups = slices.DeleteFunc(groups, func(d Duplicates) bool {
+		return len(d.Bookmarks) < 2
+	})
+	for _, d := range groups {
+		slices.SortStableFunc(d.Bookmarks, func(a, b *Bookmark) int {
+			return cmp.Compare(a.Created, b.Created)
+		})
+	}
+	return groups, nil
+}
+
+// FindDuplicates returns the duplicate bookmarks of the on-disk db
+func FindDuplicates(ctx context.Context) ([]Duplicates, error) {
+	return DiskDB.Duplicates(ctx)
+}
+
+// MergeDuplicates merges each group of duplicates of the on-disk db into its
+// oldest bookmark. It is safe to call while the daemon is running.
+func MergeDuplicates(ctx context.Context, dups []Duplicates) error {
+	var changes []*PendingChange
+	for _, d := range dups {
+		changes = append(changes, d.changes()...)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return writeDisk(ctx, changes...)
+}
+
+// FindCachedDuplicates is the daemon side equivalent of [FindDuplicates]
+func FindCachedDuplicates(ctx context.Context) ([]Duplicates, error) {
+	if Cache.DB == nil || L2Cache.DB == nil {
+		return nil, errCacheNotInitialized
+	}
+
+	// the L2 cache uses the same bookmark ids as the on-disk db
+	SyncCaches()
+	return L2Cache.Duplicates(ctx)
+}
+
+// MergeCachedDuplicates is the daemon side equivalent of [MergeDuplicates]
+func MergeCachedDuplicates(dups []Duplicates) error {
+	if Cache.DB == nil || L2Cache.DB == nil {
+		return errCacheNotInitialized
+	}
+
+	var changes []*PendingChange
+	for _, d := range dups {
+		changes = append(changes, d.changes()...)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if err := applyToCaches(changes); err != nil {
+		return err
+	}
+	ScheduleBackupToDisk()
+
+	for _, d := range dups {
+		bus.Bookmarks.Publish(bus.BookmarkEvent{
+			Type:     bus.BookmarkUpdated,
+			Bookmark: d.Merge(),
+		})
+		for _, bk := range d.Bookmarks[1:] {
+			bus.Bookmarks.Publish(bus.BookmarkEvent{
+				Type:     bus.BookmarkDeleted,
+				Bookmark: &Bookmark{URL: bk.URL},
+			})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki/pkg/bus"
+)
+
+func nextEvent(t *testing.T, sub *bus.Subscription[bus.BookmarkEvent]) bus.BookmarkEvent {
+	t.Helper()
+	select {
+	case ev := <-sub.C:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("no bookmark event")
+	}
+	return bus.BookmarkEvent{}
+}
+
+func TestSyncBookmarkEvents(t *testing.T) {
+	setupPendingTest(t)
+	sub := bus.Bookmarks.Subscribe(bus.Options{Name: "test"})
+	defer sub.Close()
+
+	buffer := getBuffer(t)
+	defer buffer.Close()
+
+	require.NoError(t, buffer.UpsertBookmark(&Bookmark{
+		URL:    "https://new.example.com",
+		Title:  "new",
+		Tags:   []string{"foo"},
+		Module: "test",
+	}))
+	require.NoError(t, buffer.UpsertBookmark(&Bookmark{
+		URL:    testBookmarks[0].URL,
+		Title:  testBookmarks[0].Metadata,
+		Tags:   []string{"extra"},
+		Module: "test",
+	}))
+
+	buffer.SyncTo(Cache.DB)
+
+	ev := nextEvent(t, sub)
+	assert.Equal(t, bus.BookmarkAdded, ev.Type)
+	assert.Equal(t, "https://new.example.com", ev.Bookmark.URL)
+	assert.Equal(t, []string{"foo"}, ev.Bookmark.Tags)
+
+	ev = nextEvent(t, sub)
+	assert.Equal(t, bus.BookmarkUpdated, ev.Type)
+	assert.Equal(t, testBookmarks[0].URL, ev.Bookmark.URL)
+	assert.ElementsMatch(t, []string{"example", "homepage", "extra"}, ev.Bookmark.Tags)
+
+	// syncing the caches between them is not a change
+	Cache.SyncTo(L2Cache.DB)
+	select {
+	case ev := <-sub.C:
+		t.Fatalf("unexpected event: %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestCachedBookmarkEvents(t *testing.T) {
+	setupPendingTest(t)
+	sub := bus.Bookmarks.Subscribe(bus.Options{Name: "test"})
+	defer sub.Close()
+
+	require.NoError(t, SetCachedBookmark(&Bookmark{URL: "https://new.example.com"}))
+	assert.Equal(t, bus.BookmarkAdded, nextEvent(t, sub).Type)
+
+	require.NoError(t, SetCachedBookmark(&Bookmark{URL: testBookmarks[0].URL, Title: "edited"}))
+	ev := nextEvent(t, sub)
+	assert.Equal(t, bus.BookmarkUpdated, ev.Type)
+	assert.Equal(t, "edited", ev.Bookmark.Title)
+
+	require.NoError(t, RemoveCachedBookmark(testBookmarks[1].URL))
+	ev = nextEvent(t, sub)
+	assert.Equal(t, bus.BookmarkDeleted, ev.Type)
+	assert.Equal(t, testBookmarks[1].URL, ev.Bookmark.URL)
+}
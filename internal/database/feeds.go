@@ -0,0 +1,54 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+)
+
+// RecentBookmarks runs [DB.RecentBookmarks] on the on-disk db
+func RecentBookmarks(ctx context.Context, tag string, limit int) ([]*Bookmark, error) {
+	return DiskDB.RecentBookmarks(ctx, tag, limit)
+}
+
+// RecentBookmarks returns the limit most recently modified bookmarks, only the
+// ones tagged with tag when it is not empty. Private bookmarks are excluded.
+func (db *DB) RecentBookmarks(ctx context.Context, tag string, limit int) ([]*Bookmark, error) {
+	where := `WHERE flags & ? = 0`
+	args := []any{FlagPrivate}
+	if tag != "" {
+		where += ` AND instr(tags, ?) > 0`
+		args = append(args, delimWrap(tag, TagSep))
+	}
+	args = append(args, limit)
+
+	rawBooks := RawBookmarks{}
+	err := db.Handle.SelectContext(ctx, &rawBooks,
+		`SELECT * FROM gskbookmarks `+where+` ORDER BY modified DESC, id DESC LIMIT ?`,
+		args...)
+	if err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+
+	return rawBooks.AsBookmarks(), nil
+}
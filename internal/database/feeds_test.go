@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentBookmarks(t *testing.T) {
+	ctx := context.Background()
+	setupPendingTest(t)
+
+	for i, bk := range []*Bookmark{
+		{URL: "https://a.com", Tags: []string{"to-share"}},
+		{URL: "https://b.com", Tags: []string{"to-share-later"}},
+		{URL: "https://c.com", Tags: []string{"to-share", "go"}},
+		{URL: "https://d.com", Tags: []string{"to-share"}},
+	} {
+		require.NoError(t, SetBookmark(ctx, bk))
+		_, err := DiskDB.Handle.Exec(`UPDATE gskbookmarks SET modified = ? WHERE url = ?`,
+			1000+i, bk.URL)
+		require.NoError(t, err)
+	}
+	require.NoError(t, SetFlags(ctx, "https://d.com", FlagPrivate, 0))
+
+	urls := func(marks []*Bookmark) []string {
+		var res []string
+		for _, bk := range marks {
+			res = append(res, bk.URL)
+		}
+		return res
+	}
+
+	marks, err := RecentBookmarks(ctx, "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://c.com", "https://b.com", "https://a.com"}, urls(marks))
+
+	marks, err = RecentBookmarks(ctx, "to-share", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://c.com", "https://a.com"}, urls(marks))
+
+	marks, err = RecentBookmarks(ctx, "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://c.com"}, urls(marks))
+}
@@ -0,0 +1,120 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/bus"
+)
+
+// Flags is the bitmask stored in the `flags` column of the bookmarks, see
+// [gosuki.Flags]
+type Flags = gosuki.Flags
+
+const (
+	ModuleFlags = gosuki.ModuleFlags
+
+	FlagImmutableTitle = gosuki.FlagImmutableTitle
+	FlagPinned         = gosuki.FlagPinned
+	FlagPrivate        = gosuki.FlagPrivate
+	FlagReadLater      = gosuki.FlagReadLater
+	FlagArchived       = gosuki.FlagArchived
+)
+
+// qSetFlags sets the bits of mask to the bits of flags
+const qSetFlags = `
+	UPDATE gskbookmarks
+	SET flags = (flags & ~?) | (? & ?), version = ?, modified = strftime('%s')
+	WHERE URL = ?
+	`
+
+func setFlags(tx *sqlx.Tx, url string, flags, mask Flags, version uint64) error {
+	res, err := tx.Exec(qSetFlags, mask, flags, mask, version, url)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrBookmarkNotFound
+	}
+	return nil
+}
+
+// SetFlags sets the flags in set and clears the flags in clear of the bookmark
+// with the given url in the on-disk db. It is safe to call while the daemon is
+// running.
+func SetFlags(ctx context.Context, url string, set, clear Flags) error {
+	return writeDisk(ctx, newFlagsChange(url, set, clear))
+}
+
+// SetCachedFlags is the daemon side equivalent of [SetFlags]. It returns the
+// updated bookmark.
+func SetCachedFlags(url string, set, clear Flags) (*Bookmark, error) {
+	if Cache.DB == nil || L2Cache.DB == nil {
+		return nil, errCacheNotInitialized
+	}
+
+	var exists int
+	if err := Cache.Handle.Get(&exists, `SELECT 1 FROM gskbookmarks WHERE URL = ?`, url); err != nil {
+		return nil, ErrBookmarkNotFound
+	}
+
+	if err := applyToCaches([]*PendingChange{newFlagsChange(url, set, clear)}); err != nil {
+		return nil, err
+	}
+	ScheduleBackupToDisk()
+
+	var raw RawBookmark
+	if err := Cache.Handle.Get(&raw, `SELECT * FROM gskbookmarks WHERE URL = ?`, url); err != nil {
+		return nil, DBError{DBName: Cache.Name, Err: err}
+	}
+
+	var events bookmarkEvents
+	events.add(bus.BookmarkUpdated, raw)
+	events.publish()
+
+	return RawBookmarks{&raw}.AsBookmarks()[0], nil
+}
+
+func newFlagsChange(url string, set, clear Flags) *PendingChange {
+	return &PendingChange{
+		Op:    OpFlags,
+		URL:   url,
+		Flags: set,
+		Mask:  set | clear,
+	}
+}
+
+// ExcludePrivate returns the bookmarks not flagged as private
+func ExcludePrivate(bookmarks []*Bookmark) []*Bookmark {
+	res := make([]*Bookmark, 0, len(bookmarks))
+	for _, bk := range bookmarks {
+		if !Flags(bk.Flags).Has(FlagPrivate) {
+			res = append(res, bk)
+		}
+	}
+	return res
+}
@@ -0,0 +1,197 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki/pkg/bus"
+	"github.com/blob42/gosuki/pkg/marktab"
+)
+
+func TestListPinnedAndArchived(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDB("test_list_flags", "", DBTypeInMemoryDSN).Init()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.InitSchema(ctx))
+
+	for _, bk := range []*Bookmark{
+		{URL: "https://a.com", Title: "a", Tags: []string{"go"}},
+		{URL: "https://b.com", Title: "b", Tags: []string{"go"}, Flags: int(FlagPinned)},
+		{URL: "https://c.com", Title: "c", Tags: []string{"go"}, Flags: int(FlagArchived)},
+		{URL: "https://d.com", Title: "d", Tags: []string{"go"}, Flags: int(FlagArchived | FlagPinned)},
+	} {
+		require.NoError(t, db.UpsertBookmark(bk))
+	}
+
+	urls := func(res *QueryResult) []string {
+		var urls []string
+		for _, bk := range res.Bookmarks {
+			urls = append(urls, bk.URL)
+		}
+		return urls
+	}
+	all := &PaginationParams{Page: 1, Size: -1}
+
+	res, err := db.ListBookmarks(ctx, all)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://b.com", "https://a.com"}, urls(res))
+	assert.Equal(t, uint(2), res.Total)
+
+	res, err = db.ArchivedBookmarks(ctx, all)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://d.com", "https://c.com"}, urls(res))
+
+	res, err = db.AllBookmarks(ctx, all)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://b.com", "https://d.com", "https://a.com", "https://c.com"}, urls(res))
+	assert.Equal(t, uint(4), res.Total)
+
+	// searches find the archived bookmarks, pinned first
+	res, err = db.QueryBookmarks(ctx, ".com", false, all)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://b.com", "https://d.com", "https://a.com", "https://c.com"}, urls(res))
+
+	res, err = db.BookmarksByTag(ctx, "go", all)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://b.com", "https://d.com", "https://a.com", "https://c.com"}, urls(res))
+}
+
+func cachedFlags(t *testing.T, db *DB, url string) Flags {
+	t.Helper()
+	var flags Flags
+	require.NoError(t, db.Handle.Get(&flags, `SELECT flags FROM gskbookmarks WHERE URL = ?`, url))
+	return flags
+}
+
+func TestSetFlags(t *testing.T) {
+	ctx := context.Background()
+	setupPendingTest(t)
+	url := testBookmarks[0].URL
+	require.NoError(t, SetBookmark(ctx, &Bookmark{URL: url, Title: "title"}))
+
+	require.NoError(t, SetFlags(ctx, url, FlagPinned|FlagPrivate, 0))
+	require.NoError(t, SetFlags(ctx, url, FlagArchived, FlagPrivate))
+	assert.Equal(t, FlagPinned|FlagArchived, cachedFlags(t, DiskDB, url))
+
+	err := SetFlags(ctx, "https://missing.example.com", FlagPinned, 0)
+	assert.ErrorIs(t, err, ErrBookmarkNotFound)
+
+	// the journal is replayed on the caches
+	require.NoError(t, ApplyPendingChanges())
+	for _, cache := range []*DB{Cache.DB, L2Cache.DB} {
+		assert.Equal(t, FlagPinned|FlagArchived, cachedFlags(t, cache, url), cache.Name)
+	}
+
+	bk, err := DiskDB.BookmarkByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int(FlagPinned|FlagArchived), bk.Flags)
+}
+
+func TestSetCachedFlags(t *testing.T) {
+	setupPendingTest(t)
+	url := testBookmarks[1].URL
+	sub := bus.Bookmarks.Subscribe(bus.Options{Name: "test"})
+	defer sub.Close()
+
+	bk, err := SetCachedFlags(url, FlagReadLater|FlagImmutableTitle, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int(FlagReadLater|FlagImmutableTitle), bk.Flags)
+	for _, cache := range []*DB{Cache.DB, L2Cache.DB} {
+		assert.Equal(t, FlagReadLater|FlagImmutableTitle, cachedFlags(t, cache, url))
+	}
+
+	ev := nextEvent(t, sub)
+	assert.Equal(t, bus.BookmarkUpdated, ev.Type)
+	assert.Equal(t, int(FlagReadLater|FlagImmutableTitle), ev.Bookmark.Flags)
+
+	_, err = SetCachedFlags("https://missing.example.com", FlagPinned, 0)
+	assert.ErrorIs(t, err, ErrBookmarkNotFound)
+}
+
+func TestSyncKeepsFlags(t *testing.T) {
+	setupPendingTest(t)
+	immutable := testBookmarks[0]
+	pinned := testBookmarks[1]
+
+	_, err := SetCachedFlags(immutable.URL, FlagImmutableTitle|FlagPrivate, 0)
+	require.NoError(t, err)
+	_, err = SetCachedFlags(pinned.URL, FlagPinned, 0)
+	require.NoError(t, err)
+
+	// the modules resync the bookmarks with other titles and no flags
+	buffer := getBuffer(t)
+	defer buffer.Close()
+	for _, raw := range []RawBookmark{immutable, pinned} {
+		require.NoError(t, buffer.UpsertBookmark(&Bookmark{
+			URL:    raw.URL,
+			Title:  "title from module",
+			Tags:   []string{"synced"},
+			Module: "test",
+		}))
+	}
+	buffer.SyncTo(Cache.DB)
+	Cache.SyncTo(L2Cache.DB)
+
+	for _, cache := range []*DB{Cache.DB, L2Cache.DB} {
+		var got RawBookmark
+		require.NoError(t, cache.Handle.Get(&got, `SELECT * FROM gskbookmarks WHERE URL = ?`, immutable.URL))
+		assert.Equal(t, immutable.Metadata, got.Metadata, "immutable title in %s", cache.Name)
+		assert.Contains(t, got.Tags, "synced")
+		assert.Equal(t, int(FlagImmutableTitle|FlagPrivate), got.Flags)
+		assert.Equal(t, xhsum(got.URL, got.Metadata, got.Tags, got.Desc), got.XHSum)
+
+		require.NoError(t, cache.Handle.Get(&got, `SELECT * FROM gskbookmarks WHERE URL = ?`, pinned.URL))
+		assert.Equal(t, "title from module", got.Metadata)
+		assert.Equal(t, int(FlagPinned), got.Flags, cache.Name)
+	}
+
+	// flag changes made on the L1 cache are synced to the L2 cache
+	_, err = Cache.Handle.Exec(`UPDATE gskbookmarks SET flags = ? WHERE URL = ?`, FlagArchived, pinned.URL)
+	require.NoError(t, err)
+	Cache.SyncTo(L2Cache.DB)
+	assert.Equal(t, FlagArchived, cachedFlags(t, L2Cache.DB, pinned.URL))
+}
+
+func TestSyncArchivedByRule(t *testing.T) {
+	setupPendingTest(t)
+	existing := testBookmarks[0]
+
+	_, err := SetCachedFlags(existing.URL, FlagPinned, 0)
+	require.NoError(t, err)
+
+	// a marktab archive rule matches a bookmark already in the caches
+	bk := &Bookmark{URL: existing.URL, Title: existing.Metadata, Module: "test"}
+	require.True(t, marktab.Action{Type: marktab.ActionArchive}.Apply(bk))
+
+	buffer := getBuffer(t)
+	defer buffer.Close()
+	require.NoError(t, buffer.UpsertBookmark(bk))
+	buffer.SyncTo(Cache.DB)
+	Cache.SyncTo(L2Cache.DB)
+
+	for _, cache := range []*DB{Cache.DB, L2Cache.DB} {
+		assert.Equal(t, FlagPinned|FlagArchived, cachedFlags(t, cache, existing.URL), cache.Name)
+	}
+
+	// other flags of the modules are not kept
+	bk.Flags |= int(FlagPrivate)
+	require.NoError(t, buffer.UpsertBookmark(bk))
+	buffer.SyncTo(Cache.DB)
+	assert.Equal(t, FlagPinned|FlagArchived, cachedFlags(t, Cache.DB, existing.URL))
+}
+
+func TestExcludePrivate(t *testing.T) {
+	bookmarks := []*Bookmark{
+		{URL: "https://a.com"},
+		{URL: "https://b.com", Flags: int(FlagPrivate | FlagPinned)},
+		{URL: "https://c.com", Flags: int(FlagPinned)},
+	}
+	res := ExcludePrivate(bookmarks)
+	require.Len(t, res, 2)
+	assert.Equal(t, "https://a.com", res[0].URL)
+	assert.Equal(t, "https://c.com", res[1].URL)
+}
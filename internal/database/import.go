@@ -0,0 +1,121 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ImportedBookmark is a bookmark imported from the export file of another
+// program. The creation time is the Created time of the bookmark.
+type ImportedBookmark struct {
+	*Bookmark
+
+	// the bookmark is in the read later queue of the program, read or not
+	Queued bool
+	Unread bool
+
+	// times the bookmark was queued and read
+	QueuedAt time.Time
+	ReadAt   time.Time
+}
+
+// ImportBookmarks writes the imported bookmarks and their read later state to
+// the on-disk db in a single transaction. Unlike [SetBookmark], the tags are
+// added to the ones of an existing bookmark, an empty title or description
+// keeps the existing one and the oldest creation time is kept. It is safe to
+// call while the daemon is running.
+func ImportBookmarks(ctx context.Context, items []ImportedBookmark) error {
+	changes := make([]*PendingChange, 0, len(items))
+	for _, item := range items {
+		bk := item.Bookmark
+		change := newSetChange(bk)
+		change.Op = OpImport
+		change.CreatedAt = bk.Created
+		changes = append(changes, change)
+
+		if item.Queued {
+			changes = append(changes,
+				newReadStateChange(bk.URL, item.Unread, item.QueuedAt, item.ReadAt))
+		}
+	}
+	return writeDisk(ctx, changes...)
+}
+
+// importBookmark merges the imported bookmark of change with the existing
+// bookmark at the same url, see [ImportBookmarks]
+func importBookmark(tx *sqlx.Tx, c *PendingChange, version uint64) error {
+	title, tags, desc := c.Metadata, c.Tags, c.Desc
+
+	var existing RawBookmark
+	err := tx.Get(&existing, `SELECT metadata, tags, desc FROM gskbookmarks WHERE URL = ?`, c.URL)
+	switch {
+	case err == nil:
+		if title == "" {
+			title = existing.Metadata
+		}
+		if desc == "" {
+			desc = existing.Desc
+		}
+		merged := tagsFromString(existing.Tags, TagSep)
+		for _, tag := range tagsFromString(tags, TagSep).Get() {
+			merged.Add(tag)
+		}
+		tags = merged.Sort().String(true)
+	case !errors.Is(err, sql.ErrNoRows):
+		return err
+	}
+
+	_, err = tx.Exec(qSetBookmark,
+		c.URL,
+		title,
+		tags,
+		desc,
+		c.Module,
+		xhsum(c.URL, title, tags, desc),
+		version,
+		CanonicalURL(c.URL),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(qUpdateCreatedAt, c.CreatedAt, c.URL, c.CreatedAt, c.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return addSource(tx, c.URL, c.Module, c.CreatedAt, c.Created)
+}
+
+// unixTime returns the unix time of t, zero for the zero time
+func unixTime(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.Unix())
+}
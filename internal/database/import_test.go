@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportBookmarks(t *testing.T) {
+	ctx := context.Background()
+	setupPendingTest(t)
+	url := "https://example.org/imported"
+
+	require.NoError(t, SetBookmark(ctx, &Bookmark{
+		URL:   url,
+		Title: "existing",
+		Tags:  []string{"mine"},
+		Desc:  "kept",
+	}))
+
+	require.NoError(t, ImportBookmarks(ctx, []ImportedBookmark{
+		{
+			Bookmark: &Bookmark{URL: url, Tags: []string{"imported"}, Module: "pinboard", Created: 1000},
+		},
+		{
+			Bookmark: &Bookmark{URL: "https://example.org/unread", Title: "unread", Module: "pocket"},
+			Queued:   true,
+			Unread:   true,
+			QueuedAt: time.Unix(100, 0),
+		},
+		{
+			Bookmark: &Bookmark{URL: "https://example.org/read", Module: "pocket"},
+			Queued:   true,
+			QueuedAt: time.Unix(100, 0),
+			ReadAt:   time.Unix(200, 0),
+		},
+	}))
+
+	check := func(db *DB) {
+		var raw RawBookmark
+		require.NoError(t, db.Handle.Get(&raw, `SELECT * FROM gskbookmarks WHERE URL = ?`, url))
+		bk := RawBookmarks{&raw}.AsBookmarks()[0]
+		assert.Equal(t, "existing", bk.Title, db.Name)
+		assert.Equal(t, "kept", bk.Desc, db.Name)
+		assert.ElementsMatch(t, []string{"mine", "imported"}, bk.Tags, db.Name)
+		assert.EqualValues(t, 1000, bk.Created, db.Name)
+
+		sources, err := db.Sources(ctx, []string{url})
+		require.NoError(t, err)
+		require.Len(t, sources[url], 1, db.Name)
+		assert.Equal(t, "pinboard", sources[url][0].Module, db.Name)
+		assert.EqualValues(t, 1000, sources[url][0].Created, db.Name)
+
+		all := &PaginationParams{Page: 1, Size: -1}
+		res, err := db.ReadLater(ctx, false, all)
+		require.NoError(t, err)
+		require.Len(t, res.Bookmarks, 1, db.Name)
+		assert.Equal(t, "https://example.org/unread", res.Bookmarks[0].URL)
+		assert.EqualValues(t, 100, res.Bookmarks[0].QueuedAt)
+
+		res, err = db.ReadLater(ctx, true, all)
+		require.NoError(t, err)
+		require.Len(t, res.Bookmarks, 1, db.Name)
+		assert.EqualValues(t, 200, res.Bookmarks[0].ReadAt)
+	}
+	check(DiskDB)
+
+	// the daemon replays the journal before writing to disk
+	require.NoError(t, ApplyPendingChanges())
+	check(L2Cache.DB)
+}
@@ -86,6 +86,14 @@ func Init(ctx context.Context, cmd *cli.Command) {
 			log.Fatal(err)
 		}
 
+		// Changes journaled by external writers are already part of the
+		// disk content loaded to the caches. Hold the disk lock until the
+		// journal is cleared.
+		lock, err := LockDisk(dbpath)
+		if err != nil {
+			log.Fatal(err)
+		}
+
 		// first sync to the l1 cache from disk
 		err = Cache.SyncFromDisk(dbpath)
 		if err != nil {
@@ -98,6 +106,20 @@ func Init(ctx context.Context, cmd *cli.Command) {
 			log.Fatal(err)
 		}
 
+		err = clearPendingChanges()
+		if err != nil {
+			log.Fatal(err)
+		}
+		lock.Unlock()
+
+		// the canonical urls depend on the config and are refreshed at
+		// startup. They are written to disk with the next backup.
+		for _, cache := range []*DB{Cache.DB, L2Cache.DB} {
+			if err = cache.UpdateCanonicalURLs(); err != nil {
+				log.Fatal(err)
+			}
+		}
+
 	} else {
 		// new pristine db
 		if err != nil {
@@ -60,3 +60,37 @@ func (checker *VFSLockChecker) Locked() (bool, error) {
 	return false, nil
 
 }
+
+// DiskLock is an advisory lock held on a file next to the on-disk database.
+// It coordinates the writes to gosuki.db between the daemon, which
+// periodically overwrites the file with its L2 cache, and external programs
+// such as `suki` which write directly to the file.
+type DiskLock struct {
+	f *os.File
+}
+
+const diskLockSuffix = ".lock"
+
+// LockDisk acquires an exclusive lock for the database file at dbpath. It
+// blocks until the lock is available.
+func LockDisk(dbpath string) (*DiskLock, error) {
+	f, err := os.OpenFile(dbpath+diskLockSuffix, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = unix.Flock(int(f.Fd()), unix.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &DiskLock{f}, nil
+}
+
+func (l *DiskLock) Unlock() error {
+	if l == nil {
+		return nil
+	}
+	defer l.f.Close()
+	return unix.Flock(int(l.f.Fd()), unix.LOCK_UN)
+}
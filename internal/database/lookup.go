@@ -0,0 +1,145 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// TagCount is a tag and the number of bookmarks having it
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+
+	// number of bookmarks of the same site having the tag
+	SiteCount int `json:"site_count,omitempty"`
+}
+
+// GetBookmarkByURL returns the bookmark of the on-disk db saved at url or at
+// an url with the same canonical form
+func GetBookmarkByURL(ctx context.Context, url string) (*Bookmark, error) {
+	return DiskDB.BookmarkByURL(ctx, url)
+}
+
+func (db *DB) BookmarkByURL(ctx context.Context, url string) (*Bookmark, error) {
+	raws := RawBookmarks{}
+	err := db.Handle.SelectContext(ctx, &raws,
+		`SELECT * FROM gskbookmarks WHERE url = ? OR canonical = ?
+		ORDER BY url = ? DESC, id LIMIT 1`,
+		url, CanonicalURL(url), url)
+	if err != nil {
+		return nil, err
+	}
+	if len(raws) == 0 {
+		return nil, ErrBookmarkNotFound
+	}
+
+	return raws.AsBookmarks()[0], nil
+}
+
+// LookupCachedBookmark is the daemon side equivalent of [GetBookmarkByURL]. It
+// reads the L2 cache which has the same bookmark ids as the on-disk db.
+func LookupCachedBookmark(ctx context.Context, url string) (*Bookmark, error) {
+	SyncCachesForRead()
+	return L2Cache.DB.BookmarkByURL(ctx, url)
+}
+
+// SuggestCachedTags is the daemon side equivalent of [SuggestTags]
+func SuggestCachedTags(ctx context.Context, prefix, pageURL string, limit int) ([]TagCount, error) {
+	SyncCachesForRead()
+	return L2Cache.DB.SuggestTags(ctx, prefix, pageURL, limit)
+}
+
+// SuggestTags returns the tags of the on-disk db starting with prefix, see
+// [DB.SuggestTags]
+func SuggestTags(ctx context.Context, prefix, pageURL string, limit int) ([]TagCount, error) {
+	return DiskDB.SuggestTags(ctx, prefix, pageURL, limit)
+}
+
+// SuggestTags returns up to limit tags starting with prefix. The tags used by
+// the bookmarks of the same site as pageURL come first, then the most used
+// tags.
+func (db *DB) SuggestTags(ctx context.Context, prefix, pageURL string, limit int) ([]TagCount, error) {
+	var rows []struct {
+		URL  string `db:"URL"`
+		Tags string `db:"tags"`
+	}
+	err := db.Handle.SelectContext(ctx, &rows,
+		`SELECT URL, tags FROM gskbookmarks WHERE tags != '' AND tags != ?`, TagSep)
+	if err != nil {
+		return nil, err
+	}
+
+	site := siteOf(pageURL)
+	prefix = strings.ToLower(prefix)
+	counts := map[string]*TagCount{}
+	for _, row := range rows {
+		sameSite := site != "" && siteOf(row.URL) == site
+		for _, tag := range tagsFromString(row.Tags, TagSep).Get() {
+			if !strings.HasPrefix(strings.ToLower(tag), prefix) {
+				continue
+			}
+			tc, ok := counts[tag]
+			if !ok {
+				tc = &TagCount{Tag: tag}
+				counts[tag] = tc
+			}
+			tc.Count++
+			if sameSite {
+				tc.SiteCount++
+			}
+		}
+	}
+
+	result := make([]TagCount, 0, len(counts))
+	for _, tc := range counts {
+		result = append(result, *tc)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.SiteCount != b.SiteCount {
+			return a.SiteCount > b.SiteCount
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Tag < b.Tag
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// siteOf returns the host of rawURL without `www.`
+func siteOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookmarkByURL(t *testing.T) {
+	ctx := context.Background()
+	setupPendingTest(t)
+
+	require.NoError(t, SetBookmark(ctx, &Bookmark{URL: "https://example.org/page", Title: "Page"}))
+
+	bk, err := GetBookmarkByURL(ctx, "https://example.org/page")
+	require.NoError(t, err)
+	assert.Equal(t, "Page", bk.Title)
+
+	bk, err = GetBookmarkByURL(ctx, "http://www.example.org/page/?utm_source=feed")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.org/page", bk.URL)
+
+	_, err = GetBookmarkByURL(ctx, "https://example.org/other")
+	assert.ErrorIs(t, err, ErrBookmarkNotFound)
+}
+
+func TestSuggestTags(t *testing.T) {
+	ctx := context.Background()
+	setupPendingTest(t)
+
+	for _, bk := range []*Bookmark{
+		{URL: "https://go.dev/doc", Tags: []string{"golang", "docs"}},
+		{URL: "https://go.dev/blog", Tags: []string{"golang", "blog"}},
+		{URL: "https://example.com/1", Tags: []string{"docs", "demo"}},
+		{URL: "https://example.com/2", Tags: []string{"docs"}},
+		{URL: "https://example.com/3", Tags: []string{"demo"}},
+	} {
+		require.NoError(t, SetBookmark(ctx, bk))
+	}
+
+	tags, err := SuggestTags(ctx, "d", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []TagCount{
+		{Tag: "docs", Count: 3},
+		{Tag: "demo", Count: 2},
+	}, tags)
+
+	// tags of the same site first
+	tags, err = SuggestTags(ctx, "", "https://www.go.dev/tour", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []TagCount{
+		{Tag: "golang", Count: 2, SiteCount: 2},
+		{Tag: "docs", Count: 3, SiteCount: 1},
+	}, tags)
+}
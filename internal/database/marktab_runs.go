@@ -0,0 +1,240 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Marktab actions run by the daemon are recorded in the `marktab_runs` table
+// of the L2 cache, which is backed up to disk with the bookmarks. A rule is
+// run only once for a given bookmark: recorded runs are used to skip the
+// bookmarks that were already handled successfully by the same version of a
+// rule, and to delay new attempts of the rules that failed.
+
+const QCreateMarktabRuns = `
+	CREATE TABLE IF NOT EXISTS marktab_runs (
+		id INTEGER PRIMARY KEY,
+		rule TEXT NOT NULL,
+		rule_hash TEXT NOT NULL,
+		line INTEGER DEFAULT 0,
+		URL TEXT NOT NULL,
+		action TEXT NOT NULL,
+		status TEXT NOT NULL,
+		attempt INTEGER DEFAULT 1,
+		exit_code INTEGER DEFAULT 0,
+		output TEXT DEFAULT '',
+		started INTEGER NOT NULL,
+		duration INTEGER DEFAULT 0
+	);
+
+	CREATE INDEX IF NOT EXISTS marktab_runs_rule_url
+		ON marktab_runs(rule_hash, URL);
+	`
+
+type RunStatus string
+
+const (
+	RunSuccess RunStatus = "success"
+	RunFailed  RunStatus = "failed"
+	RunTimeout RunStatus = "timeout"
+)
+
+// MarktabRun is one attempt at running the action of a marktab rule
+type MarktabRun struct {
+	ID       uint64    `json:"id"`
+	Rule     string    `json:"rule"`
+	RuleHash string    `json:"rule_hash" db:"rule_hash"`
+	Line     int       `json:"line"`
+	URL      string    `json:"url" db:"URL"`
+	Action   string    `json:"action"`
+	Status   RunStatus `json:"status"`
+	Attempt  int       `json:"attempt"`
+	ExitCode int       `json:"exit_code" db:"exit_code"`
+	Output   string    `json:"output"`
+	Started  int64     `json:"started"`  // unix time
+	Duration int64     `json:"duration"` // milliseconds
+}
+
+// RunFilter selects the runs returned by [DB.MarktabRuns]
+type RunFilter struct {
+	ID     uint64
+	Status RunStatus
+	URL    string
+	Limit  int
+}
+
+func (db *DB) InsertMarktabRun(ctx context.Context, run *MarktabRun) error {
+	res, err := db.Handle.ExecContext(ctx,
+		`INSERT INTO marktab_runs(rule, rule_hash, line, URL, action, status,
+		attempt, exit_code, output, started, duration)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.Rule,
+		run.RuleHash,
+		run.Line,
+		run.URL,
+		run.Action,
+		run.Status,
+		run.Attempt,
+		run.ExitCode,
+		run.Output,
+		run.Started,
+		run.Duration,
+	)
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	run.ID = uint64(id)
+	return nil
+}
+
+// MarktabRunState summarizes the recorded runs of a rule for a bookmark
+type MarktabRunState struct {
+	// The rule ran successfully
+	Succeeded bool `db:"succeeded"`
+
+	// Number of failed attempts
+	Failures int `db:"failures"`
+
+	// Start time of the last attempt, unix time
+	LastStarted int64 `db:"last_started"`
+}
+
+// MarktabRunState returns the state of the runs of the rule identified by
+// ruleHash for url
+func (db *DB) MarktabRunState(ctx context.Context, ruleHash, url string) (MarktabRunState, error) {
+	var state MarktabRunState
+	err := db.Handle.GetContext(ctx, &state,
+		`SELECT
+			COALESCE(MAX(status = ?), 0) AS succeeded,
+			COALESCE(SUM(status != ?), 0) AS failures,
+			COALESCE(MAX(started), 0) AS last_started
+		FROM marktab_runs WHERE rule_hash = ? AND URL = ?`,
+		RunSuccess, RunSuccess, ruleHash, url)
+	if err != nil {
+		return state, DBError{DBName: db.Name, Err: err}
+	}
+	return state, nil
+}
+
+// HasMarktabRun reports whether the rule identified by ruleHash already ran
+// successfully for url. Failed runs are not counted.
+func (db *DB) HasMarktabRun(ctx context.Context, ruleHash, url string) (bool, error) {
+	state, err := db.MarktabRunState(ctx, ruleHash, url)
+	return state.Succeeded, err
+}
+
+// PruneMarktabRuns deletes the runs older than the keep most recent ones. The
+// last successful run of each rule and bookmark is kept as it prevents the
+// rule from running again. keep <= 0 keeps all the runs.
+func (db *DB) PruneMarktabRuns(ctx context.Context, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	_, err := db.Handle.ExecContext(ctx,
+		`DELETE FROM marktab_runs
+		WHERE id NOT IN (SELECT id FROM marktab_runs ORDER BY id DESC LIMIT ?)
+		AND id NOT IN (
+			SELECT MAX(id) FROM marktab_runs WHERE status = ?
+			GROUP BY rule_hash, URL
+		)`,
+		keep, RunSuccess)
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	return nil
+}
+
+// MarktabRuns returns the recorded runs matching filter, most recent first
+func (db *DB) MarktabRuns(ctx context.Context, filter RunFilter) ([]MarktabRun, error) {
+	var where []string
+	var args []any
+
+	if filter.ID != 0 {
+		where = append(where, "id = ?")
+		args = append(args, filter.ID)
+	}
+	if filter.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.URL != "" {
+		where = append(where, "URL LIKE ?")
+		args = append(args, "%"+filter.URL+"%")
+	}
+
+	query := "SELECT * FROM marktab_runs"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC"
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	runs := []MarktabRun{}
+	if err := db.Handle.SelectContext(ctx, &runs, query, args...); err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+	return runs, nil
+}
+
+// RecordMarktabRun stores run in the L2 cache, prunes the history to the keep
+// most recent runs, see [DB.PruneMarktabRuns], and schedules a backup to disk.
+// It is a no-op when the caches are not initialized.
+func RecordMarktabRun(ctx context.Context, run *MarktabRun, keep int) error {
+	if L2Cache.DB == nil {
+		return nil
+	}
+
+	if err := L2Cache.InsertMarktabRun(ctx, run); err != nil {
+		return err
+	}
+	if err := L2Cache.PruneMarktabRuns(ctx, keep); err != nil {
+		return err
+	}
+	ScheduleBackupToDisk()
+	return nil
+}
+
+// CachedMarktabRunState returns the state of the runs of the rule identified
+// by ruleHash for url. Errors are logged and reported as no runs.
+func CachedMarktabRunState(ctx context.Context, ruleHash, url string) MarktabRunState {
+	if L2Cache.DB == nil {
+		return MarktabRunState{}
+	}
+
+	state, err := L2Cache.MarktabRunState(ctx, ruleHash, url)
+	if err != nil {
+		log.Error("checking marktab runs", "err", err)
+	}
+	return state
+}
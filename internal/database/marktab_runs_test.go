@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarktabRuns(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDB("test_marktab_runs", "", DBTypeInMemoryDSN).Init()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.InitSchema(ctx))
+
+	runs := []*MarktabRun{
+		{Rule: "tag=a -> shell true", RuleHash: "aaaa", URL: "https://a.com", Action: "shell", Status: RunSuccess, Started: 1},
+		{Rule: "tag=b -> shell false", RuleHash: "bbbb", URL: "https://b.com", Action: "shell", Status: RunFailed, ExitCode: 1, Started: 2},
+		{Rule: "tag=b -> shell false", RuleHash: "bbbb", URL: "https://b.com", Action: "shell", Status: RunFailed, Attempt: 2, ExitCode: 1, Started: 3},
+	}
+	for _, run := range runs {
+		require.NoError(t, db.InsertMarktabRun(ctx, run))
+		assert.NotZero(t, run.ID)
+	}
+
+	done, err := db.HasMarktabRun(ctx, "aaaa", "https://a.com")
+	require.NoError(t, err)
+	assert.True(t, done)
+
+	done, err = db.HasMarktabRun(ctx, "aaaa", "https://b.com")
+	require.NoError(t, err)
+	assert.False(t, done)
+
+	// failed runs are not counted as done
+	done, err = db.HasMarktabRun(ctx, "bbbb", "https://b.com")
+	require.NoError(t, err)
+	assert.False(t, done)
+
+	state, err := db.MarktabRunState(ctx, "bbbb", "https://b.com")
+	require.NoError(t, err)
+	assert.Equal(t, MarktabRunState{Failures: 2, LastStarted: 3}, state)
+
+	all, err := db.MarktabRuns(ctx, RunFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, runs[2].ID, all[0].ID, "most recent first")
+
+	failed, err := db.MarktabRuns(ctx, RunFilter{Status: RunFailed, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, 2, failed[0].Attempt)
+
+	byURL, err := db.MarktabRuns(ctx, RunFilter{URL: "a.com"})
+	require.NoError(t, err)
+	require.Len(t, byURL, 1)
+	assert.Equal(t, "tag=a -> shell true", byURL[0].Rule)
+}
+
+func TestPruneMarktabRuns(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDB("test_prune_marktab_runs", "", DBTypeInMemoryDSN).Init()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.InitSchema(ctx))
+
+	insert := func(hash string, status RunStatus) {
+		require.NoError(t, db.InsertMarktabRun(ctx, &MarktabRun{
+			Rule: "rule", RuleHash: hash, URL: "https://a.com", Action: "shell", Status: status,
+		}))
+	}
+	insert("aaaa", RunSuccess)
+	insert("aaaa", RunSuccess)
+	for range 5 {
+		insert("bbbb", RunFailed)
+	}
+
+	require.NoError(t, db.PruneMarktabRuns(ctx, 0))
+	all, err := db.MarktabRuns(ctx, RunFilter{})
+	require.NoError(t, err)
+	assert.Len(t, all, 7)
+
+	require.NoError(t, db.PruneMarktabRuns(ctx, 2))
+	all, err = db.MarktabRuns(ctx, RunFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, RunFailed, all[0].Status)
+	assert.Equal(t, RunFailed, all[1].Status)
+	assert.Equal(t, uint64(2), all[2].ID, "last successful run is kept")
+
+	done, err := db.HasMarktabRun(ctx, "aaaa", "https://a.com")
+	require.NoError(t, err)
+	assert.True(t, done)
+}
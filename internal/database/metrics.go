@@ -0,0 +1,94 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	syncDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gosuki_cache_sync_duration_seconds",
+		Help:    "Duration of bookmark syncs to the L1 and L2 caches.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 8),
+	}, []string{"cache"})
+
+	backupDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "gosuki_backup_duration_seconds",
+		Help:    "Duration of the backups of the L2 cache to disk.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 8),
+	})
+
+	backupsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gosuki_backups_total",
+		Help: "Number of backups of the L2 cache to disk by result.",
+	}, []string{"result"})
+)
+
+// observeSync records the duration of a sync to dst started at start
+func observeSync(dst *DB, start time.Time) {
+	var cache string
+	switch dst.Name {
+	case CacheName:
+		cache = "l1"
+	case L2CacheName:
+		cache = "l2"
+	default:
+		return
+	}
+	syncDuration.WithLabelValues(cache).Observe(time.Since(start).Seconds())
+}
+
+// observeBackup records a backup to disk started at start
+func observeBackup(start time.Time, err error) {
+	backupDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		backupsTotal.WithLabelValues("error").Inc()
+		return
+	}
+	backupsTotal.WithLabelValues("success").Inc()
+}
+
+// CountByModule returns the number of bookmarks per module
+func (db *DB) CountByModule(ctx context.Context) (map[string]uint, error) {
+	var rows []struct {
+		Module string
+		Count  uint
+	}
+
+	err := db.Handle.SelectContext(ctx, &rows,
+		`SELECT module, COUNT(*) AS count FROM gskbookmarks GROUP BY module`)
+	if err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+
+	res := make(map[string]uint, len(rows))
+	for _, r := range rows {
+		res[r.Module] = r.Count
+	}
+	return res, nil
+}
@@ -0,0 +1,86 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+// Performs the database schema migration from version 10 to version 11.
+// This migration adds the `created_at` column to the `gskbookmarks` table, the
+// `bookmark_sources` table and the `target` column of the `pending_changes`
+// journal. The creation time of the existing bookmarks is unknown and set to
+// their last modification time. Their module is recorded as their only source.
+func (db *DB) migrateToVersion11() error {
+	log.Debug("DB schema: migrating to v11")
+	tx, err := db.Handle.Beginx()
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	for _, column := range []struct{ table, name, def string }{
+		{"gskbookmarks", "created_at", "INTEGER DEFAULT 0"},
+		{"pending_changes", "target", "TEXT DEFAULT ''"},
+	} {
+		var exists bool
+		err = tx.Get(&exists,
+			`SELECT COUNT(*) > 0 FROM pragma_table_info(?) WHERE name = ?`,
+			column.table, column.name)
+		if err != nil {
+			tx.Rollback()
+			return DBError{DBName: db.Name, Err: err}
+		}
+		if exists {
+			continue
+		}
+
+		_, err = tx.Exec(
+			`ALTER TABLE ` + column.table + ` ADD COLUMN ` + column.name + ` ` + column.def)
+		if err != nil {
+			tx.Rollback()
+			return DBError{DBName: db.Name, Err: err}
+		}
+	}
+
+	_, err = tx.Exec(QCreateBookmarkSources)
+	if err != nil {
+		tx.Rollback()
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	_, err = tx.Exec(`UPDATE gskbookmarks SET created_at = modified WHERE created_at = 0`)
+	if err != nil {
+		tx.Rollback()
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	_, err = tx.Exec(
+		`INSERT OR IGNORE INTO bookmark_sources(URL, module, seen_at)
+		SELECT URL, module, modified FROM gskbookmarks WHERE module != ''`)
+	if err != nil {
+		tx.Rollback()
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+// Performs the database schema migration from version 11 to version 12.
+// This migration adds the `created_at`, `queued_at` and `read_at` columns to
+// the `pending_changes` journal used to record the bookmarks imported from
+// other programs with their creation time and read later state.
+func (db *DB) migrateToVersion12() error {
+	log.Debug("DB schema: migrating to v12")
+	tx, err := db.Handle.Beginx()
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	for _, column := range []string{"created_at", "queued_at", "read_at"} {
+		var exists bool
+		err = tx.Get(&exists,
+			`SELECT COUNT(*) > 0 FROM pragma_table_info('pending_changes') WHERE name = ?`,
+			column)
+		if err != nil {
+			tx.Rollback()
+			return DBError{DBName: db.Name, Err: err}
+		}
+		if exists {
+			continue
+		}
+
+		_, err = tx.Exec(`ALTER TABLE pending_changes ADD COLUMN ` + column + ` INTEGER DEFAULT 0`)
+		if err != nil {
+			tx.Rollback()
+			return DBError{DBName: db.Name, Err: err}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import "fmt"
+
+// Performs the database schema migration from version 7 to version 8.
+// This migration adds the `flags` and `mask` columns to the `pending_changes`
+// journal used to record the changes of the bookmark flags. See [SetFlags].
+func (db *DB) migrateToVersion8() error {
+	log.Debug("DB schema: migrating to v8")
+	tx, err := db.Handle.Beginx()
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	var columns []string
+	err = tx.Select(&columns, `SELECT name FROM pragma_table_info('pending_changes')`)
+	if err != nil {
+		tx.Rollback()
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	existing := make(map[string]bool)
+	for _, c := range columns {
+		existing[c] = true
+	}
+
+	// the journal created by the v4 migration already has the new columns
+	for _, column := range []string{"flags", "mask"} {
+		if existing[column] {
+			continue
+		}
+		_, err = tx.Exec(fmt.Sprintf(
+			`ALTER TABLE pending_changes ADD COLUMN %s INTEGER DEFAULT 0`, column))
+		if err != nil {
+			tx.Rollback()
+			return DBError{DBName: db.Name, Err: err}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	return nil
+}
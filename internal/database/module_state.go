@@ -0,0 +1,94 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// Modules keep small values, like the cursor of a poller, in the
+// `module_state` table of the L2 cache, which is backed up to disk with the
+// bookmarks. The values are kept across restarts of the daemon.
+
+const QCreateModuleState = `
+	CREATE TABLE IF NOT EXISTS module_state (
+		module TEXT NOT NULL,
+		key TEXT NOT NULL,
+		value TEXT NOT NULL,
+		modified INTEGER DEFAULT (strftime('%s')),
+		PRIMARY KEY (module, key)
+	);
+	`
+
+// SetModuleState stores the value of key for module
+func (db *DB) SetModuleState(ctx context.Context, module, key, value string) error {
+	_, err := db.Handle.ExecContext(ctx,
+		`INSERT INTO module_state(module, key, value) VALUES (?, ?, ?)
+		ON CONFLICT(module, key) DO UPDATE SET
+			value = excluded.value,
+			modified = strftime('%s')`,
+		module, key, value)
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	return nil
+}
+
+// GetModuleState returns the value of key for module, it is empty when the
+// value was never set
+func (db *DB) GetModuleState(ctx context.Context, module, key string) (string, error) {
+	var value string
+	err := db.Handle.GetContext(ctx, &value,
+		`SELECT value FROM module_state WHERE module = ? AND key = ?`,
+		module, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	} else if err != nil {
+		return "", DBError{DBName: db.Name, Err: err}
+	}
+	return value, nil
+}
+
+// ModuleState returns the value of key for module from the L2 cache
+func ModuleState(ctx context.Context, module, key string) (string, error) {
+	if L2Cache.DB == nil {
+		return "", errCacheNotInitialized
+	}
+	return L2Cache.GetModuleState(ctx, module, key)
+}
+
+// SetModuleState stores the value of key for module in the L2 cache and
+// schedules a backup to disk
+func SetModuleState(ctx context.Context, module, key, value string) error {
+	if L2Cache.DB == nil {
+		return errCacheNotInitialized
+	}
+
+	if err := L2Cache.SetModuleState(ctx, module, key, value); err != nil {
+		return err
+	}
+	ScheduleBackupToDisk()
+	return nil
+}
@@ -0,0 +1,33 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleState(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDB("test_module_state", "", DBTypeInMemoryDSN).Init()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.InitSchema(ctx))
+
+	value, err := db.GetModuleState(ctx, "reddit", "cursor")
+	require.NoError(t, err)
+	assert.Empty(t, value, "unset values are empty")
+
+	require.NoError(t, db.SetModuleState(ctx, "reddit", "cursor", "t3_a"))
+	require.NoError(t, db.SetModuleState(ctx, "reddit", "cursor", "t3_b"))
+	require.NoError(t, db.SetModuleState(ctx, "lobsters", "cursor", "xyz"))
+
+	value, err = db.GetModuleState(ctx, "reddit", "cursor")
+	require.NoError(t, err)
+	assert.Equal(t, "t3_b", value)
+
+	value, err = db.GetModuleState(ctx, "lobsters", "cursor")
+	require.NoError(t, err)
+	assert.Equal(t, "xyz", value, "values are kept per module")
+}
@@ -0,0 +1,288 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/blob42/gosuki/pkg/bus"
+)
+
+// Metadata fetched from the bookmarked pages is stored in the `page_metadata`
+// table of the L2 cache, which is backed up to disk with the bookmarks. It is
+// used to fill the missing titles and descriptions of the bookmarks and to
+// show favicons in the web UI.
+
+const QCreatePageMetadata = `
+	CREATE TABLE IF NOT EXISTS page_metadata (
+		URL TEXT PRIMARY KEY,
+		title TEXT DEFAULT '',
+		description TEXT DEFAULT '',
+		site_name TEXT DEFAULT '',
+		image TEXT DEFAULT '',
+		canonical TEXT DEFAULT '',
+		favicon TEXT DEFAULT '',
+		status INTEGER DEFAULT 0,
+		error TEXT DEFAULT '',
+		fetched INTEGER NOT NULL
+	);
+	`
+
+// PageMetadata holds the metadata extracted from a bookmarked page
+type PageMetadata struct {
+	URL         string `json:"url" db:"URL"` // bookmark url
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	SiteName    string `json:"site_name" db:"site_name"`
+	Image       string `json:"image"`
+	Canonical   string `json:"canonical"`
+	Favicon     string `json:"favicon"`
+	Status      int    `json:"status"` // http status
+	Error       string `json:"error"`
+	Fetched     int64  `json:"fetched"` // unix time
+}
+
+func (db *DB) UpsertPageMetadata(ctx context.Context, meta *PageMetadata) error {
+	_, err := db.Handle.NamedExecContext(ctx,
+		`INSERT INTO page_metadata(URL, title, description, site_name, image,
+		canonical, favicon, status, error, fetched)
+		VALUES (:URL, :title, :description, :site_name, :image, :canonical,
+		:favicon, :status, :error, :fetched)
+		ON CONFLICT(URL) DO UPDATE SET
+			title = excluded.title,
+			description = excluded.description,
+			site_name = excluded.site_name,
+			image = excluded.image,
+			canonical = excluded.canonical,
+			favicon = excluded.favicon,
+			status = excluded.status,
+			error = excluded.error,
+			fetched = excluded.fetched`,
+		meta,
+	)
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	return nil
+}
+
+// GetPageMetadata returns the stored metadata of the page at url
+func (db *DB) GetPageMetadata(ctx context.Context, url string) (*PageMetadata, error) {
+	meta := &PageMetadata{}
+	err := db.Handle.GetContext(ctx, meta,
+		`SELECT * FROM page_metadata WHERE URL = ?`, url)
+	if err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+	return meta, nil
+}
+
+// URLsToEnrich returns at most limit bookmark urls with a missing title or
+// description whose page was never fetched. Pages that could not be fetched
+// before failedBefore are tried again.
+func (db *DB) URLsToEnrich(ctx context.Context, failedBefore int64, limit int) ([]string, error) {
+	urls := []string{}
+	err := db.Handle.SelectContext(ctx, &urls,
+		`SELECT b.URL FROM gskbookmarks b
+		LEFT JOIN page_metadata p ON p.URL = b.URL
+		WHERE (b.URL LIKE 'http://%' OR b.URL LIKE 'https://%')
+		AND (
+			(b.flags & ? = 0 AND (b.metadata = '' OR b.metadata = b.URL))
+			OR b.desc = ''
+		)
+		AND (p.URL IS NULL OR (p.error != '' AND p.fetched < ?))
+		ORDER BY b.id DESC
+		LIMIT ?`,
+		FlagImmutableTitle, failedBefore, limit,
+	)
+	if err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+	return urls, nil
+}
+
+// Favicons returns the favicon urls of the given pages. Pages without a known
+// favicon are not in the result.
+func (db *DB) Favicons(ctx context.Context, urls []string) (map[string]string, error) {
+	res := make(map[string]string)
+	if len(urls) == 0 {
+		return res, nil
+	}
+
+	query, args, err := sqlx.In(
+		`SELECT URL, favicon FROM page_metadata WHERE favicon != '' AND URL IN (?)`,
+		urls)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Handle.QueryxContext(ctx, db.Handle.Rebind(query), args...)
+	if err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var url, favicon string
+		if err := rows.Scan(&url, &favicon); err != nil {
+			return nil, DBError{DBName: db.Name, Err: err}
+		}
+		res[url] = favicon
+	}
+	return res, rows.Err()
+}
+
+// poorTitle reports whether title carries no information about the page at
+// url
+func poorTitle(title, url string) bool {
+	title = strings.TrimSpace(title)
+	if title == "" || title == url {
+		return true
+	}
+	_, rest, ok := strings.Cut(url, "://")
+	return ok && strings.TrimSuffix(title, "/") == strings.TrimSuffix(rest, "/")
+}
+
+// enrich fills the missing title and description of the bookmark with the
+// page metadata. The title is kept when the bookmark is flagged with
+// [FlagImmutableTitle]. It reports whether the bookmark was changed.
+func enrich(tx *sqlx.Tx, meta *PageMetadata, version uint64) (*RawBookmark, bool, error) {
+	bk := &RawBookmark{}
+	err := tx.Get(bk, `SELECT * FROM gskbookmarks WHERE URL = ?`, meta.URL)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var changed bool
+	if meta.Title != "" && !Flags(bk.Flags).Has(FlagImmutableTitle) &&
+		poorTitle(bk.Metadata, bk.URL) && meta.Title != bk.Metadata {
+		bk.Metadata = meta.Title
+		changed = true
+	}
+	if meta.Description != "" && bk.Desc == "" {
+		bk.Desc = meta.Description
+		changed = true
+	}
+	if !changed {
+		return bk, false, nil
+	}
+
+	bk.XHSum = xhsum(bk.URL, bk.Metadata, bk.Tags, bk.Desc)
+	_, err = tx.Exec(
+		`UPDATE gskbookmarks
+		SET metadata = ?, desc = ?, xhsum = ?, version = ?, modified = strftime('%s')
+		WHERE URL = ?`,
+		bk.Metadata, bk.Desc, bk.XHSum, version, bk.URL,
+	)
+	return bk, err == nil, err
+}
+
+// EnrichBookmark stores the metadata of a fetched page then fills the missing
+// title and description of the bookmark in the caches. An update event is
+// published when the bookmark changed.
+func EnrichBookmark(ctx context.Context, meta *PageMetadata) error {
+	if Cache.DB == nil || L2Cache.DB == nil {
+		return errCacheNotInitialized
+	}
+
+	if err := L2Cache.UpsertPageMetadata(ctx, meta); err != nil {
+		return err
+	}
+
+	var updated *RawBookmark
+	if meta.Error == "" {
+		var err error
+		if updated, err = enrichCaches(ctx, meta); err != nil {
+			return err
+		}
+	}
+	ScheduleBackupToDisk()
+
+	if updated != nil {
+		var ev bookmarkEvents
+		ev.add(bus.BookmarkUpdated, *updated)
+		ev.publish()
+	}
+	return nil
+}
+
+func enrichCaches(ctx context.Context, meta *PageMetadata) (*RawBookmark, error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	version := Clock.LocalTick()
+	var updated *RawBookmark
+	for _, cache := range []*DB{Cache.DB, L2Cache.DB} {
+		tx, err := cache.Handle.BeginTxx(ctx, nil)
+		if err != nil {
+			return nil, DBError{DBName: cache.Name, Err: err}
+		}
+
+		bk, changed, err := enrich(tx, meta, version)
+		if errors.Is(err, sql.ErrNoRows) {
+			// the bookmark was deleted in the meantime
+			tx.Rollback()
+			continue
+		} else if err != nil {
+			tx.Rollback()
+			return nil, DBError{DBName: cache.Name, Err: err}
+		}
+
+		if err = tx.Commit(); err != nil {
+			return nil, DBError{DBName: cache.Name, Err: err}
+		}
+		if changed && cache == Cache.DB {
+			updated = bk
+		}
+	}
+	return updated, nil
+}
+
+// PagesToEnrich returns the urls of the bookmarks to enrich from the L2 cache.
+// See [DB.URLsToEnrich].
+func PagesToEnrich(ctx context.Context, failedBefore int64, limit int) ([]string, error) {
+	if L2Cache.DB == nil {
+		return nil, errCacheNotInitialized
+	}
+	return L2Cache.URLsToEnrich(ctx, failedBefore, limit)
+}
+
+// Favicons returns the known favicons of the pages at urls. Errors are logged
+// and reported as no favicon.
+func Favicons(ctx context.Context, urls []string) map[string]string {
+	if L2Cache.DB == nil {
+		return map[string]string{}
+	}
+
+	favicons, err := L2Cache.Favicons(ctx, urls)
+	if err != nil {
+		log.Error("loading favicons", "err", err)
+		return map[string]string{}
+	}
+	return favicons
+}
@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki/pkg/bus"
+)
+
+func TestPoorTitle(t *testing.T) {
+	assert.True(t, poorTitle("", "https://a.com"))
+	assert.True(t, poorTitle("  ", "https://a.com"))
+	assert.True(t, poorTitle("https://a.com/x", "https://a.com/x"))
+	assert.True(t, poorTitle("a.com/x", "https://a.com/x/"))
+	assert.False(t, poorTitle("A", "https://a.com"))
+}
+
+func TestEnrichBookmark(t *testing.T) {
+	ctx := context.Background()
+	setupPendingTest(t)
+
+	bookmarks := []*Bookmark{
+		{URL: "https://empty.com"},
+		{URL: "https://titled.com", Title: "My title"},
+		{URL: "https://immutable.com", Title: "https://immutable.com"},
+		{URL: "https://complete.com", Title: "Complete", Desc: "described"},
+		{URL: "file:///home/user/doc.html"},
+	}
+	for _, bk := range bookmarks {
+		require.NoError(t, SetCachedBookmark(bk))
+	}
+	for _, cache := range []*DB{Cache.DB, L2Cache.DB} {
+		_, err := cache.Handle.Exec(
+			`UPDATE gskbookmarks SET flags = ?, desc = 'kept' WHERE URL = ?`,
+			FlagImmutableTitle, "https://immutable.com")
+		require.NoError(t, err)
+	}
+
+	urls, err := PagesToEnrich(ctx, 0, 100)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"https://empty.com", "https://titled.com"}, urls,
+		"the immutable title is not missing")
+	for _, url := range testBookmarks {
+		assert.NotContains(t, urls, url.URL)
+	}
+
+	sub := bus.Bookmarks.Subscribe(bus.Options{Name: "test"})
+	defer sub.Close()
+
+	for _, url := range []string{"https://empty.com", "https://titled.com", "https://immutable.com"} {
+		require.NoError(t, EnrichBookmark(ctx, &PageMetadata{
+			URL:         url,
+			Title:       "Page title",
+			Description: "Page description",
+			Favicon:     url + "/favicon.ico",
+			Status:      200,
+			Fetched:     100,
+		}))
+	}
+
+	for _, cache := range []*DB{Cache.DB, L2Cache.DB} {
+		var got []RawBookmark
+		require.NoError(t, cache.Handle.Select(&got,
+			`SELECT * FROM gskbookmarks WHERE URL IN (?, ?, ?) ORDER BY URL`,
+			"https://empty.com", "https://immutable.com", "https://titled.com"))
+		require.Len(t, got, 3)
+
+		assert.Equal(t, "Page title", got[0].Metadata, cache.Name)
+		assert.Equal(t, "Page description", got[0].Desc, cache.Name)
+		assert.Equal(t, xhsum(got[0].URL, got[0].Metadata, got[0].Tags, got[0].Desc), got[0].XHSum)
+
+		assert.Equal(t, "https://immutable.com", got[1].Metadata, "immutable title")
+		assert.Equal(t, "kept", got[1].Desc)
+
+		assert.Equal(t, "My title", got[2].Metadata)
+		assert.Equal(t, "Page description", got[2].Desc)
+	}
+
+	var events []bus.BookmarkEvent
+	for range 2 {
+		events = append(events, <-sub.C)
+	}
+	assert.Equal(t, bus.BookmarkUpdated, events[0].Type)
+	assert.Equal(t, "https://empty.com", events[0].Bookmark.URL)
+	assert.Equal(t, "Page title", events[0].Bookmark.Title)
+	assert.Equal(t, "https://titled.com", events[1].Bookmark.URL)
+	assert.Zero(t, sub.Len(), "unchanged bookmarks are not published")
+
+	// fetched pages are not returned again, failed ones are retried later
+	require.NoError(t, EnrichBookmark(ctx, &PageMetadata{
+		URL:     "https://immutable.com",
+		Error:   "404 Not Found",
+		Status:  404,
+		Fetched: 100,
+	}))
+	require.NoError(t, L2Cache.DB.UpsertPageMetadata(ctx, &PageMetadata{URL: "https://titled.com", Error: "timeout", Fetched: 100}))
+	_, err = Cache.Handle.Exec(`UPDATE gskbookmarks SET desc = '' WHERE URL = 'https://titled.com'`)
+	require.NoError(t, err)
+	_, err = L2Cache.Handle.Exec(`UPDATE gskbookmarks SET desc = '' WHERE URL = 'https://titled.com'`)
+	require.NoError(t, err)
+
+	urls, err = PagesToEnrich(ctx, 50, 100)
+	require.NoError(t, err)
+	assert.Empty(t, urls)
+	urls, err = PagesToEnrich(ctx, 200, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://titled.com"}, urls)
+
+	favicons := Favicons(ctx, []string{"https://empty.com", "https://immutable.com", "https://none.com"})
+	assert.Equal(t, map[string]string{"https://empty.com": "https://empty.com/favicon.ico"}, favicons)
+
+	meta, err := L2Cache.GetPageMetadata(ctx, "https://immutable.com")
+	require.NoError(t, err)
+	assert.Equal(t, 404, meta.Status)
+	assert.Empty(t, meta.Favicon)
+}
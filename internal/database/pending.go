@@ -0,0 +1,386 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/blob42/gosuki/pkg/bus"
+)
+
+// The daemon keeps the bookmarks in its in-memory caches and periodically
+// overwrites gosuki.db with the content of the L2 cache (see
+// [cacheSyncScheduler]). Programs writing directly to gosuki.db while the
+// daemon is running would see their changes lost on the next backup.
+//
+// To avoid this, direct writes to the on-disk db are also recorded in the
+// `pending_changes` table. Before every backup, the daemon replays the journal
+// onto its caches and clears it. Writers and the daemon coordinate using
+// [LockDisk].
+
+const QCreatePendingChanges = `
+	CREATE TABLE IF NOT EXISTS pending_changes (
+		id INTEGER PRIMARY KEY,
+		op TEXT NOT NULL,
+		URL TEXT NOT NULL,
+		metadata TEXT DEFAULT '',
+		tags TEXT DEFAULT '',
+		desc TEXT DEFAULT '',
+		module TEXT DEFAULT '',
+		flags INTEGER DEFAULT 0,
+		mask INTEGER DEFAULT 0,
+		target TEXT DEFAULT '',
+		created INTEGER DEFAULT (strftime('%s')),
+		created_at INTEGER DEFAULT 0,
+		queued_at INTEGER DEFAULT 0,
+		read_at INTEGER DEFAULT 0
+	)
+	`
+
+type ChangeOp string
+
+const (
+	OpSet    ChangeOp = "set"
+	OpDelete ChangeOp = "delete"
+
+	// sets the bits of Mask in the flags of a bookmark to the bits of Flags
+	OpFlags ChangeOp = "flags"
+
+	// merges a bookmark into the bookmark at Target and deletes it
+	OpMerge ChangeOp = "merge"
+
+	// merges a bookmark imported from another program, see [ImportBookmarks]
+	OpImport ChangeOp = "import"
+
+	// sets the read later state of a bookmark and the times it was queued
+	// and read
+	OpReadState ChangeOp = "read-state"
+)
+
+var ErrBookmarkNotFound = errors.New("bookmark not found")
+
+// PendingChange is a change made to the on-disk db that has not yet been
+// applied to the daemon caches.
+type PendingChange struct {
+	ID       uint64
+	Op       ChangeOp
+	URL      string `db:"URL"`
+	Metadata string
+	Tags     string
+	Desc     string
+	Module   string
+	Flags    Flags
+	Mask     Flags
+	Target   string
+	Created  uint64
+
+	// creation time of an imported bookmark
+	CreatedAt uint64 `db:"created_at"`
+
+	// read later times of an imported bookmark
+	QueuedAt uint64 `db:"queued_at"`
+	ReadAt   uint64 `db:"read_at"`
+}
+
+const (
+	// sets the exact title, tags and description of a bookmark
+	qSetBookmark = `
+	INSERT INTO gskbookmarks(URL, metadata, tags, desc, module, xhsum, version, canonical)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(URL) DO UPDATE SET
+		metadata = excluded.metadata,
+		tags = excluded.tags,
+		desc = excluded.desc,
+		modified = strftime('%s'),
+		xhsum = excluded.xhsum,
+		version = excluded.version
+	`
+
+	qNextDiskVersion = `SELECT COALESCE(max(version),0)+1 FROM gskbookmarks`
+)
+
+func (c *PendingChange) apply(tx *sqlx.Tx, version uint64) error {
+	var err error
+	switch c.Op {
+	case OpSet:
+		_, err = tx.Exec(qSetBookmark,
+			c.URL,
+			c.Metadata,
+			c.Tags,
+			c.Desc,
+			c.Module,
+			xhsum(c.URL, c.Metadata, c.Tags, c.Desc),
+			version,
+			CanonicalURL(c.URL),
+		)
+		if err == nil {
+			err = addSource(tx, c.URL, c.Module, 0, c.Created)
+		}
+	case OpDelete:
+		_, err = tx.Exec(`DELETE FROM gskbookmarks WHERE URL = ?`, c.URL)
+	case OpFlags:
+		err = setFlags(tx, c.URL, c.Flags, c.Mask, version)
+	case OpMerge:
+		err = mergeBookmark(tx, c.URL, c.Target, version)
+	case OpImport:
+		err = importBookmark(tx, c, version)
+	case OpReadState:
+		err = setReadState(tx, c.URL, c.Flags.Has(FlagReadLater), c.QueuedAt, c.ReadAt, version)
+	default:
+		err = fmt.Errorf("unknown change op: %s", c.Op)
+	}
+	return err
+}
+
+// lockPath returns the path of the db file used for the disk lock
+func lockPath() string {
+	if DiskDB == nil {
+		return ""
+	}
+	return DiskDB.filePath
+}
+
+// writeDisk applies the changes to the on-disk db and records them in the
+// journal in a single transaction.
+func writeDisk(ctx context.Context, changes ...*PendingChange) error {
+	if DiskDB == nil || DiskDB.Handle == nil {
+		return errors.New("disk db is not initialized")
+	}
+
+	lock, err := LockDisk(lockPath())
+	if err != nil {
+		return fmt.Errorf("locking db: %w", err)
+	}
+	defer lock.Unlock()
+
+	tx, err := DiskDB.Handle.BeginTxx(ctx, nil)
+	if err != nil {
+		return DBError{DBName: DiskDB.Name, Err: err}
+	}
+
+	// The journal is created by the daemon when migrating the db. Make sure
+	// it exists in case the daemon was never upgraded.
+	if _, err = tx.ExecContext(ctx, QCreatePendingChanges); err != nil {
+		tx.Rollback()
+		return DBError{DBName: DiskDB.Name, Err: err}
+	}
+
+	var version uint64
+	if err = tx.GetContext(ctx, &version, qNextDiskVersion); err != nil {
+		tx.Rollback()
+		return DBError{DBName: DiskDB.Name, Err: err}
+	}
+
+	for _, change := range changes {
+		if err = change.apply(tx, version); err != nil {
+			tx.Rollback()
+			return DBError{DBName: DiskDB.Name, Err: err}
+		}
+
+		if _, err = tx.ExecContext(ctx,
+			`INSERT INTO pending_changes(op, URL, metadata, tags, desc, module, flags, mask, target,
+				created_at, queued_at, read_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			change.Op,
+			change.URL,
+			change.Metadata,
+			change.Tags,
+			change.Desc,
+			change.Module,
+			change.Flags,
+			change.Mask,
+			change.Target,
+			change.CreatedAt,
+			change.QueuedAt,
+			change.ReadAt,
+		); err != nil {
+			tx.Rollback()
+			return DBError{DBName: DiskDB.Name, Err: err}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return DBError{DBName: DiskDB.Name, Err: err}
+	}
+
+	return nil
+}
+
+// SetBookmark writes bk to the on-disk db. Unlike [DB.UpsertBookmark], the
+// title, tags and description of an existing bookmark are replaced instead of
+// merged. It is safe to call while the daemon is running.
+func SetBookmark(ctx context.Context, bk *Bookmark) error {
+	return writeDisk(ctx, newSetChange(bk))
+}
+
+// RemoveBookmark deletes the bookmark with the given URL from the on-disk db.
+// It is safe to call while the daemon is running.
+func RemoveBookmark(ctx context.Context, url string) error {
+	return writeDisk(ctx, &PendingChange{Op: OpDelete, URL: url})
+}
+
+// GetBookmarkByID returns the bookmark with the given id from the on-disk db
+func GetBookmarkByID(ctx context.Context, id uint64) (*Bookmark, error) {
+	return DiskDB.BookmarkByID(ctx, id)
+}
+
+func (db *DB) BookmarkByID(ctx context.Context, id uint64) (*Bookmark, error) {
+	raws := RawBookmarks{}
+	err := db.Handle.SelectContext(ctx, &raws,
+		`SELECT * FROM gskbookmarks WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(raws) == 0 {
+		return nil, ErrBookmarkNotFound
+	}
+
+	return raws.AsBookmarks()[0], nil
+}
+
+// ApplyPendingChanges replays the journal of the on-disk db onto the L1 and L2
+// caches then clears it. It must be called while holding the disk lock, right
+// before backing up the L2 cache to disk.
+func ApplyPendingChanges() error {
+	var changes []*PendingChange
+
+	if DiskDB == nil || DiskDB.Handle == nil {
+		return nil
+	}
+
+	err := DiskDB.Handle.Select(&changes, `SELECT * FROM pending_changes ORDER BY id`)
+	if err != nil && err != sql.ErrNoRows {
+		return DBError{DBName: DiskDB.Name, Err: err}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	log.Debug("applying pending changes", "count", len(changes))
+
+	if err = applyToCaches(changes); err != nil {
+		return err
+	}
+
+	_, err = DiskDB.Handle.Exec(`DELETE FROM pending_changes WHERE id <= ?`,
+		changes[len(changes)-1].ID)
+	if err != nil {
+		return DBError{DBName: DiskDB.Name, Err: err}
+	}
+
+	return nil
+}
+
+// clearPendingChanges empties the journal on disk and in the caches
+func clearPendingChanges() error {
+	for _, db := range []*DB{DiskDB, Cache.DB, L2Cache.DB} {
+		if _, err := db.Handle.Exec(`DELETE FROM pending_changes`); err != nil {
+			return DBError{DBName: db.Name, Err: err}
+		}
+	}
+	return nil
+}
+
+// applyToCaches applies the changes to the L1 and L2 caches and clears their
+// copy of the journal.
+func applyToCaches(changes []*PendingChange) error {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	for _, cache := range []*DB{Cache.DB, L2Cache.DB} {
+		tx, err := cache.Handle.Beginx()
+		if err != nil {
+			return DBError{DBName: cache.Name, Err: err}
+		}
+
+		for _, change := range changes {
+			if err = change.apply(tx, Clock.LocalTick()); err != nil {
+				log.Error("applying change", "op", change.Op, "url", change.URL, "err", err)
+			}
+		}
+
+		// The L2 cache holds a copy of the journal loaded from disk at startup.
+		// Clear it or it would be written back on the next backup.
+		if _, err = tx.Exec(`DELETE FROM pending_changes`); err != nil {
+			tx.Rollback()
+			return DBError{DBName: cache.Name, Err: err}
+		}
+
+		if err = tx.Commit(); err != nil {
+			return DBError{DBName: cache.Name, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func newSetChange(bk *Bookmark) *PendingChange {
+	return &PendingChange{
+		Op:       OpSet,
+		URL:      bk.URL,
+		Metadata: bk.Title,
+		Tags:     NewTags(bk.Tags, TagSep).PreSanitize().Sort().String(true),
+		Desc:     bk.Desc,
+		Module:   bk.Module,
+	}
+}
+
+// SetCachedBookmark is the daemon side equivalent of [SetBookmark]. The
+// bookmark is written to the caches and a backup to disk is scheduled.
+func SetCachedBookmark(bk *Bookmark) error {
+	evType := bus.BookmarkAdded
+	var id uint64
+	if err := Cache.Handle.Get(&id, `SELECT id FROM gskbookmarks WHERE url = ?`, bk.URL); err == nil {
+		evType = bus.BookmarkUpdated
+	}
+
+	if err := applyToCaches([]*PendingChange{newSetChange(bk)}); err != nil {
+		return err
+	}
+	ScheduleBackupToDisk()
+
+	published := *bk
+	published.ID = id
+	bus.Bookmarks.Publish(bus.BookmarkEvent{Type: evType, Bookmark: &published})
+	return nil
+}
+
+// RemoveCachedBookmark is the daemon side equivalent of [RemoveBookmark].
+func RemoveCachedBookmark(url string) error {
+	if err := applyToCaches([]*PendingChange{{Op: OpDelete, URL: url}}); err != nil {
+		return err
+	}
+	ScheduleBackupToDisk()
+
+	bus.Bookmarks.Publish(bus.BookmarkEvent{
+		Type:     bus.BookmarkDeleted,
+		Bookmark: &Bookmark{URL: url},
+	})
+	return nil
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPendingTest(t *testing.T) {
+	Clock = &LamportClock{}
+	dbPath := t.TempDir() + "/gosuki.db"
+
+	mem, err := NewDB("test_db", "", DBTypeInMemoryDSN).Init()
+	require.NoError(t, err)
+	require.NoError(t, mem.BackupToDisk(dbPath))
+
+	DiskDB, err = NewDB("gosuki_db", dbPath, DBTypeFileDSN).Init()
+	require.NoError(t, err)
+	require.NoError(t, DiskDB.InitSchema(context.Background()))
+
+	Cache.DB = getCache(t, CacheName)
+	L2Cache.DB = getCache(t, L2CacheName)
+
+	t.Cleanup(func() {
+		DiskDB.Close()
+		Cache.DB.Close()
+		L2Cache.DB.Close()
+		DiskDB, Cache.DB, L2Cache.DB = nil, nil, nil
+	})
+}
+
+func TestPendingChanges(t *testing.T) {
+	ctx := context.Background()
+	setupPendingTest(t)
+
+	// replace the existing bookmark and add a new one
+	err := SetBookmark(ctx, &Bookmark{
+		URL:   testBookmarks[0].URL,
+		Title: "new title",
+		Tags:  []string{"foo"},
+	})
+	require.NoError(t, err)
+
+	err = SetBookmark(ctx, &Bookmark{
+		URL:    "https://new.example.com",
+		Tags:   []string{"bar", "baz"},
+		Module: "suki",
+	})
+	require.NoError(t, err)
+
+	err = RemoveBookmark(ctx, testBookmarks[1].URL)
+	require.NoError(t, err)
+
+	var count int
+	require.NoError(t, DiskDB.Handle.Get(&count, `SELECT count(*) FROM pending_changes`))
+	assert.Equal(t, 3, count)
+
+	require.NoError(t, ApplyPendingChanges())
+
+	require.NoError(t, DiskDB.Handle.Get(&count, `SELECT count(*) FROM pending_changes`))
+	assert.Equal(t, 0, count, "journal should be cleared")
+
+	for _, cache := range []*DB{Cache.DB, L2Cache.DB} {
+		var bk RawBookmark
+		err = cache.Handle.Get(&bk, `SELECT * FROM gskbookmarks WHERE URL = ?`,
+			testBookmarks[0].URL)
+		require.NoError(t, err, cache.Name)
+		assert.Equal(t, "new title", bk.Metadata, cache.Name)
+		assert.Equal(t, ",foo,", bk.Tags, "tags must be replaced, not merged")
+
+		err = cache.Handle.Get(&bk, `SELECT * FROM gskbookmarks WHERE URL = ?`,
+			"https://new.example.com")
+		require.NoError(t, err, cache.Name)
+		assert.Equal(t, ",bar,baz,", bk.Tags)
+
+		err = cache.Handle.Get(&count, `SELECT count(*) FROM gskbookmarks WHERE URL = ?`,
+			testBookmarks[1].URL)
+		require.NoError(t, err)
+		assert.Zero(t, count, "bookmark should be removed from %s", cache.Name)
+
+		err = cache.Handle.Get(&count, `SELECT count(*) FROM pending_changes`)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	}
+}
+
+func TestGetBookmarkByID(t *testing.T) {
+	ctx := context.Background()
+	setupPendingTest(t)
+
+	require.NoError(t, SetBookmark(ctx, &Bookmark{URL: "https://example.org"}))
+
+	var id uint64
+	require.NoError(t, DiskDB.Handle.Get(&id,
+		`SELECT id FROM gskbookmarks WHERE URL = ?`, "https://example.org"))
+
+	bk, err := GetBookmarkByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.org", bk.URL)
+	assert.Equal(t, id, bk.ID)
+
+	_, err = GetBookmarkByID(ctx, id+1)
+	assert.ErrorIs(t, err, ErrBookmarkNotFound)
+}
@@ -47,6 +47,9 @@ const (
 	`
 
 	QQueryPaginate = ` LIMIT %d OFFSET %d`
+
+	// pinned bookmarks are listed first, 2 is the bit of FlagPinned
+	QOrderPinnedFirst = ` ORDER BY flags & 2 DESC, id`
 )
 
 type PaginationParams struct {
@@ -68,24 +71,75 @@ func (raws RawBookmarks) AsBookmarks() []*gosuki.Bookmark {
 	for _, raw := range raws {
 		tags := tagsFromString(raw.Tags, TagSep)
 		res = append(res, &Bookmark{
+			ID:       raw.ID,
 			URL:      raw.URL,
 			Title:    raw.Metadata,
 			Tags:     tags.Get(),
 			Desc:     raw.Desc,
 			Module:   raw.Module,
 			Modified: raw.Modified,
+			Flags:    raw.Flags,
+			Created:  raw.CreatedAt,
+			QueuedAt: raw.QueuedAt,
+			ReadAt:   raw.ReadAt,
 		})
 	}
 
 	return res
 }
 
+// QueryBookmarksByTag runs [DB.QueryBookmarksByTag] on the on-disk db
 func QueryBookmarksByTag(
 	ctx context.Context,
 	query,
 	tag string,
 	fuzzy bool,
 	pagination *PaginationParams,
+) (*QueryResult, error) {
+	return DiskDB.QueryBookmarksByTag(ctx, query, tag, fuzzy, pagination)
+}
+
+// QueryBookmarks runs [DB.QueryBookmarks] on the on-disk db
+func QueryBookmarks(
+	ctx context.Context,
+	query string,
+	fuzzy bool,
+	pagination *PaginationParams,
+) (*QueryResult, error) {
+	return DiskDB.QueryBookmarks(ctx, query, fuzzy, pagination)
+}
+
+// BookmarksByTag runs [DB.BookmarksByTag] on the on-disk db
+func BookmarksByTag(
+	ctx context.Context,
+	tag string,
+	pagination *PaginationParams,
+) (*QueryResult, error) {
+	return DiskDB.BookmarksByTag(ctx, tag, pagination)
+}
+
+// ListBookmarks runs [DB.ListBookmarks] on the on-disk db
+func ListBookmarks(
+	ctx context.Context,
+	pagination *PaginationParams,
+) (*QueryResult, error) {
+	return DiskDB.ListBookmarks(ctx, pagination)
+}
+
+// ArchivedBookmarks runs [DB.ArchivedBookmarks] on the on-disk db
+func ArchivedBookmarks(
+	ctx context.Context,
+	pagination *PaginationParams,
+) (*QueryResult, error) {
+	return DiskDB.ArchivedBookmarks(ctx, pagination)
+}
+
+func (db *DB) QueryBookmarksByTag(
+	ctx context.Context,
+	query,
+	tag string,
+	fuzzy bool,
+	pagination *PaginationParams,
 ) (*QueryResult, error) {
 	query = strings.TrimSpace(query)
 	tag = strings.TrimSpace(tag)
@@ -101,13 +155,13 @@ func QueryBookmarksByTag(
 	sqlQuery := buildSelectQuery(query, fuzzy, tag, pagination)
 
 	rawBooks := RawBookmarks{}
-	err := DiskDB.Handle.SelectContext(ctx, &rawBooks, sqlQuery)
+	err := db.Handle.SelectContext(ctx, &rawBooks, sqlQuery)
 	if err != nil {
 		return nil, err
 	}
 
 	var total uint
-	err = DiskDB.Handle.GetContext(ctx, &total,
+	err = db.Handle.GetContext(ctx, &total,
 		fmt.Sprintf(buildCountQuery(tag, fuzzy), query, query, query))
 	if err != nil {
 		return nil, err
@@ -116,7 +170,7 @@ func QueryBookmarksByTag(
 	return &QueryResult{rawBooks.AsBookmarks(), total}, nil
 }
 
-func QueryBookmarks(
+func (db *DB) QueryBookmarks(
 	ctx context.Context,
 	query string,
 	fuzzy bool,
@@ -130,13 +184,13 @@ func QueryBookmarks(
 	sqlQuery := buildSelectQuery(query, fuzzy, "", pagination)
 
 	rawBooks := RawBookmarks{}
-	err := DiskDB.Handle.SelectContext(ctx, &rawBooks, sqlQuery)
+	err := db.Handle.SelectContext(ctx, &rawBooks, sqlQuery)
 	if err != nil {
 		return nil, err
 	}
 
 	var total uint
-	err = DiskDB.Handle.GetContext(ctx, &total,
+	err = db.Handle.GetContext(ctx, &total,
 		fmt.Sprintf(buildCountQuery("", fuzzy), query, query, query))
 	if err != nil {
 		return nil, err
@@ -145,7 +199,7 @@ func QueryBookmarks(
 	return &QueryResult{rawBooks.AsBookmarks(), total}, nil
 }
 
-func BookmarksByTag(
+func (db *DB) BookmarksByTag(
 	ctx context.Context,
 	tag string,
 	pagination *PaginationParams,
@@ -158,17 +212,17 @@ func BookmarksByTag(
 		return nil, errors.New("empty tag provided")
 	}
 
-	query = query + " (" + tagsCondition + ")"
+	query = query + " (" + tagsCondition + ")" + QOrderPinnedFirst
 	query += fmt.Sprintf(" "+QQueryPaginate, pagination.Size, (pagination.Page-1)*pagination.Size)
 
 	rawBooks := RawBookmarks{}
-	err := DiskDB.Handle.SelectContext(ctx, &rawBooks, query)
+	err := db.Handle.SelectContext(ctx, &rawBooks, query)
 	if err != nil {
 		return nil, err
 	}
 
 	var count uint
-	err = DiskDB.Handle.GetContext(
+	err = db.Handle.GetContext(
 		ctx,
 		&count,
 		fmt.Sprintf("SELECT COUNT(*) FROM gskbookmarks WHERE %s", tagsCondition),
@@ -180,15 +234,43 @@ func BookmarksByTag(
 	return &QueryResult{rawBooks.AsBookmarks(), count}, nil
 }
 
-func ListBookmarks(
+// ListBookmarks lists the bookmarks, pinned first. Archived bookmarks are
+// only listed by [DB.ArchivedBookmarks] and [DB.AllBookmarks].
+func (db *DB) ListBookmarks(
 	ctx context.Context,
 	pagination *PaginationParams,
+) (*QueryResult, error) {
+	return db.listBookmarks(ctx, fmt.Sprintf("flags & %d = 0", FlagArchived), pagination)
+}
+
+// ArchivedBookmarks lists the archived bookmarks, pinned first
+func (db *DB) ArchivedBookmarks(
+	ctx context.Context,
+	pagination *PaginationParams,
+) (*QueryResult, error) {
+	return db.listBookmarks(ctx, fmt.Sprintf("flags & %d != 0", FlagArchived), pagination)
+}
+
+// AllBookmarks lists the bookmarks including the archived ones, pinned first
+func (db *DB) AllBookmarks(
+	ctx context.Context,
+	pagination *PaginationParams,
+) (*QueryResult, error) {
+	return db.listBookmarks(ctx, "1", pagination)
+}
+
+func (db *DB) listBookmarks(
+	ctx context.Context,
+	where string,
+	pagination *PaginationParams,
 ) (*QueryResult, error) {
 	rawBooks := RawBookmarks{}
-	err := DiskDB.Handle.SelectContext(
+	err := db.Handle.SelectContext(
 		ctx,
 		&rawBooks,
-		fmt.Sprintf("SELECT * FROM gskbookmarks LIMIT %d OFFSET %d",
+		fmt.Sprintf("SELECT * FROM gskbookmarks WHERE %s%s LIMIT %d OFFSET %d",
+			where,
+			QOrderPinnedFirst,
 			pagination.Size,
 			(pagination.Page-1)*pagination.Size,
 		),
@@ -197,7 +279,9 @@ func ListBookmarks(
 		return nil, err
 	}
 
-	total, err := CountTotalBookmarks(ctx)
+	var total uint
+	err = db.Handle.GetContext(ctx, &total,
+		fmt.Sprintf("SELECT COUNT(*) FROM gskbookmarks WHERE %s", where))
 	if err != nil {
 		return nil, fmt.Errorf("counting urls: %w", err)
 	}
@@ -238,15 +322,16 @@ func buildSelectQuery(
 	}
 
 	sqlPrelude := `
-		SELECT URL, metadata, tags, module
+		SELECT id, URL, metadata, tags, desc, module, flags
 		FROM gskbookmarks
 		WHERE 
 	`
 
 	sqlQuery := fmt.Sprintf(
-		"%s %s %s",
+		"%s %s %s %s",
 		sqlPrelude,
 		buildWhereClause(tag, fuzzy),
+		QOrderPinnedFirst,
 		QQueryPaginate,
 	)
 
@@ -0,0 +1,176 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// The read later queue is made of the bookmarks flagged with [FlagReadLater].
+// Marking a bookmark as read clears the flag. The `read_later` table keeps the
+// time a bookmark was queued and read. It is maintained by triggers on the
+// flags of the bookmarks, which test the bit of FlagReadLater (8), so the
+// state is the same on all the databases the flags are synced to.
+
+const QCreateReadLater = `
+	CREATE TABLE IF NOT EXISTS read_later (
+		URL TEXT PRIMARY KEY,
+		queued_at INTEGER NOT NULL,
+		read_at INTEGER DEFAULT 0
+	);
+
+	CREATE TRIGGER IF NOT EXISTS read_later_insert
+	AFTER INSERT ON gskbookmarks
+	WHEN new.flags & 8
+	BEGIN
+		INSERT INTO read_later(URL, queued_at, read_at)
+		VALUES (new.URL, strftime('%s'), 0)
+		ON CONFLICT(URL) DO UPDATE SET
+			queued_at = excluded.queued_at,
+			read_at = 0;
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS read_later_update
+	AFTER UPDATE OF flags ON gskbookmarks
+	WHEN new.flags & 8 != old.flags & 8
+	BEGIN
+		INSERT INTO read_later(URL, queued_at, read_at)
+		VALUES (
+			new.URL,
+			strftime('%s'),
+			CASE WHEN new.flags & 8 THEN 0 ELSE strftime('%s') END
+		)
+		ON CONFLICT(URL) DO UPDATE SET
+			queued_at = CASE WHEN new.flags & 8 THEN excluded.queued_at ELSE queued_at END,
+			read_at = excluded.read_at;
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS read_later_delete
+	AFTER DELETE ON gskbookmarks
+	BEGIN
+		DELETE FROM read_later WHERE URL = old.URL;
+	END
+	`
+
+const qSelectReadLater = `
+	SELECT b.*, COALESCE(r.queued_at, b.modified) AS queued_at, COALESCE(r.read_at, 0) AS read_at
+	FROM gskbookmarks b
+	LEFT JOIN read_later r ON r.URL = b.URL
+	`
+
+// ReadLater runs [DB.ReadLater] on the on-disk db
+func ReadLater(ctx context.Context, read bool, pagination *PaginationParams) (*QueryResult, error) {
+	return DiskDB.ReadLater(ctx, read, pagination)
+}
+
+// ReadLater returns the unread bookmarks of the read later queue, most
+// recently queued first. When read is true, it returns the bookmarks that were
+// read, most recently read first.
+func (db *DB) ReadLater(ctx context.Context, read bool, pagination *PaginationParams) (*QueryResult, error) {
+	where := `WHERE b.flags & ? != 0`
+	order := `ORDER BY queued_at DESC, b.id DESC`
+	if read {
+		where = `WHERE b.flags & ? = 0 AND r.read_at > 0`
+		order = `ORDER BY read_at DESC, b.id DESC`
+	}
+
+	rawBooks := RawBookmarks{}
+	err := db.Handle.SelectContext(ctx, &rawBooks,
+		fmt.Sprintf("%s %s %s"+QQueryPaginate, qSelectReadLater, where, order,
+			pagination.Size,
+			(pagination.Page-1)*pagination.Size,
+		),
+		FlagReadLater,
+	)
+	if err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+
+	var total uint
+	err = db.Handle.GetContext(ctx, &total,
+		`SELECT COUNT(*) FROM gskbookmarks b LEFT JOIN read_later r ON r.URL = b.URL `+where,
+		FlagReadLater,
+	)
+	if err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+
+	return &QueryResult{rawBooks.AsBookmarks(), total}, nil
+}
+
+// setReadState sets the read later state of the bookmark with the given url
+// and the times it was queued and read. Unread bookmarks are flagged with
+// [FlagReadLater].
+func setReadState(tx *sqlx.Tx, url string, unread bool, queued, read, version uint64) error {
+	var flags Flags
+	if unread {
+		flags = FlagReadLater
+		read = 0
+	}
+
+	if err := setFlags(tx, url, flags, FlagReadLater, version); err != nil {
+		return err
+	}
+
+	// replace the times set by the triggers
+	_, err := tx.Exec(
+		`INSERT INTO read_later(URL, queued_at, read_at) VALUES (?, ?, ?)
+		ON CONFLICT(URL) DO UPDATE SET
+			queued_at = excluded.queued_at,
+			read_at = excluded.read_at`,
+		url, queued, read,
+	)
+	return err
+}
+
+// SetReadState sets the read later state of the bookmark with the given url
+// in the on-disk db, see [setReadState]. It is safe to call while the daemon
+// is running.
+func SetReadState(ctx context.Context, url string, unread bool, queued, read time.Time) error {
+	return writeDisk(ctx, newReadStateChange(url, unread, queued, read))
+}
+
+func newReadStateChange(url string, unread bool, queued, read time.Time) *PendingChange {
+	change := &PendingChange{
+		Op:       OpReadState,
+		URL:      url,
+		QueuedAt: unixTime(queued),
+		ReadAt:   unixTime(read),
+	}
+	if unread {
+		change.Flags = FlagReadLater
+	}
+	return change
+}
+
+// wasQueued reports whether the bookmark with the given url has ever been in
+// the read later queue
+func wasQueued(tx *sqlx.Tx, url string) bool {
+	var queued int
+	err := tx.Get(&queued, `SELECT 1 FROM read_later WHERE URL = ?`, url)
+	return err == nil
+}
@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readLaterTimes(t *testing.T, db *DB, url string) (queued, read int64) {
+	t.Helper()
+	require.NoError(t, db.Handle.QueryRow(
+		`SELECT queued_at, read_at FROM read_later WHERE URL = ?`, url,
+	).Scan(&queued, &read))
+	return queued, read
+}
+
+func TestReadLaterTriggers(t *testing.T) {
+	// the triggers test the bit of FlagReadLater
+	require.Equal(t, Flags(8), FlagReadLater)
+
+	setupPendingTest(t)
+	url := testBookmarks[0].URL
+
+	_, err := SetCachedFlags(url, FlagReadLater, 0)
+	require.NoError(t, err)
+	for _, cache := range []*DB{Cache.DB, L2Cache.DB} {
+		queued, read := readLaterTimes(t, cache, url)
+		assert.NotZero(t, queued, cache.Name)
+		assert.Zero(t, read, cache.Name)
+	}
+
+	// other flags do not change the queue
+	_, err = Cache.Handle.Exec(`UPDATE read_later SET queued_at = 1 WHERE URL = ?`, url)
+	require.NoError(t, err)
+	_, err = SetCachedFlags(url, FlagPinned, 0)
+	require.NoError(t, err)
+	queued, _ := readLaterTimes(t, Cache.DB, url)
+	assert.Equal(t, int64(1), queued)
+
+	// marking as read keeps the queue time
+	_, err = SetCachedFlags(url, 0, FlagReadLater)
+	require.NoError(t, err)
+	queued, read := readLaterTimes(t, Cache.DB, url)
+	assert.Equal(t, int64(1), queued)
+	assert.NotZero(t, read)
+
+	// deleted bookmarks leave the queue
+	require.NoError(t, RemoveCachedBookmark(url))
+	var count int
+	require.NoError(t, Cache.Handle.Get(&count, `SELECT COUNT(*) FROM read_later`))
+	assert.Zero(t, count)
+}
+
+func TestReadLaterQueue(t *testing.T) {
+	ctx := context.Background()
+	setupPendingTest(t)
+	all := &PaginationParams{Page: 1, Size: -1}
+
+	for _, raw := range testBookmarks[:3] {
+		require.NoError(t, DiskDB.UpsertBookmark(&Bookmark{URL: raw.URL, Title: raw.Metadata}))
+	}
+	for i, raw := range testBookmarks[:3] {
+		require.NoError(t, SetReadState(ctx, raw.URL, i != 1,
+			time.Unix(int64(100+i), 0), time.Unix(int64(200+i), 0)))
+	}
+
+	res, err := DiskDB.ReadLater(ctx, false, all)
+	require.NoError(t, err)
+	require.Equal(t, uint(2), res.Total)
+	assert.Equal(t, testBookmarks[2].URL, res.Bookmarks[0].URL)
+	assert.Equal(t, uint64(102), res.Bookmarks[0].QueuedAt)
+	assert.Zero(t, res.Bookmarks[0].ReadAt)
+	assert.Equal(t, testBookmarks[0].URL, res.Bookmarks[1].URL)
+
+	res, err = DiskDB.ReadLater(ctx, true, all)
+	require.NoError(t, err)
+	require.Equal(t, uint(1), res.Total)
+	assert.Equal(t, testBookmarks[1].URL, res.Bookmarks[0].URL)
+	assert.Equal(t, uint64(101), res.Bookmarks[0].QueuedAt)
+	assert.Equal(t, uint64(201), res.Bookmarks[0].ReadAt)
+
+	err = SetReadState(ctx, "https://missing.example.com", true, time.Now(), time.Time{})
+	assert.ErrorIs(t, err, ErrBookmarkNotFound)
+
+	// the journal is replayed on the caches
+	require.NoError(t, L2Cache.UpsertBookmark(&Bookmark{URL: testBookmarks[1].URL}))
+	require.NoError(t, ApplyPendingChanges())
+	res, err = L2Cache.ReadLater(ctx, true, all)
+	require.NoError(t, err)
+	require.Equal(t, uint(1), res.Total)
+	assert.Equal(t, uint64(201), res.Bookmarks[0].ReadAt)
+}
+
+func TestSyncQueuesReadLaterOnce(t *testing.T) {
+	setupPendingTest(t)
+	url := "https://toread.example.com"
+
+	buffer := getBuffer(t)
+	defer buffer.Close()
+	capture := func(flags Flags) {
+		require.NoError(t, buffer.UpsertBookmark(&Bookmark{
+			URL:    url,
+			Tags:   []string{"toread"},
+			Flags:  int(flags),
+			Module: "test",
+		}))
+		buffer.SyncTo(Cache.DB)
+		Cache.SyncTo(L2Cache.DB)
+	}
+
+	capture(FlagReadLater)
+	for _, cache := range []*DB{Cache.DB, L2Cache.DB} {
+		assert.Equal(t, FlagReadLater, cachedFlags(t, cache, url), cache.Name)
+	}
+
+	_, err := SetCachedFlags(url, 0, FlagReadLater)
+	require.NoError(t, err)
+
+	// the read bookmark is not queued again by the module
+	require.NoError(t, buffer.UpsertBookmark(&Bookmark{URL: url, Title: "changed", Module: "test"}))
+	capture(FlagReadLater)
+	assert.Equal(t, Flags(0), cachedFlags(t, Cache.DB, url))
+
+	// an existing bookmark is queued when captured by a module
+	existing := testBookmarks[1].URL
+	require.NoError(t, buffer.UpsertBookmark(&Bookmark{
+		URL:    existing,
+		Tags:   []string{"toread"},
+		Flags:  int(FlagReadLater),
+		Module: "test",
+	}))
+	buffer.SyncTo(Cache.DB)
+	assert.Equal(t, FlagReadLater, cachedFlags(t, Cache.DB, existing))
+}
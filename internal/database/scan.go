@@ -108,7 +108,7 @@ type RawBookmark struct {
 	// Last modified
 	Modified uint64
 
-	// kept for buku compat, not used for now
+	// user flags, see [Flags]
 	Flags int
 
 	Module string
@@ -121,4 +121,14 @@ type RawBookmark struct {
 
 	// Node that made the change
 	NodeID UUID `db:"node_id"`
+
+	// canonical url used to detect duplicates, see [CanonicalURL]
+	Canonical string
+
+	// time the bookmark was first saved
+	CreatedAt uint64 `db:"created_at"`
+
+	// read later queue, only selected by the read later queries
+	QueuedAt uint64 `db:"queued_at"`
+	ReadAt   uint64 `db:"read_at"`
 }
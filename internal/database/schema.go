@@ -37,9 +37,29 @@ import (
 	  - Added version column to gskbookmarks table
 	  - Added node_id column to gskbookmarks table
 	  - Created sync_nodes table for node synchronization management
+  - Version 4: Added pending_changes table used to journal changes made to the
+    on-disk db by external programs (cli) while the daemon is running
+  - Version 5: Added marktab_runs table holding the history of marktab
+    command runs
+  - Version 6: Added webhook_deliveries table holding the queue of outgoing
+    webhooks
+  - Version 7: Added page_metadata table holding the metadata fetched from
+    the bookmarked pages
+  - Version 8: Added flags and mask columns to the pending_changes journal
+  - Version 9: Added read_later table and its triggers holding the read later
+    queue times
+  - Version 10: Added canonical column to gskbookmarks table used to detect
+    duplicate urls
+  - Version 11: Added created_at column to gskbookmarks table, the
+    bookmark_sources table holding the modules that saved each bookmark and
+    the target column to the pending_changes journal
+  - Version 12: Added created_at, queued_at and read_at columns to the
+    pending_changes journal
+  - Version 13: Added module_state table holding the values kept by the
+    modules across restarts
 */
 
-const CurrentSchemaVersion = 3
+const CurrentSchemaVersion = 13
 
 const (
 
@@ -49,6 +69,7 @@ const (
 	// flags: designed to be extended in future using bitwise masks
 	// Masks:
 	//     0b00000001: set title immutable ((do not change title when updating the bookmarks from the web ))
+	//     see [Flags] for the other bits
 	QCreateSchema = `
     CREATE TABLE IF NOT EXISTS gskbookmarks (
 		id INTEGER PRIMARY KEY,
@@ -61,15 +82,19 @@ const (
 		module TEXT DEFAULT '' ,
 		xhsum TEXT DEFAULT '',
 		version INTEGER DEFAULT 0,
-		node_id BLOB
+		node_id BLOB,
+		canonical TEXT DEFAULT '',
+		created_at INTEGER DEFAULT 0
 	);
 
+	CREATE INDEX IF NOT EXISTS gskbookmarks_canonical ON gskbookmarks(canonical);
+
 	CREATE TABLE IF NOT EXISTS sync_nodes (
 		ordinal INTEGER PRIMARY KEY,
 		node_id BLOB NOT NULL UNIQUE,
 		version INTEGER NOT NULL
-	)
-	`
+	);
+	` + QCreatePendingChanges + ";" + QCreateMarktabRuns + ";" + QCreateWebhookDeliveries + ";" + QCreatePageMetadata + ";" + QCreateReadLater + ";" + QCreateBookmarkSources + ";" + QCreateModuleState
 
 	// The following view and and triggers provide buku compatibility
 	QCreateView = `CREATE VIEW bookmarks AS
@@ -225,6 +250,56 @@ func checkDBVersion(db *DB) error {
 					return err
 				}
 				version = 3
+			case 3:
+				if err = db.migrateToVersion4(); err != nil {
+					return err
+				}
+				version = 4
+			case 4:
+				if err = db.migrateToVersion5(); err != nil {
+					return err
+				}
+				version = 5
+			case 5:
+				if err = db.migrateToVersion6(); err != nil {
+					return err
+				}
+				version = 6
+			case 6:
+				if err = db.migrateToVersion7(); err != nil {
+					return err
+				}
+				version = 7
+			case 7:
+				if err = db.migrateToVersion8(); err != nil {
+					return err
+				}
+				version = 8
+			case 8:
+				if err = db.migrateToVersion9(); err != nil {
+					return err
+				}
+				version = 9
+			case 9:
+				if err = db.migrateToVersion10(); err != nil {
+					return err
+				}
+				version = 10
+			case 10:
+				if err = db.migrateToVersion11(); err != nil {
+					return err
+				}
+				version = 11
+			case 11:
+				if err = db.migrateToVersion12(); err != nil {
+					return err
+				}
+				version = 12
+			case 12:
+				if err = db.migrateToVersion13(); err != nil {
+					return err
+				}
+				version = 13
 			}
 		}
 	}
@@ -102,7 +102,7 @@ func TestSchemaUpgrade(t *testing.T) {
 	require.Equal(t, CurrentSchemaVersion, version, "schema version upgrade failed")
 
 	// Verify that the new tables exist after upgrade
-	tables := []string{"gskbookmarks", "bookmarks"}
+	tables := []string{"gskbookmarks", "bookmarks", "module_state"}
 	for _, table := range tables {
 		var count int
 		err = db.Handle.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count)
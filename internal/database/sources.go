@@ -0,0 +1,237 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/blob42/gosuki"
+)
+
+// Each module or browser profile that saves a bookmark is recorded in the
+// `bookmark_sources` table with the time the bookmark was added in the source
+// when known, as with the browsers, and the time it was first synced. The
+// sources are copied along the bookmarks from the buffers to the caches.
+//
+// The `created_at` column of the bookmarks holds the oldest time the bookmark
+// was saved in any source.
+
+const QCreateBookmarkSources = `
+	CREATE TABLE IF NOT EXISTS bookmark_sources (
+		URL TEXT NOT NULL,
+		module TEXT NOT NULL,
+		created_at INTEGER DEFAULT 0,
+		seen_at INTEGER DEFAULT (strftime('%s')),
+		PRIMARY KEY (URL, module)
+	);
+
+	CREATE TRIGGER IF NOT EXISTS gskbookmarks_created_at
+	AFTER INSERT ON gskbookmarks
+	WHEN new.created_at IS NULL OR new.created_at = 0
+	BEGIN
+		UPDATE gskbookmarks SET created_at = strftime('%s') WHERE id = new.id;
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS bookmark_sources_delete
+	AFTER DELETE ON gskbookmarks
+	BEGIN
+		DELETE FROM bookmark_sources WHERE URL = old.URL;
+	END
+	`
+
+const (
+	// keeps the oldest times of a source
+	qOnSourceConflict = `
+	ON CONFLICT(URL, module) DO UPDATE SET
+		created_at = CASE
+			WHEN excluded.created_at > 0 AND (created_at = 0 OR excluded.created_at < created_at)
+			THEN excluded.created_at ELSE created_at END,
+		seen_at = min(seen_at, excluded.seen_at)
+	WHERE (excluded.created_at > 0 AND (created_at = 0 OR excluded.created_at < created_at))
+		OR excluded.seen_at < seen_at
+	`
+
+	qUpsertSource = `
+	INSERT INTO bookmark_sources(URL, module, created_at, seen_at)
+	VALUES (?, ?, ?, COALESCE(NULLIF(?, 0), strftime('%s')))
+	` + qOnSourceConflict
+
+	// sets the creation time of a bookmark to created if older
+	qUpdateCreatedAt = `
+	UPDATE gskbookmarks SET created_at = ?
+	WHERE URL = ? AND ? > 0 AND (created_at = 0 OR ? < created_at)
+	`
+)
+
+type rawSource struct {
+	URL     string `db:"URL"`
+	Module  string
+	Created uint64 `db:"created_at"`
+	Seen    uint64 `db:"seen_at"`
+}
+
+// addSource records that module saved the bookmark at url. A zero seen time
+// is the current time.
+func addSource(tx sqlx.Execer, url, module string, created, seen uint64) error {
+	if module == "" {
+		return nil
+	}
+	_, err := tx.Exec(qUpsertSource, url, module, created, seen)
+	return err
+}
+
+// sourceKey identifies a source of a bookmark
+type sourceKey struct {
+	url    string
+	module string
+}
+
+// olderThan reports whether s has older times than the source d, the times
+// kept by [qOnSourceConflict]
+func (s rawSource) olderThan(d rawSource) bool {
+	return (s.Created > 0 && (d.Created == 0 || s.Created < d.Created)) ||
+		(s.Seen > 0 && s.Seen < d.Seen)
+}
+
+// syncSources copies the sources of the bookmarks of src to dst. targets maps
+// the urls of the bookmarks of src to the ones in dst, see [canonicalTarget].
+// Only the sources missing from dst or with older times are written.
+func syncSources(src *DB, dstTx *sqlx.Tx, targets map[string]string) error {
+	var sources, dstSources []rawSource
+	if err := src.Handle.Select(&sources, `SELECT * FROM bookmark_sources`); err != nil {
+		return err
+	}
+	if err := dstTx.Select(&dstSources, `SELECT * FROM bookmark_sources`); err != nil {
+		return err
+	}
+
+	existing := make(map[sourceKey]rawSource, len(dstSources))
+	for _, s := range dstSources {
+		existing[sourceKey{s.URL, s.Module}] = s
+	}
+
+	for _, s := range sources {
+		url, ok := targets[s.URL]
+		if !ok {
+			url = canonicalTarget(dstTx, s.URL, CanonicalURL(s.URL))
+		}
+
+		d, ok := existing[sourceKey{url, s.Module}]
+		if ok && !s.olderThan(d) {
+			continue
+		}
+		if err := addSource(dstTx, url, s.Module, s.Created, s.Seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeBookmark merges the bookmark at url into the bookmark at target: the
+// sources are moved to target which keeps the oldest creation time, then the
+// bookmark is deleted.
+func mergeBookmark(tx *sqlx.Tx, url, target string, version uint64) error {
+	var created uint64
+	err := tx.Get(&created, `SELECT created_at FROM gskbookmarks WHERE URL = ?`, url)
+	if err != nil {
+		return ErrBookmarkNotFound
+	}
+
+	res, err := tx.Exec(qUpdateCreatedAt, created, target, created, created)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		_, err = tx.Exec(`UPDATE gskbookmarks SET version = ? WHERE URL = ?`, version, target)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO bookmark_sources(URL, module, created_at, seen_at)
+		SELECT ?, module, created_at, seen_at FROM bookmark_sources WHERE URL = ?
+		`+qOnSourceConflict, target, url)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM gskbookmarks WHERE URL = ?`, url)
+	return err
+}
+
+// Sources returns the sources of the bookmarks at urls, oldest first
+func (db *DB) Sources(ctx context.Context, urls []string) (map[string][]gosuki.Source, error) {
+	res := make(map[string][]gosuki.Source)
+	if len(urls) == 0 {
+		return res, nil
+	}
+
+	query, args, err := sqlx.In(
+		`SELECT * FROM bookmark_sources WHERE URL IN (?)
+		ORDER BY CASE WHEN created_at > 0 THEN created_at ELSE seen_at END, module`,
+		urls)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []rawSource
+	err = db.Handle.SelectContext(ctx, &sources, db.Handle.Rebind(query), args...)
+	if err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+
+	for _, s := range sources {
+		res[s.URL] = append(res[s.URL], gosuki.Source{
+			Module:  s.Module,
+			Created: s.Created,
+			Seen:    s.Seen,
+		})
+	}
+	return res, nil
+}
+
+// LoadSources sets the sources of the bookmarks from the on-disk db. Errors
+// are logged and the bookmarks are left without sources.
+func LoadSources(ctx context.Context, bookmarks []*Bookmark) {
+	if DiskDB == nil || DiskDB.Handle == nil || len(bookmarks) == 0 {
+		return
+	}
+
+	urls := make([]string, 0, len(bookmarks))
+	for _, bk := range bookmarks {
+		urls = append(urls, bk.URL)
+	}
+
+	sources, err := DiskDB.Sources(ctx, urls)
+	if err != nil {
+		log.Error("loading bookmark sources", "err", err)
+		return
+	}
+
+	for _, bk := range bookmarks {
+		bk.Sources = sources[bk.URL]
+	}
+}
@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookmarkSources(t *testing.T) {
+	ctx := context.Background()
+	setupPendingTest(t)
+	url := "https://example.org/sources"
+
+	for _, bk := range []*Bookmark{
+		{URL: url, Title: "From firefox", Module: "firefox_default", Created: 2000},
+		{URL: "http://www.example.org/sources/", Module: "chrome_Default", Created: 1000},
+		{URL: url, Module: "github"},
+	} {
+		buffer := getBuffer(t)
+		require.NoError(t, buffer.UpsertBookmark(bk))
+		buffer.SyncTo(Cache.DB)
+		buffer.Close()
+	}
+	Cache.SyncTo(L2Cache.DB)
+
+	for _, db := range []*DB{Cache.DB, L2Cache.DB} {
+		var created uint64
+		require.NoError(t, db.Handle.Get(&created,
+			`SELECT created_at FROM gskbookmarks WHERE URL = ?`, url))
+		assert.EqualValues(t, 1000, created, db.Name)
+
+		sources, err := db.Sources(ctx, []string{url})
+		require.NoError(t, err)
+		require.Len(t, sources[url], 3, db.Name)
+		assert.Equal(t, "chrome_Default", sources[url][0].Module)
+		assert.EqualValues(t, 1000, sources[url][0].Created)
+		assert.Equal(t, "firefox_default", sources[url][1].Module)
+		assert.Equal(t, "github", sources[url][2].Module)
+		assert.Zero(t, sources[url][2].Created)
+		assert.NotZero(t, sources[url][2].Seen)
+	}
+
+	// syncing again keeps the oldest times
+	buffer := getBuffer(t)
+	defer buffer.Close()
+	require.NoError(t, buffer.UpsertBookmark(&Bookmark{URL: url, Module: "chrome_Default", Created: 3000}))
+	buffer.SyncTo(Cache.DB)
+	sources, err := Cache.Sources(ctx, []string{url})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1000, sources[url][0].Created)
+}
+
+func TestCreatedAtDefault(t *testing.T) {
+	buffer := getBuffer(t)
+	defer buffer.Close()
+
+	require.NoError(t, buffer.UpsertBookmark(&Bookmark{URL: "https://example.org/new"}))
+	var raw RawBookmark
+	require.NoError(t, buffer.Handle.Get(&raw, `SELECT * FROM gskbookmarks`))
+	assert.NotZero(t, raw.CreatedAt)
+	assert.Equal(t, raw.CreatedAt, RawBookmarks{&raw}.AsBookmarks()[0].Created)
+
+	// bookmarks without a module have no source
+	sources, err := buffer.Sources(context.Background(), []string{raw.URL})
+	require.NoError(t, err)
+	assert.Empty(t, sources)
+}
+
+func TestMergeMovesSources(t *testing.T) {
+	ctx := context.Background()
+	setupPendingTest(t)
+
+	for _, raw := range []RawBookmark{
+		{URL: "https://example.org/merge", Module: "firefox_default", CreatedAt: 2000},
+		{URL: "https://example.org/merge/", Module: "chrome_Default", CreatedAt: 1000},
+	} {
+		_, err := DiskDB.Handle.Exec(
+			`INSERT INTO gskbookmarks(URL, module, created_at) VALUES (?, ?, ?)`,
+			raw.URL, raw.Module, raw.CreatedAt)
+		require.NoError(t, err)
+		require.NoError(t, addSource(DiskDB.Handle, raw.URL, raw.Module, raw.CreatedAt, 0))
+	}
+
+	dups, err := FindDuplicates(ctx)
+	require.NoError(t, err)
+	require.Len(t, dups, 1)
+
+	// the oldest bookmark is kept
+	assert.Equal(t, "https://example.org/merge/", dups[0].Bookmarks[0].URL)
+	require.NoError(t, MergeDuplicates(ctx, dups))
+
+	sources, err := DiskDB.Sources(ctx, []string{
+		"https://example.org/merge",
+		"https://example.org/merge/",
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, sources, "https://example.org/merge")
+	require.Len(t, sources["https://example.org/merge/"], 2)
+
+	bookmarks := []*Bookmark{{URL: "https://example.org/merge/"}}
+	LoadSources(ctx, bookmarks)
+	assert.Equal(t, "chrome_Default", bookmarks[0].Sources[0].Module)
+	assert.Equal(t, "firefox_default", bookmarks[0].Sources[1].Module)
+}
+
+func TestSyncSourcesWritesChanges(t *testing.T) {
+	setupPendingTest(t)
+	url := "https://example.org/sources"
+
+	syncToL2 := func(src *DB) int {
+		tx, err := L2Cache.Handle.Beginx()
+		require.NoError(t, err)
+		defer tx.Commit()
+
+		var before, after int
+		require.NoError(t, tx.Get(&before, `SELECT total_changes()`))
+		require.NoError(t, syncSources(src, tx, map[string]string{}))
+		require.NoError(t, tx.Get(&after, `SELECT total_changes()`))
+		return after - before
+	}
+
+	require.NoError(t, addSource(Cache.Handle, url, "firefox_default", 2000, 0))
+	require.NoError(t, addSource(Cache.Handle, url, "github", 0, 0))
+	assert.Equal(t, 2, syncToL2(Cache.DB))
+
+	// unchanged sources are not written again
+	assert.Zero(t, syncToL2(Cache.DB))
+
+	// new sources and older times are written
+	require.NoError(t, addSource(Cache.Handle, url, "chrome_Default", 0, 0))
+	require.NoError(t, addSource(Cache.Handle, url, "firefox_default", 1000, 0))
+	assert.Equal(t, 2, syncToL2(Cache.DB))
+
+	sources, err := L2Cache.Sources(context.Background(), []string{url})
+	require.NoError(t, err)
+	require.Len(t, sources[url], 3)
+	assert.Equal(t, "firefox_default", sources[url][0].Module)
+	assert.EqualValues(t, 1000, sources[url][0].Created)
+}
@@ -56,6 +56,7 @@ package database
 
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"sync"
@@ -65,6 +66,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	sqlite3 "github.com/mattn/go-sqlite3"
 
+	"github.com/blob42/gosuki/pkg/bus"
 	"github.com/blob42/gosuki/pkg/config"
 )
 
@@ -133,11 +135,20 @@ description
 - Uses Lamport clock for p2p synchronization to maintain causal ordering
 */
 func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
+	defer observeSync(dst, time.Now())
+
 	var err error
 	var sqlite3Err sqlite3.Error
 	var isSqlErr bool
 	var existingUrls = make(map[uint64]*RawBookmark)
 
+	// urls of the bookmarks of src in dst, see [canonicalTarget]
+	var targets = make(map[string]string)
+
+	// changes to the L1 cache are new bookmarks from the modules
+	var events bookmarkEvents
+	notify := dst.Name == CacheName
+
 	log.Debugf("syncing <%s> to <%s>", src.Name, dst.Name)
 	cacheMu.Lock()
 	defer cacheMu.Unlock()
@@ -164,10 +175,11 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 			module,
 			xhsum,
 			version,
-			node_id
-			
+			node_id,
+			canonical,
+			created_at
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 	)
 	if err != nil {
 		log.Error("prepare stmt", "err", err)
@@ -230,7 +242,7 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 	}
 
 	getDstTagsStmt, err := dst.Handle.Preparex(
-		`SELECT tags FROM gskbookmarks WHERE url=? LIMIT 1`,
+		`SELECT metadata, tags, desc, flags FROM gskbookmarks WHERE url=? LIMIT 1`,
 	)
 
 	// Start syncing all entries from source table
@@ -244,6 +256,15 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 			continue
 		}
 
+		// Bookmarks with the same canonical url as an existing one are
+		// merged into it
+		if scan.Canonical == "" {
+			scan.Canonical = CanonicalURL(scan.URL)
+		}
+		srcURL := scan.URL
+		scan.URL = canonicalTarget(dstTx, scan.URL, scan.Canonical)
+		targets[srcURL] = scan.URL
+
 		// Try to insert to row in dst table
 		_, err = dstTx.Stmtx(tryInsertDstRow).Exec(
 			scan.URL,
@@ -260,6 +281,8 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 			),
 			remoteClock,
 			scan.NodeID,
+			scan.Canonical,
+			scan.CreatedAt,
 		)
 
 		isSqlErr = false
@@ -286,6 +309,9 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 
 			existingUrls[uint64(oldBkHash)] = &scan
 
+		} else if err == nil && notify {
+			events.add(bus.BookmarkAdded, scan)
+
 			// insertion success on l2 cache, update clock
 		} else if err == nil && dst.Name == L2CacheName {
 			_, err = dstTx.Exec("UPDATE gskbookmarks SET version = ? WHERE URL = ?",
@@ -300,6 +326,8 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 	err = dstTx.Commit()
 	if err != nil {
 		log.Error("sync", "from", src.Name, "to", dst.Name, "err", err)
+	} else {
+		events.publish()
 	}
 
 	dstTx, err = dst.Handle.Beginx()
@@ -309,12 +337,20 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 
 	// Loop performing the update for each existing bookmark
 	for hash, scan := range existingUrls {
-		var tags string
+		var dstBk RawBookmark
 		//log.Debugf("updating existing %s", scan.Url)
 
-		if err = dstTx.Stmtx(getDstTagsStmt).Get(&tags, scan.URL); err != nil {
+		if err = dstTx.Stmtx(getDstTagsStmt).Get(&dstBk, scan.URL); err != nil {
 			log.Error("get tags query", "err", err)
 		}
+		tags := dstBk.Tags
+
+		// keep the oldest creation time
+		_, err = dstTx.Exec(qUpdateCreatedAt,
+			scan.CreatedAt, scan.URL, scan.CreatedAt, scan.CreatedAt)
+		if err != nil {
+			log.Error("update created_at", "err", err)
+		}
 
 		srcTags := tagsFromString(scan.Tags, TagSep).Sort()
 		dstTags := tagsFromString(tags, TagSep).Sort()
@@ -332,9 +368,39 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 			newTags.Add(k)
 		}
 		newTagsStr := newTags.Sort().StringWrap()
-		newHash := xhsum(scan.URL, scan.Metadata, newTagsStr, scan.Desc)
 
-		if strconv.FormatUint(hash, 10) == newHash {
+		// Flags are set by the user on the caches. Keep them when syncing the
+		// module buffers, which can only add the module flags, and never
+		// overwrite an immutable title.
+		title, desc, flags := scan.Metadata, scan.Desc, scan.Flags
+		if notify {
+			flags = dstBk.Flags
+			if Flags(flags).Has(FlagImmutableTitle) {
+				title = dstBk.Metadata
+			}
+
+			// bookmarks captured for later reading by a module are queued
+			// once. They are not queued again after being read.
+			if Flags(scan.Flags).Has(FlagReadLater) && !Flags(flags).Has(FlagReadLater) &&
+				!wasQueued(dstTx, scan.URL) {
+				flags |= int(FlagReadLater)
+			}
+
+			// the other module flags, like the archived flag set by marktab
+			// rules, are added like the tags of the rules
+			flags |= scan.Flags & int(ModuleFlags&^FlagReadLater)
+		}
+
+		// empty titles and descriptions do not replace the existing ones
+		if title == "" {
+			title = dstBk.Metadata
+		}
+		if desc == "" {
+			desc = dstBk.Desc
+		}
+		newHash := xhsum(scan.URL, title, newTagsStr, desc)
+
+		if strconv.FormatUint(hash, 10) == newHash && flags == dstBk.Flags {
 			continue
 		}
 
@@ -345,12 +411,12 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 		}
 
 		_, err = dstTx.Stmtx(updateDstRow).Exec(
-			scan.Metadata,
-			scan.Metadata,
+			title,
+			title,
 			newTagsStr,
-			scan.Desc,
-			scan.Desc,
-			0, //flags
+			desc,
+			desc,
+			flags,
 			scan.Module,
 			newHash,
 			clock,
@@ -360,14 +426,27 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 
 		if err != nil {
 			log.Errorf("%s: %s", err, scan.URL)
+		} else if notify {
+			updated := *scan
+			updated.Metadata = title
+			updated.Desc = desc
+			updated.Tags = newTagsStr
+			updated.Flags = flags
+			events.add(bus.BookmarkUpdated, updated)
 		}
 		log.Tracef("synced %s to %s", scan.URL, dst.Name)
 	}
 
+	if err = syncSources(src, dstTx, targets); err != nil {
+		log.Error("sync sources", "from", src.Name, "to", dst.Name, "err", err)
+	}
+
 	err = dstTx.Commit()
 	if err != nil {
 		dstTx.Rollback()
 		log.Error("sync:commit", "err", err)
+	} else {
+		events.publish()
 	}
 
 	// If we are syncing to memcache, schedule a write to disk
@@ -410,17 +489,8 @@ func cacheSyncScheduler(input <-chan any) {
 				if Cache.DB == nil {
 					log.Fatalf("cache db is nil")
 				}
-				// Backup in 2 levels
-				// 1. Sync Cache to L2 cache
-				// 2. Backup L2 cache to disk
-				// This allows comparing bookmark change checksums against the
-				// disk database. In other words, L1 cache used for efficiency
-				// and L2 ensures data integrity and avoids unecessary I/O.
-				Cache.SyncTo(L2Cache.DB)
-				if err := L2Cache.BackupToDisk(config.DBPath); err != nil {
+				if err := flushToDisk(); err != nil {
 					log.Fatalf("failed to sync l2 cache to disk: %s", err)
-				} else {
-					SyncTrigger.Store(true)
 				}
 
 				// empty the queue
@@ -432,6 +502,84 @@ func cacheSyncScheduler(input <-chan any) {
 	}
 }
 
+// flushToDisk syncs the L1 cache to the L2 cache then backs up the L2 cache to
+// disk.
+func flushToDisk() error {
+	// Backup in 2 levels
+	// 1. Sync Cache to L2 cache
+	// 2. Backup L2 cache to disk
+	// This allows comparing bookmark change checksums against the
+	// disk database. In other words, L1 cache used for efficiency
+	// and L2 ensures data integrity and avoids unecessary I/O.
+	//
+	// External writers (suki) journal their changes in the disk
+	// db. They are replayed onto the caches before the backup
+	// overwrites the disk db.
+	lock, err := LockDisk(config.DBPath)
+	if err != nil {
+		log.Error("locking disk db", "err", err)
+	}
+	defer lock.Unlock()
+
+	if err = ApplyPendingChanges(); err != nil {
+		log.Error("applying pending changes", "err", err)
+	}
+
+	Cache.SyncTo(L2Cache.DB)
+	start := time.Now()
+	err = L2Cache.BackupToDisk(config.DBPath)
+	observeBackup(start, err)
+	bus.Syncs.Publish(bus.SyncEvent{
+		Time:     time.Now(),
+		Duration: time.Since(start),
+		Err:      err,
+	})
+	if err != nil {
+		return err
+	}
+
+	SyncTrigger.Store(true)
+	return nil
+}
+
+// FlushToDisk immediately writes the content of the caches to disk without
+// waiting for the sync scheduler.
+func FlushToDisk() error {
+	if Cache.DB == nil || L2Cache.DB == nil {
+		return errors.New("cache is not initialized")
+	}
+	return flushToDisk()
+}
+
+// SyncCaches syncs the L1 cache to the L2 cache. Once synced, the L2 cache
+// reflects the latest state of the bookmarks with the same ids as on disk.
+func SyncCaches() {
+	Cache.SyncTo(L2Cache.DB)
+}
+
+// minimum delay between two syncs of the caches made by [SyncCachesForRead]
+const readSyncDelay = time.Second
+
+var readSync struct {
+	sync.Mutex
+	last time.Time
+}
+
+// SyncCachesForRead syncs the caches before reading the L2 cache, as done by
+// the control socket on each request. Syncs are debounced to one per
+// readSyncDelay. Changes made through the daemon, see [applyToCaches], are
+// written to both caches and visible immediately.
+func SyncCachesForRead() {
+	readSync.Lock()
+	defer readSync.Unlock()
+
+	if time.Since(readSync.last) < readSyncDelay {
+		return
+	}
+	SyncCaches()
+	readSync.last = time.Now()
+}
+
 func ScheduleBackupToDisk() {
 	go func() {
 		log.Debug("received sync to disk request")
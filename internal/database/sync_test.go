@@ -304,6 +304,8 @@ func TestSyncToDisk(t *testing.T) {
 	}
 
 	for i, bm := range bookmarks {
+		// the canonical url is set when syncing
+		bm.Canonical = CanonicalURL(bm.URL)
 		require.Equal(t, bm, dstBookmarks[i])
 	}
 }
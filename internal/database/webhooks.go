@@ -0,0 +1,247 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Outgoing webhook deliveries are queued in the `webhook_deliveries` table of
+// the L2 cache, which is backed up to disk with the bookmarks. Deliveries that
+// could not be sent are retried by the daemon, including after a restart.
+
+const QCreateWebhookDeliveries = `
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id INTEGER PRIMARY KEY,
+		webhook TEXT NOT NULL,
+		event TEXT NOT NULL,
+		URL TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		status TEXT NOT NULL,
+		attempts INTEGER DEFAULT 0,
+		last_error TEXT DEFAULT '',
+		next_attempt INTEGER NOT NULL,
+		created INTEGER NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS webhook_deliveries_due
+		ON webhook_deliveries(status, next_attempt);
+	`
+
+var errCacheNotInitialized = errors.New("cache is not initialized")
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending DeliveryStatus = "pending"
+	DeliveryFailed  DeliveryStatus = "failed"
+)
+
+// WebhookDelivery is a rendered webhook payload waiting to be sent
+type WebhookDelivery struct {
+	ID          uint64         `json:"id"`
+	Webhook     string         `json:"webhook"`
+	Event       string         `json:"event"`
+	URL         string         `json:"url" db:"URL"` // bookmark url
+	Payload     string         `json:"payload"`
+	Status      DeliveryStatus `json:"status"`
+	Attempts    int            `json:"attempts"`
+	LastError   string         `json:"last_error" db:"last_error"`
+	NextAttempt int64          `json:"next_attempt" db:"next_attempt"` // unix time
+	Created     int64          `json:"created"`                        // unix time
+}
+
+func (db *DB) InsertWebhookDelivery(ctx context.Context, d *WebhookDelivery) error {
+	if d.Status == "" {
+		d.Status = DeliveryPending
+	}
+
+	res, err := db.Handle.ExecContext(ctx,
+		`INSERT INTO webhook_deliveries(webhook, event, URL, payload, status,
+		attempts, last_error, next_attempt, created)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		d.Webhook,
+		d.Event,
+		d.URL,
+		d.Payload,
+		d.Status,
+		d.Attempts,
+		d.LastError,
+		d.NextAttempt,
+		d.Created,
+	)
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	d.ID = uint64(id)
+	return nil
+}
+
+// UpdateWebhookDelivery stores the status, attempts, last error and next
+// attempt of d
+func (db *DB) UpdateWebhookDelivery(ctx context.Context, d *WebhookDelivery) error {
+	_, err := db.Handle.ExecContext(ctx,
+		`UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, last_error = ?, next_attempt = ?
+		WHERE id = ?`,
+		d.Status, d.Attempts, d.LastError, d.NextAttempt, d.ID,
+	)
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	return nil
+}
+
+func (db *DB) DeleteWebhookDelivery(ctx context.Context, id uint64) error {
+	_, err := db.Handle.ExecContext(ctx,
+		`DELETE FROM webhook_deliveries WHERE id = ?`, id)
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	return nil
+}
+
+// PruneFailedWebhookDeliveries deletes the failed deliveries older than the
+// keep most recent ones. Pending deliveries are kept. keep <= 0 keeps all the
+// failed deliveries.
+func (db *DB) PruneFailedWebhookDeliveries(ctx context.Context, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	_, err := db.Handle.ExecContext(ctx,
+		`DELETE FROM webhook_deliveries
+		WHERE status = ? AND id NOT IN (
+			SELECT id FROM webhook_deliveries WHERE status = ?
+			ORDER BY id DESC LIMIT ?
+		)`,
+		DeliveryFailed, DeliveryFailed, keep)
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	return nil
+}
+
+// DueWebhookDeliveries returns at most limit pending deliveries whose next
+// attempt is at or before now, oldest first
+func (db *DB) DueWebhookDeliveries(ctx context.Context, now int64, limit int) ([]WebhookDelivery, error) {
+	query := `SELECT * FROM webhook_deliveries
+		WHERE status = ? AND next_attempt <= ?
+		ORDER BY next_attempt, id`
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	deliveries := []WebhookDelivery{}
+	err := db.Handle.SelectContext(ctx, &deliveries, query, DeliveryPending, now)
+	if err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+	return deliveries, nil
+}
+
+// WebhookDeliveries returns the queued deliveries with the given status, or
+// all of them if status is empty, most recent first
+func (db *DB) WebhookDeliveries(ctx context.Context, status DeliveryStatus) ([]WebhookDelivery, error) {
+	var where []string
+	var args []any
+	if status != "" {
+		where = append(where, "status = ?")
+		args = append(args, status)
+	}
+
+	query := "SELECT * FROM webhook_deliveries"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC"
+
+	deliveries := []WebhookDelivery{}
+	if err := db.Handle.SelectContext(ctx, &deliveries, query, args...); err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+	return deliveries, nil
+}
+
+// QueueWebhookDelivery stores d in the L2 cache and schedules a backup to
+// disk. It returns an error when the caches are not initialized.
+func QueueWebhookDelivery(ctx context.Context, d *WebhookDelivery) error {
+	if L2Cache.DB == nil {
+		return errCacheNotInitialized
+	}
+
+	if err := L2Cache.InsertWebhookDelivery(ctx, d); err != nil {
+		return err
+	}
+	ScheduleBackupToDisk()
+	return nil
+}
+
+// SaveWebhookDelivery stores the new state of a delivery that failed. When
+// the delivery is given up, the failed deliveries are pruned to the keep most
+// recent ones, see [DB.PruneFailedWebhookDeliveries].
+func SaveWebhookDelivery(ctx context.Context, d *WebhookDelivery, keep int) error {
+	if L2Cache.DB == nil {
+		return errCacheNotInitialized
+	}
+
+	if err := L2Cache.UpdateWebhookDelivery(ctx, d); err != nil {
+		return err
+	}
+	if d.Status == DeliveryFailed {
+		if err := L2Cache.PruneFailedWebhookDeliveries(ctx, keep); err != nil {
+			return err
+		}
+	}
+	ScheduleBackupToDisk()
+	return nil
+}
+
+// AckWebhookDelivery removes a delivery that was sent from the queue
+func AckWebhookDelivery(ctx context.Context, id uint64) error {
+	if L2Cache.DB == nil {
+		return errCacheNotInitialized
+	}
+
+	if err := L2Cache.DeleteWebhookDelivery(ctx, id); err != nil {
+		return err
+	}
+	ScheduleBackupToDisk()
+	return nil
+}
+
+// DueWebhooks returns the deliveries ready to be sent from the L2 cache
+func DueWebhooks(ctx context.Context, now int64, limit int) ([]WebhookDelivery, error) {
+	if L2Cache.DB == nil {
+		return nil, errCacheNotInitialized
+	}
+	return L2Cache.DueWebhookDeliveries(ctx, now, limit)
+}
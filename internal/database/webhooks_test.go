@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliveries(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDB("test_webhook_deliveries", "", DBTypeInMemoryDSN).Init()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.InitSchema(ctx))
+
+	deliveries := []*WebhookDelivery{
+		{Webhook: "a", Event: "added", URL: "https://a.com", Payload: `{}`, NextAttempt: 10, Created: 1},
+		{Webhook: "b", Event: "updated", URL: "https://b.com", Payload: `{}`, NextAttempt: 5, Created: 2},
+		{Webhook: "b", Event: "deleted", URL: "https://c.com", Payload: `{}`, NextAttempt: 50, Created: 3},
+	}
+	for _, d := range deliveries {
+		require.NoError(t, db.InsertWebhookDelivery(ctx, d))
+		assert.NotZero(t, d.ID)
+		assert.Equal(t, DeliveryPending, d.Status)
+	}
+
+	due, err := db.DueWebhookDeliveries(ctx, 20, 0)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, deliveries[1].ID, due[0].ID, "earliest attempt first")
+
+	due, err = db.DueWebhookDeliveries(ctx, 20, 1)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+
+	// a failed delivery is rescheduled
+	d := due[0]
+	d.Attempts = 1
+	d.LastError = "500 Internal Server Error"
+	d.NextAttempt = 100
+	require.NoError(t, db.UpdateWebhookDelivery(ctx, &d))
+
+	due, err = db.DueWebhookDeliveries(ctx, 20, 0)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, deliveries[0].ID, due[0].ID)
+
+	// failed deliveries are never due
+	d.Status = DeliveryFailed
+	d.NextAttempt = 0
+	require.NoError(t, db.UpdateWebhookDelivery(ctx, &d))
+	failed, err := db.WebhookDeliveries(ctx, DeliveryFailed)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, "500 Internal Server Error", failed[0].LastError)
+
+	require.NoError(t, db.DeleteWebhookDelivery(ctx, deliveries[0].ID))
+	all, err := db.WebhookDeliveries(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, deliveries[2].ID, all[0].ID, "most recent first")
+}
+
+func TestPruneFailedWebhookDeliveries(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDB("test_prune_webhook_deliveries", "", DBTypeInMemoryDSN).Init()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.InitSchema(ctx))
+
+	var failed []uint64
+	for i := range 5 {
+		d := &WebhookDelivery{Webhook: "a", Event: "added", URL: "https://a.com", Payload: `{}`, Created: int64(i)}
+		if i%2 == 0 {
+			d.Status = DeliveryFailed
+		}
+		require.NoError(t, db.InsertWebhookDelivery(ctx, d))
+		if d.Status == DeliveryFailed {
+			failed = append(failed, d.ID)
+		}
+	}
+
+	require.NoError(t, db.PruneFailedWebhookDeliveries(ctx, 0))
+	all, err := db.WebhookDeliveries(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, all, 5, "keep <= 0 keeps all the deliveries")
+
+	require.NoError(t, db.PruneFailedWebhookDeliveries(ctx, 2))
+	kept, err := db.WebhookDeliveries(ctx, DeliveryFailed)
+	require.NoError(t, err)
+	require.Len(t, kept, 2)
+	assert.Equal(t, failed[2], kept[0].ID)
+	assert.Equal(t, failed[1], kept[1].ID)
+
+	pending, err := db.WebhookDeliveries(ctx, DeliveryPending)
+	require.NoError(t, err)
+	assert.Len(t, pending, 2, "pending deliveries are kept")
+}
@@ -0,0 +1,321 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+// Package feeds publishes the bookmarks as Atom and RSS 2.0 feeds and exports
+// them as OPML.
+//
+// The web server serves the most recently modified bookmarks at:
+//
+//	/feeds/all.atom
+//	/feeds/all.rss
+//	/feeds/tag/{tag}.atom
+//	/feeds/tag/{tag}.rss
+//
+// The `limit` query param sets the number of entries.
+package feeds
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/build"
+	"github.com/blob42/gosuki/pkg/logging"
+)
+
+var log = logging.GetLogger("feeds")
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+
+	generator = "gosuki"
+)
+
+// Feed is a list of bookmarks published as a feed
+type Feed struct {
+	Title string
+
+	// url of the web page listing the bookmarks
+	Link string
+
+	// url of the feed itself
+	Self string
+
+	Bookmarks []*gosuki.Bookmark
+}
+
+// Updated returns the last time a bookmark of the feed was modified
+func (f *Feed) Updated() time.Time {
+	var last uint64
+	for _, bk := range f.Bookmarks {
+		last = max(last, bk.Modified)
+	}
+	if last == 0 {
+		return time.Now()
+	}
+	return time.Unix(int64(last), 0)
+}
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Updated   string      `xml:"updated"`
+	Links     []atomLink  `xml:"link"`
+	Author    atomAuthor  `xml:"author"`
+	Generator string      `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       atomLink       `xml:"link"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Summary    string         `xml:"summary,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+// WriteAtom writes the feed in the Atom format
+func WriteAtom(w io.Writer, f *Feed) error {
+	feed := atomFeed{
+		Title:   f.Title,
+		ID:      f.Self,
+		Updated: f.Updated().UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: f.Self},
+			{Rel: "alternate", Type: "text/html", Href: f.Link},
+		},
+		Author:    atomAuthor{Name: generator},
+		Generator: generator + " " + build.Version(),
+	}
+
+	for _, bk := range f.Bookmarks {
+		entry := atomEntry{
+			Title:   title(bk),
+			ID:      bk.URL,
+			Link:    atomLink{Href: bk.URL},
+			Updated: time.Unix(int64(bk.Modified), 0).UTC().Format(time.RFC3339),
+			Summary: bk.Desc,
+		}
+		if bk.Created != 0 {
+			entry.Published = time.Unix(int64(bk.Created), 0).UTC().Format(time.RFC3339)
+		}
+		for _, tag := range bk.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return writeXML(w, feed)
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	Description string   `xml:"description,omitempty"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+}
+
+// WriteRSS writes the feed in the RSS 2.0 format
+func WriteRSS(w io.Writer, f *Feed) error {
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Title,
+			LastBuildDate: f.Updated().UTC().Format(time.RFC1123Z),
+			Generator:     generator + " " + build.Version(),
+		},
+	}
+
+	for _, bk := range f.Bookmarks {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       title(bk),
+			Link:        bk.URL,
+			GUID:        rssGUID{IsPermaLink: true, Value: bk.URL},
+			Description: bk.Desc,
+			Categories:  bk.Tags,
+			PubDate:     time.Unix(int64(bk.Modified), 0).UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	return writeXML(w, feed)
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func title(bk *gosuki.Bookmark) string {
+	if bk.Title != "" {
+		return bk.Title
+	}
+	return bk.URL
+}
+
+var contentTypes = map[string]string{
+	".atom": "application/atom+xml; charset=utf-8",
+	".rss":  "application/rss+xml; charset=utf-8",
+}
+
+// AllFeed serves the feeds of all the bookmarks at /feeds/{file}
+func AllFeed(w http.ResponseWriter, r *http.Request) {
+	name, format := splitFile(chi.URLParam(r, "file"))
+	if name != "all" {
+		http.NotFound(w, r)
+		return
+	}
+	serveFeed(w, r, "", format)
+}
+
+// TagFeed serves the feeds of the bookmarks with a tag at /feeds/tag/{file}
+func TagFeed(w http.ResponseWriter, r *http.Request) {
+	tag, format := splitFile(chi.URLParam(r, "file"))
+	if tag == "" {
+		http.NotFound(w, r)
+		return
+	}
+	serveFeed(w, r, tag, format)
+}
+
+func splitFile(file string) (name, format string) {
+	file, err := url.PathUnescape(file)
+	if err != nil {
+		return "", ""
+	}
+	format = path.Ext(file)
+	return strings.TrimSuffix(file, format), format
+}
+
+func serveFeed(w http.ResponseWriter, r *http.Request, tag, format string) {
+	contentType, ok := contentTypes[format]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	limit := DefaultLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			http.Error(w, fmt.Sprintf("invalid limit: %q", s), http.StatusBadRequest)
+			return
+		}
+		limit = min(n, MaxLimit)
+	}
+
+	marks, err := db.RecentBookmarks(r.Context(), tag, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	base := baseURL(r)
+	feed := &Feed{
+		Title:     "gosuki bookmarks",
+		Link:      base + "/",
+		Self:      base + r.URL.Path,
+		Bookmarks: marks,
+	}
+	if tag != "" {
+		feed.Title = "gosuki bookmarks tagged #" + tag
+		feed.Link = base + "/bookmarks/" + url.PathEscape(tag)
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if format == ".rss" {
+		err = WriteRSS(w, feed)
+	} else {
+		err = WriteAtom(w, feed)
+	}
+	if err != nil {
+		log.Error("writing feed", "path", r.URL.Path, "err", err)
+	}
+}
+
+// baseURL returns the url of the server as seen by the client
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,101 @@
+package feeds
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+var testBookmarks = []*gosuki.Bookmark{
+	{
+		URL:      "https://go.dev/blog",
+		Title:    "The Go Blog",
+		Desc:     "news & <updates>",
+		Tags:     []string{"golang", "to-share"},
+		Modified: 1700000200,
+		Created:  1700000000,
+	},
+	{URL: "https://example.com", Modified: 1700000100},
+}
+
+func TestWriteAtom(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteAtom(&buf, &Feed{
+		Title:     "feed",
+		Link:      "http://localhost:2025/",
+		Self:      "http://localhost:2025/feeds/all.atom",
+		Bookmarks: testBookmarks,
+	}))
+
+	var feed atomFeed
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &feed))
+	assert.Equal(t, "http://localhost:2025/feeds/all.atom", feed.ID)
+	assert.Equal(t, "2023-11-14T22:16:40Z", feed.Updated)
+	require.Len(t, feed.Entries, 2)
+
+	entry := feed.Entries[0]
+	assert.Equal(t, "The Go Blog", entry.Title)
+	assert.Equal(t, "https://go.dev/blog", entry.Link.Href)
+	assert.Equal(t, "news & <updates>", entry.Summary)
+	assert.Equal(t, "2023-11-14T22:13:20Z", entry.Published)
+	assert.Equal(t, []atomCategory{{"golang"}, {"to-share"}}, entry.Categories)
+
+	// untitled bookmarks use their url
+	assert.Equal(t, "https://example.com", feed.Entries[1].Title)
+	assert.Empty(t, feed.Entries[1].Published)
+}
+
+func TestWriteRSS(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteRSS(&buf, &Feed{
+		Title:     "feed",
+		Link:      "http://localhost:2025/",
+		Bookmarks: testBookmarks,
+	}))
+
+	var feed rssFeed
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &feed))
+	assert.Equal(t, "2.0", feed.Version)
+	require.Len(t, feed.Channel.Items, 2)
+
+	item := feed.Channel.Items[0]
+	assert.Equal(t, "https://go.dev/blog", item.Link)
+	assert.Equal(t, rssGUID{IsPermaLink: true, Value: "https://go.dev/blog"}, item.GUID)
+	assert.Equal(t, []string{"golang", "to-share"}, item.Categories)
+	assert.Equal(t, "Tue, 14 Nov 2023 22:16:40 +0000", item.PubDate)
+}
+
+func TestWriteOPML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteOPML(&buf, testBookmarks, "http://localhost:2025/"))
+
+	var doc opml
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Len(t, doc.Body, 3)
+
+	var texts []string
+	for _, group := range doc.Body {
+		texts = append(texts, group.Text)
+	}
+	assert.Equal(t, []string{"golang", "to-share", Untagged}, texts)
+
+	share := doc.Body[1]
+	assert.Equal(t, "rss", share.Type)
+	assert.Equal(t, "http://localhost:2025/feeds/tag/to-share.atom", share.XMLURL)
+	require.Len(t, share.Outlines, 1)
+	assert.Equal(t, outline{Text: "The Go Blog", Type: "link", URL: "https://go.dev/blog"}, share.Outlines[0])
+
+	untagged := doc.Body[2]
+	assert.Empty(t, untagged.XMLURL)
+	assert.Equal(t, "https://example.com", untagged.Outlines[0].URL)
+
+	// without server url the tags are plain outlines
+	buf.Reset()
+	require.NoError(t, WriteOPML(&buf, testBookmarks, ""))
+	assert.NotContains(t, buf.String(), "xmlUrl")
+}
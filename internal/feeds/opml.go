@@ -0,0 +1,104 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package feeds
+
+import (
+	"encoding/xml"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blob42/gosuki"
+)
+
+// Untagged is the outline of the bookmarks without tags in the OPML export
+const Untagged = "untagged"
+
+type opml struct {
+	XMLName xml.Name  `xml:"opml"`
+	Version string    `xml:"version,attr"`
+	Head    opmlHead  `xml:"head"`
+	Body    []outline `xml:"body>outline"`
+}
+
+type opmlHead struct {
+	Title       string `xml:"title"`
+	DateCreated string `xml:"dateCreated"`
+}
+
+type outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	URL      string    `xml:"url,attr,omitempty"`
+	XMLURL   string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string    `xml:"htmlUrl,attr,omitempty"`
+	Outlines []outline `xml:"outline"`
+}
+
+// WriteOPML writes the bookmarks as an OPML 2.0 document with an outline per
+// tag. A bookmark with several tags is listed under each of them. When
+// serverURL is set, each tag outline links to the Atom feed of the tag so the
+// document can be imported in a feed reader.
+func WriteOPML(w io.Writer, bookmarks []*gosuki.Bookmark, serverURL string) error {
+	serverURL = strings.TrimSuffix(serverURL, "/")
+
+	byTag := map[string][]outline{}
+	for _, bk := range bookmarks {
+		link := outline{Text: title(bk), Type: "link", URL: bk.URL}
+		tags := bk.Tags
+		if len(tags) == 0 {
+			tags = []string{Untagged}
+		}
+		for _, tag := range tags {
+			byTag[tag] = append(byTag[tag], link)
+		}
+	}
+
+	tags := make([]string, 0, len(byTag))
+	for tag := range byTag {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	doc := opml{
+		Version: "2.0",
+		Head: opmlHead{
+			Title:       "gosuki bookmarks",
+			DateCreated: time.Now().UTC().Format(time.RFC1123Z),
+		},
+	}
+	for _, tag := range tags {
+		group := outline{Text: tag, Title: tag, Outlines: byTag[tag]}
+		if serverURL != "" && tag != Untagged {
+			group.Type = "rss"
+			group.XMLURL = serverURL + "/feeds/tag/" + url.PathEscape(tag) + ".atom"
+			group.HTMLURL = serverURL + "/bookmarks/" + url.PathEscape(tag)
+		}
+		doc.Body = append(doc.Body, group)
+	}
+
+	return writeXML(w, doc)
+}
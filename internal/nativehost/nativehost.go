@@ -0,0 +1,262 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+// Package nativehost implements the WebExtension native messaging protocol so
+// a browser extension can query and save bookmarks.
+//
+// Each message is a JSON object prefixed with its length as a 32 bit integer
+// in native byte order. The extension sends requests with an `op` field and
+// an optional `id` copied to the response:
+//
+//	{"id": 1, "op": "lookup", "url": "https://example.com"}
+//	{"id": 2, "op": "add", "url": "https://example.com", "title": "Example", "tags": ["demo"], "desc": "selected text"}
+//	{"id": 3, "op": "suggest", "prefix": "go", "url": "https://go.dev", "limit": 10}
+//	{"id": 4, "op": "search", "query": "golang", "fuzzy": false, "limit": 20}
+//	{"id": 5, "op": "version"}
+//
+// Failed requests have an `error` field in their response.
+package nativehost
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/build"
+	"github.com/blob42/gosuki/pkg/logging"
+)
+
+const (
+	// Module of the bookmarks added by the extension
+	Module = "native-host"
+
+	// browsers do not accept messages larger than 1MB from the host
+	maxResponseSize = 1 << 20
+
+	// limit the memory used by requests
+	maxRequestSize = 64 << 20
+
+	defaultLimit = 50
+)
+
+var (
+	log = logging.GetLogger("native-host")
+
+	ErrMessageTooLarge = errors.New("message too large")
+)
+
+// Store reads and writes the bookmarks, through the daemon when it is
+// running
+type Store interface {
+	// Lookup returns the bookmark saved at url or nil
+	Lookup(ctx context.Context, url string) (*gosuki.Bookmark, error)
+	Set(ctx context.Context, bk *gosuki.Bookmark) error
+	Search(ctx context.Context, query string, fuzzy bool) ([]*gosuki.Bookmark, error)
+	SuggestTags(ctx context.Context, prefix, url string, limit int) ([]db.TagCount, error)
+}
+
+// Request is a message sent by the extension
+type Request struct {
+	ID json.RawMessage `json:"id,omitempty"`
+	Op string          `json:"op"`
+
+	URL string `json:"url,omitempty"`
+
+	// fields of the added bookmark, the fields of an existing bookmark are
+	// kept when missing
+	Title *string   `json:"title,omitempty"`
+	Tags  *[]string `json:"tags,omitempty"`
+	Desc  *string   `json:"desc,omitempty"`
+
+	Query  string `json:"query,omitempty"`
+	Fuzzy  bool   `json:"fuzzy,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+// Response is a message sent to the extension
+type Response struct {
+	ID    json.RawMessage `json:"id,omitempty"`
+	Error string          `json:"error,omitempty"`
+
+	// missing when the looked up url is not bookmarked
+	Bookmark  *gosuki.Bookmark   `json:"bookmark,omitempty"`
+	Bookmarks []*gosuki.Bookmark `json:"bookmarks,omitempty"`
+	Tags      []db.TagCount      `json:"tags,omitempty"`
+	Version   string             `json:"version,omitempty"`
+}
+
+// ReadMessage reads a length-prefixed message into v. It returns io.EOF when
+// the browser closed the connection.
+func ReadMessage(r io.Reader, v any) error {
+	var size uint32
+	if err := binary.Read(r, binary.NativeEndian, &size); err != nil {
+		return err
+	}
+	if size > maxRequestSize {
+		return ErrMessageTooLarge
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// WriteMessage writes v as a length-prefixed message
+func WriteMessage(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxResponseSize {
+		return ErrMessageTooLarge
+	}
+
+	if err := binary.Write(w, binary.NativeEndian, uint32(len(data))); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Host answers the requests of the extension
+type Host struct {
+	Store Store
+}
+
+// Serve handles the requests read from r until the browser closes it
+func (h *Host) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	for {
+		var req Request
+		err := ReadMessage(r, &req)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		var resp *Response
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+			resp = &Response{Error: "invalid request: " + err.Error()}
+		case err != nil:
+			return err
+		default:
+			log.Debug("request", "op", req.Op, "url", req.URL)
+			resp, err = h.Handle(ctx, &req)
+			if err != nil {
+				resp = &Response{Error: err.Error()}
+			}
+			resp.ID = req.ID
+		}
+
+		err = WriteMessage(w, resp)
+		if errors.Is(err, ErrMessageTooLarge) {
+			err = WriteMessage(w, &Response{ID: resp.ID, Error: "response too large, use a lower limit"})
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Handle runs a request
+func (h *Host) Handle(ctx context.Context, req *Request) (*Response, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	switch req.Op {
+	case "lookup":
+		if req.URL == "" {
+			return nil, errors.New("missing url")
+		}
+		bk, err := h.Store.Lookup(ctx, req.URL)
+		if err != nil {
+			return nil, err
+		}
+		return &Response{Bookmark: bk}, nil
+
+	case "add":
+		return h.add(ctx, req)
+
+	case "suggest":
+		tags, err := h.Store.SuggestTags(ctx, req.Prefix, req.URL, limit)
+		if err != nil {
+			return nil, err
+		}
+		return &Response{Tags: tags}, nil
+
+	case "search":
+		marks, err := h.Store.Search(ctx, req.Query, req.Fuzzy)
+		if err != nil {
+			return nil, err
+		}
+		if len(marks) > limit {
+			marks = marks[:limit]
+		}
+		return &Response{Bookmarks: marks}, nil
+
+	case "version":
+		return &Response{Version: build.Version()}, nil
+	}
+
+	return nil, fmt.Errorf("unknown op: %q", req.Op)
+}
+
+// add creates or updates the bookmark at the requested url
+func (h *Host) add(ctx context.Context, req *Request) (*Response, error) {
+	if req.URL == "" {
+		return nil, errors.New("missing url")
+	}
+
+	bk, err := h.Store.Lookup(ctx, req.URL)
+	if err != nil {
+		return nil, err
+	}
+	if bk == nil {
+		bk = &gosuki.Bookmark{URL: req.URL, Module: Module}
+	}
+
+	if req.Title != nil {
+		bk.Title = *req.Title
+	}
+	if req.Desc != nil {
+		bk.Desc = *req.Desc
+	}
+	if req.Tags != nil {
+		bk.Tags = *req.Tags
+	}
+
+	if err := h.Store.Set(ctx, bk); err != nil {
+		return nil, err
+	}
+	return &Response{Bookmark: bk}, nil
+}
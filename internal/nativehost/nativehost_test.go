@@ -0,0 +1,138 @@
+package nativehost
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+type memStore struct {
+	marks map[string]*gosuki.Bookmark
+}
+
+func (s *memStore) Lookup(_ context.Context, url string) (*gosuki.Bookmark, error) {
+	if bk, ok := s.marks[url]; ok {
+		copied := *bk
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (s *memStore) Set(_ context.Context, bk *gosuki.Bookmark) error {
+	s.marks[bk.URL] = bk
+	return nil
+}
+
+func (s *memStore) Search(_ context.Context, query string, _ bool) ([]*gosuki.Bookmark, error) {
+	var result []*gosuki.Bookmark
+	for _, bk := range s.marks {
+		if strings.Contains(bk.Title, query) {
+			result = append(result, bk)
+		}
+	}
+	return result, nil
+}
+
+func (s *memStore) SuggestTags(_ context.Context, prefix, _ string, _ int) ([]db.TagCount, error) {
+	return []db.TagCount{{Tag: prefix + "lang", Count: 2}}, nil
+}
+
+// serve pipes the framed requests to a host and returns its responses
+func serve(t *testing.T, store Store, requests ...string) []Response {
+	t.Helper()
+
+	var in, out bytes.Buffer
+	for _, req := range requests {
+		require.NoError(t, binary.Write(&in, binary.NativeEndian, uint32(len(req))))
+		in.WriteString(req)
+	}
+
+	host := &Host{Store: store}
+	require.NoError(t, host.Serve(context.Background(), &in, &out))
+
+	var responses []Response
+	for out.Len() > 0 {
+		var resp Response
+		require.NoError(t, ReadMessage(&out, &resp))
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func TestNativeHost(t *testing.T) {
+	store := &memStore{marks: map[string]*gosuki.Bookmark{
+		"https://go.dev": {URL: "https://go.dev", Title: "Go", Tags: []string{"golang"}, Module: "firefox"},
+	}}
+
+	responses := serve(t, store,
+		`{"id": 1, "op": "lookup", "url": "https://go.dev"}`,
+		`{"id": 2, "op": "lookup", "url": "https://example.com"}`,
+		`{"id": 3, "op": "add", "url": "https://example.com", "title": "Example", "tags": ["demo"], "desc": "selected"}`,
+		`{"id": 4, "op": "add", "url": "https://go.dev", "desc": "The Go website"}`,
+		`{"id": 5, "op": "suggest", "prefix": "go"}`,
+		`{"id": "s", "op": "search", "query": "Exa"}`,
+		`{"id": 7, "op": "fly"}`,
+		`{"id": 8, "op": "lookup"}`,
+		`not json`,
+		`{"op": "version"}`,
+	)
+	require.Len(t, responses, 10)
+
+	assert.JSONEq(t, `1`, string(responses[0].ID))
+	require.NotNil(t, responses[0].Bookmark)
+	assert.Equal(t, "Go", responses[0].Bookmark.Title)
+
+	assert.Nil(t, responses[1].Bookmark)
+	assert.Empty(t, responses[1].Error)
+
+	assert.Equal(t, &gosuki.Bookmark{
+		URL:    "https://example.com",
+		Title:  "Example",
+		Tags:   []string{"demo"},
+		Desc:   "selected",
+		Module: Module,
+	}, store.marks["https://example.com"])
+
+	// missing fields are kept
+	goDev := store.marks["https://go.dev"]
+	assert.Equal(t, "Go", goDev.Title)
+	assert.Equal(t, []string{"golang"}, goDev.Tags)
+	assert.Equal(t, "The Go website", goDev.Desc)
+	assert.Equal(t, "firefox", goDev.Module)
+
+	assert.Equal(t, []db.TagCount{{Tag: "golang", Count: 2}}, responses[4].Tags)
+
+	assert.JSONEq(t, `"s"`, string(responses[5].ID))
+	require.Len(t, responses[5].Bookmarks, 1)
+	assert.Equal(t, "https://example.com", responses[5].Bookmarks[0].URL)
+
+	assert.Contains(t, responses[6].Error, "unknown op")
+	assert.Contains(t, responses[7].Error, "missing url")
+	assert.Contains(t, responses[8].Error, "invalid request")
+	assert.NotEmpty(t, responses[9].Version)
+}
+
+func TestResponseTooLarge(t *testing.T) {
+	store := &memStore{marks: map[string]*gosuki.Bookmark{}}
+	for i := range 20 {
+		url := "https://example.com/" + strings.Repeat("x", 1<<16) + string(rune('a'+i))
+		store.marks[url] = &gosuki.Bookmark{URL: url, Title: "page"}
+	}
+
+	responses := serve(t, store, `{"id": 1, "op": "search", "query": "page"}`)
+	require.Len(t, responses, 1)
+	assert.Contains(t, responses[0].Error, "too large")
+
+	data, err := json.Marshal(responses[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"id":1`)
+}
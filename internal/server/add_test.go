@@ -0,0 +1,158 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+func getBody(t *testing.T, resp *http.Response, err error) string {
+	t.Helper()
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestCapture(t *testing.T) {
+	setupCaches(t)
+	db.DiskDB = db.L2Cache.DB
+	t.Cleanup(func() { db.DiskDB = nil })
+
+	require.NoError(t, db.SetCachedBookmark(&gosuki.Bookmark{
+		URL:    "https://go.dev/doc",
+		Title:  "Go docs",
+		Tags:   []string{"golang", "docs"},
+		Module: "firefox",
+	}))
+
+	srv := httptest.NewServer(NewWebUIServer(true, nil))
+	t.Cleanup(srv.Close)
+
+	t.Run("prefill", func(t *testing.T) {
+		q := url.Values{
+			"url":   {"https://go.dev/blog"},
+			"title": {`The "Go" Blog`},
+			"desc":  {"<b>selected</b>"},
+		}
+		resp, err := http.Get(srv.URL + "/add?" + q.Encode())
+		body := getBody(t, resp, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, body, `value="https://go.dev/blog"`)
+		assert.Contains(t, body, `value="The &#34;Go&#34; Blog"`)
+		assert.Contains(t, body, "&lt;b&gt;selected&lt;/b&gt;")
+		assert.Contains(t, body, `data-tag="golang"`, "same site tags are suggested")
+		assert.NotContains(t, body, "already bookmarked")
+	})
+
+	t.Run("existing", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/add?url=" + url.QueryEscape("https://go.dev/doc"))
+		body := getBody(t, resp, err)
+		assert.Contains(t, body, "already bookmarked")
+		assert.Contains(t, body, `value="Go docs"`)
+		assert.Contains(t, body, `value="docs, golang"`)
+	})
+
+	t.Run("save", func(t *testing.T) {
+		resp, err := http.PostForm(srv.URL+"/add", url.Values{
+			"url":   {"https://go.dev/blog"},
+			"title": {"The Go Blog"},
+			"tags":  {"golang, blog,, golang"},
+			"desc":  {"news"},
+		})
+		body := getBody(t, resp, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, body, "Saved")
+
+		bk, err := db.LookupCachedBookmark(t.Context(), "https://go.dev/blog")
+		require.NoError(t, err)
+		assert.Equal(t, "The Go Blog", bk.Title)
+		assert.ElementsMatch(t, []string{"golang", "blog"}, bk.Tags)
+		assert.Equal(t, "news", bk.Desc)
+		assert.Equal(t, "webui", bk.Module)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		resp, err := http.PostForm(srv.URL+"/add", url.Values{
+			"url":  {"https://go.dev/doc"},
+			"tags": {"reference"},
+		})
+		body := getBody(t, resp, err)
+		assert.Contains(t, body, "Updated")
+
+		bk, err := db.LookupCachedBookmark(t.Context(), "https://go.dev/doc")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"reference"}, bk.Tags)
+		assert.Equal(t, "firefox", bk.Module)
+	})
+
+	t.Run("missing url", func(t *testing.T) {
+		resp, err := http.PostForm(srv.URL+"/add", url.Values{"title": {"x"}})
+		body := getBody(t, resp, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, body, "missing url")
+	})
+
+	t.Run("escaped", func(t *testing.T) {
+		q := url.Values{
+			"url":   {"https://go.dev/x"},
+			"title": {`"><script>alert(1)</script>`},
+		}
+		resp, err := http.Get(srv.URL + "/add?" + q.Encode())
+		body := getBody(t, resp, err)
+		assert.NotContains(t, body, "<script>alert(1)")
+		assert.Contains(t, body, "&lt;script&gt;alert(1)&lt;/script&gt;")
+	})
+
+	t.Run("http urls only", func(t *testing.T) {
+		for _, u := range []string{"javascript:alert(1)", "file:///etc/passwd", "https://"} {
+			resp, err := http.PostForm(srv.URL+"/add", url.Values{"url": {u}})
+			body := getBody(t, resp, err)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, u)
+			assert.Contains(t, body, "only http and https urls", u)
+
+			_, err = db.LookupCachedBookmark(t.Context(), u)
+			assert.ErrorIs(t, err, db.ErrBookmarkNotFound, u)
+		}
+	})
+
+	t.Run("cross site", func(t *testing.T) {
+		post := func(header, value string) int {
+			req, err := http.NewRequest(http.MethodPost, srv.URL+"/add",
+				strings.NewReader(url.Values{"url": {"https://evil.example.com"}}.Encode()))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Header.Set(header, value)
+			resp, err := http.DefaultClient.Do(req)
+			getBody(t, resp, err)
+			return resp.StatusCode
+		}
+
+		assert.Equal(t, http.StatusForbidden, post("Sec-Fetch-Site", "cross-site"))
+		assert.Equal(t, http.StatusForbidden, post("Sec-Fetch-Site", "same-site"))
+		assert.Equal(t, http.StatusForbidden, post("Origin", "https://evil.example.com"))
+		_, err := db.LookupCachedBookmark(t.Context(), "https://evil.example.com")
+		assert.ErrorIs(t, err, db.ErrBookmarkNotFound)
+
+		assert.Equal(t, http.StatusOK, post("Sec-Fetch-Site", "same-origin"))
+		assert.Equal(t, http.StatusOK, post("Origin", srv.URL))
+	})
+
+	t.Run("bookmarklet", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/")
+		body := getBody(t, resp, err)
+		host := srv.Listener.Addr().String()
+		assert.Contains(t, body, `href="javascript:(function(){`)
+		assert.Contains(t, body, "http://"+host+"/add?url=")
+	})
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/bus"
+)
+
+// readEvent reads the next server-sent event, skipping comments
+func readEvent(t *testing.T, r *bufio.Reader) (string, string) {
+	t.Helper()
+	var name string
+	var data []string
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "" && name != "":
+			return name, strings.Join(data, "\n")
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = append(data, strings.TrimPrefix(line, "data: "))
+		}
+	}
+}
+
+func streamEvents(t *testing.T, query string) *bufio.Reader {
+	t.Helper()
+	srv := httptest.NewServer(NewWebUIServer(true, nil))
+	t.Cleanup(srv.Close)
+
+	subs := bus.Bookmarks.Subscribers()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/events"+query, nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	require.Eventually(t, func() bool {
+		return bus.Bookmarks.Subscribers() > subs
+	}, time.Second, 10*time.Millisecond)
+
+	return bufio.NewReader(resp.Body)
+}
+
+func TestEventsJSON(t *testing.T) {
+	stream := streamEvents(t, "")
+
+	bus.Bookmarks.Publish(bus.BookmarkEvent{
+		Type:     bus.BookmarkAdded,
+		Bookmark: &gosuki.Bookmark{URL: "https://example.com", Title: "<b>example</b>"},
+	})
+
+	name, data := readEvent(t, stream)
+	assert.Equal(t, "added", name)
+
+	var ev struct {
+		Type     string
+		Bookmark gosuki.Bookmark
+	}
+	require.NoError(t, json.Unmarshal([]byte(data), &ev))
+	assert.Equal(t, "added", ev.Type)
+	assert.Equal(t, "https://example.com", ev.Bookmark.URL)
+	assert.Equal(t, "<b>example</b>", ev.Bookmark.Title)
+}
+
+func TestEventsHTML(t *testing.T) {
+	stream := streamEvents(t, "?format=html")
+
+	bk := &gosuki.Bookmark{URL: "https://example.com", Title: "<b>example</b>", Tags: []string{"foo"}}
+	bus.Bookmarks.Publish(bus.BookmarkEvent{Type: bus.BookmarkAdded, Bookmark: bk})
+
+	name, data := readEvent(t, stream)
+	assert.Equal(t, "added", name)
+	assert.Contains(t, data, `<li id="bk-`)
+	assert.Contains(t, data, "&lt;b&gt;example&lt;/b&gt;")
+	assert.Contains(t, data, "/?tag=foo")
+	assert.NotContains(t, data, "hx-swap-oob")
+
+	// the published bookmark must not be modified
+	assert.Equal(t, "<b>example</b>", bk.Title)
+
+	bus.Bookmarks.Publish(bus.BookmarkEvent{Type: bus.BookmarkUpdated, Bookmark: bk})
+	name, data = readEvent(t, stream)
+	assert.Equal(t, "updated", name)
+	assert.Contains(t, data, `hx-swap-oob="outerHTML"`)
+
+	bus.Bookmarks.Publish(bus.BookmarkEvent{Type: bus.BookmarkDeleted, Bookmark: bk})
+	name, data = readEvent(t, stream)
+	assert.Equal(t, "deleted", name)
+	assert.Contains(t, data, `hx-swap-oob="delete"`)
+
+	// the fields set by modules are escaped
+	bus.Bookmarks.Publish(bus.BookmarkEvent{Type: bus.BookmarkAdded, Bookmark: &gosuki.Bookmark{
+		URL:    `javascript:alert(1)//"><script>alert(2)</script>`,
+		Title:  "xss",
+		Module: `"><script>alert(3)</script>`,
+	}})
+	name, data = readEvent(t, stream)
+	assert.Equal(t, "added", name)
+	assert.NotContains(t, data, "<script>")
+	assert.NotContains(t, data, `href="javascript:`)
+	assert.Contains(t, data, "&lt;script&gt;alert(3)&lt;/script&gt;")
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+func TestFeeds(t *testing.T) {
+	setupCaches(t)
+	db.DiskDB = db.L2Cache.DB
+	t.Cleanup(func() { db.DiskDB = nil })
+
+	require.NoError(t, db.SetCachedBookmark(&gosuki.Bookmark{
+		URL: "https://a.com", Title: "A", Tags: []string{"to-share"},
+	}))
+	require.NoError(t, db.SetCachedBookmark(&gosuki.Bookmark{
+		URL: "https://b.com", Title: "B", Tags: []string{"golang"},
+	}))
+
+	srv := httptest.NewServer(NewWebUIServer(true, nil))
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL + "/feeds/all.atom")
+	body := getBody(t, resp, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/atom+xml; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Contains(t, body, `<link href="https://a.com"></link>`)
+	assert.Contains(t, body, `<link href="https://b.com"></link>`)
+	assert.Contains(t, body, `href="`+srv.URL+`/feeds/all.atom"`)
+
+	resp, err = http.Get(srv.URL + "/feeds/tag/to-share.rss")
+	body = getBody(t, resp, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/rss+xml; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Contains(t, body, "<link>https://a.com</link>")
+	assert.NotContains(t, body, "https://b.com")
+	assert.Contains(t, body, "<link>"+srv.URL+"/bookmarks/to-share</link>")
+
+	for _, path := range []string{"/feeds/other.atom", "/feeds/all.json", "/feeds/tag/.atom"} {
+		resp, err = http.Get(srv.URL + path)
+		getBody(t, resp, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+	}
+
+	resp, err = http.Get(srv.URL + "/feeds/all.atom?limit=x")
+	getBody(t, resp, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
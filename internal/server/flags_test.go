@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+func TestMain(m *testing.M) {
+	db.RegisterSqliteHooks()
+	os.Exit(m.Run())
+}
+
+func setupCaches(t *testing.T) {
+	db.Clock = &db.LamportClock{}
+	cache, err := db.NewDB("test_server", "", db.DBTypeInMemoryDSN).Init()
+	require.NoError(t, err)
+	l2, err := db.NewDB("test_server_l2", "", db.DBTypeInMemoryDSN).Init()
+	require.NoError(t, err)
+	for _, c := range []*db.DB{cache, l2} {
+		require.NoError(t, c.InitSchema(context.Background()))
+	}
+	db.Cache.DB, db.L2Cache.DB = cache, l2
+
+	t.Cleanup(func() {
+		cache.Close()
+		l2.Close()
+		db.Cache.DB, db.L2Cache.DB = nil, nil
+	})
+}
+
+func TestPostFlags(t *testing.T) {
+	setupCaches(t)
+	require.NoError(t, db.SetCachedBookmark(&gosuki.Bookmark{URL: "https://a.com", Title: "a"}))
+
+	srv := httptest.NewServer(NewWebUIServer(true, nil))
+	t.Cleanup(srv.Close)
+
+	post := func(body string) *http.Response {
+		resp, err := http.Post(srv.URL+"/api/flags", "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	resp := post(`{"url": "https://a.com", "set": ["pinned", "private"]}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var bk gosuki.Bookmark
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&bk))
+	assert.Equal(t, int(db.FlagPinned|db.FlagPrivate), bk.Flags)
+
+	resp = post(`{"url": "https://a.com", "clear": ["private"]}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&bk))
+	assert.Equal(t, int(db.FlagPinned), bk.Flags)
+
+	assert.Equal(t, http.StatusBadRequest, post(`{"url": "https://a.com", "set": ["starred"]}`).StatusCode)
+	assert.Equal(t, http.StatusNotFound, post(`{"url": "https://b.com", "set": ["pinned"]}`).StatusCode)
+}
+
+func TestToggleFlag(t *testing.T) {
+	setupCaches(t)
+	require.NoError(t, db.SetCachedBookmark(&gosuki.Bookmark{URL: "https://a.com/?q=1", Title: "a"}))
+
+	srv := httptest.NewServer(NewWebUIServer(true, nil))
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Post(srv.URL+"/bookmarks/flags?url=https%3A%2F%2Fa.com%2F%3Fq%3D1&set=read-later", "", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `class="bookmark"`)
+	assert.Contains(t, string(body), `class="flag on" title="Read later"`)
+	assert.Contains(t, string(body), `url=https%3A%2F%2Fa.com%2F%3Fq%3D1&amp;clear=read-later`)
+}
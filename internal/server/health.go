@@ -0,0 +1,187 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/logging"
+	"github.com/blob42/gosuki/pkg/manager"
+)
+
+var log = logging.GetLogger("server")
+
+var (
+	bookmarksDesc = prometheus.NewDesc(
+		"gosuki_bookmarks",
+		"Number of bookmarks in the cache by module.",
+		[]string{"module"}, nil,
+	)
+
+	unitUpDesc = prometheus.NewDesc(
+		"gosuki_unit_up",
+		"Whether all the manager units with this name are running.",
+		[]string{"unit"}, nil,
+	)
+
+	unitRestartsDesc = prometheus.NewDesc(
+		"gosuki_unit_restarts_total",
+		"Number of times units were recovered after a panic.",
+		[]string{"unit"}, nil,
+	)
+)
+
+// collector exports the metrics computed at scrape time from the cache and
+// the manager units.
+type collector struct {
+	mngr *manager.Manager
+}
+
+func (c collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- bookmarksDesc
+	ch <- unitUpDesc
+	ch <- unitRestartsDesc
+}
+
+func (c collector) Collect(ch chan<- prometheus.Metric) {
+	if database.Cache.IsInitialized() {
+		counts, err := database.Cache.CountByModule(context.Background())
+		if err != nil {
+			log.Error("collecting bookmark metrics", "err", err)
+		}
+		for module, n := range counts {
+			ch <- prometheus.MustNewConstMetric(bookmarksDesc,
+				prometheus.GaugeValue, float64(n), module)
+		}
+	}
+
+	if c.mngr == nil {
+		return
+	}
+
+	// several units can share the same name
+	up := map[string]bool{}
+	restarts := map[string]int{}
+	for _, unit := range c.mngr.Status() {
+		running, seen := up[unit.Name]
+		up[unit.Name] = unit.State == manager.UnitRunning && (running || !seen)
+		restarts[unit.Name] += unit.Restarts
+	}
+
+	for name, running := range up {
+		var v float64
+		if running {
+			v = 1
+		}
+		ch <- prometheus.MustNewConstMetric(unitUpDesc, prometheus.GaugeValue, v, name)
+		ch <- prometheus.MustNewConstMetric(unitRestartsDesc,
+			prometheus.CounterValue, float64(restarts[name]), name)
+	}
+}
+
+// registerCollector registers the collector of mngr, replacing the one of a
+// previously created server
+func registerCollector(mngr *manager.Manager) {
+	prometheus.Unregister(collector{})
+	if err := prometheus.Register(collector{mngr}); err != nil {
+		log.Error("registering metrics", "err", err)
+	}
+}
+
+// unitsHealth returns an error listing the units that failed or are waiting
+// to be restarted. Units stopped on purpose are not reported.
+func unitsHealth(mngr *manager.Manager) error {
+	if mngr == nil {
+		return nil
+	}
+
+	var down []string
+	for _, unit := range mngr.Status() {
+		switch unit.State {
+		case manager.UnitFailed, manager.UnitRestarting:
+			down = append(down, fmt.Sprintf("%s (%s)", unit.Name, unit.State))
+		}
+	}
+	if len(down) > 0 {
+		return fmt.Errorf("units down: %s", strings.Join(down, ", "))
+	}
+	return nil
+}
+
+// paths polled by monitoring tools
+var probePaths = map[string]bool{
+	"/metrics": true,
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// skipProbes wraps the middleware mw so that it is not applied to the
+// monitoring endpoints. Used to avoid logging every scrape.
+func skipProbes(mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if probePaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+			wrapped.ServeHTTP(w, r)
+		})
+	}
+}
+
+func writeHealth(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, err)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+// healthz reports whether all the manager units are running
+func healthz(mngr *manager.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, unitsHealth(mngr))
+	}
+}
+
+// readyz reports whether the daemon is ready to serve bookmarks: the cache is
+// initialized and all the units are running.
+func readyz(mngr *manager.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !database.Cache.IsInitialized() {
+			writeHealth(w, errors.New("cache is not initialized"))
+			return
+		}
+		writeHealth(w, unitsHealth(mngr))
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki/pkg/manager"
+)
+
+type idleUnit struct{}
+
+func (idleUnit) Run(um manager.UnitManager) {
+	<-um.ShouldStop()
+	um.Done()
+}
+
+type failingUnit struct{}
+
+func (failingUnit) Run(um manager.UnitManager) {
+	um.Panic("boom")
+}
+
+func get(t *testing.T, h http.Handler, path string) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return rec.Code, string(body)
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	mngr := manager.NewManager()
+	mngr.AddUnit(idleUnit{}, "idle")
+	srv := NewWebUIServer(true, mngr)
+
+	require.Eventually(t, func() bool {
+		code, _ := get(t, srv, "/healthz")
+		return code == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+
+	// the cache is not initialized in tests
+	code, body := get(t, srv, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, body, "cache")
+
+	code, body = get(t, srv, "/metrics")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `gosuki_unit_up{unit="idle"} 1`)
+	assert.Contains(t, body, `gosuki_unit_restarts_total{unit="idle"} 0`)
+
+	mngr.AddUnit(failingUnit{}, "failing")
+	require.Eventually(t, func() bool {
+		code, body = get(t, srv, "/healthz")
+		return code == http.StatusServiceUnavailable
+	}, time.Second, 10*time.Millisecond)
+	assert.Contains(t, body, "failing")
+}
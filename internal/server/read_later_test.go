@@ -0,0 +1,66 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/api"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+func TestReadLater(t *testing.T) {
+	setupCaches(t)
+	// the web UI reads the on-disk db
+	db.DiskDB = db.L2Cache.DB
+	t.Cleanup(func() { db.DiskDB = nil })
+
+	for _, url := range []string{"https://a.com", "https://b.com"} {
+		require.NoError(t, db.SetCachedBookmark(&gosuki.Bookmark{URL: url, Title: url}))
+		_, err := db.SetCachedFlags(url, db.FlagReadLater, 0)
+		require.NoError(t, err)
+	}
+
+	srv := httptest.NewServer(NewWebUIServer(true, nil))
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL + "/read-later")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "2 unread")
+	assert.Contains(t, string(body), `hx-post="/read-later/read?url=https%3A%2F%2Fa.com"`)
+
+	resp, err = http.Post(srv.URL+"/read-later/read?url=https%3A%2F%2Fa.com", "", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(srv.URL + "/api/read-later")
+	require.NoError(t, err)
+	var payload api.Payload
+	payload.Result = &[]*gosuki.Bookmark{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+	resp.Body.Close()
+	unread := *payload.Result.(*[]*gosuki.Bookmark)
+	require.Len(t, unread, 1)
+	assert.Equal(t, "https://b.com", unread[0].URL)
+	assert.NotZero(t, unread[0].QueuedAt)
+
+	resp, err = http.Get(srv.URL + "/api/read-later?read=on")
+	require.NoError(t, err)
+	payload.Result = &[]*gosuki.Bookmark{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+	resp.Body.Close()
+	read := *payload.Result.(*[]*gosuki.Bookmark)
+	require.Len(t, read, 1)
+	assert.Equal(t, "https://a.com", read[0].URL)
+	assert.NotZero(t, read[0].ReadAt)
+}
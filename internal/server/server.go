@@ -26,12 +26,15 @@ import (
 	"fmt"
 	"io/fs"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/blob42/gosuki/internal/api"
+	"github.com/blob42/gosuki/internal/feeds"
 	webui "github.com/blob42/gosuki/internal/webui"
 	"github.com/blob42/gosuki/pkg/manager"
 )
@@ -63,25 +66,80 @@ func (s *WebUIServer) Run(m manager.UnitManager) {
 
 	// Wait for stop signal
 	<-m.ShouldStop()
+
+	// release the address so the unit can be started again
+	if err := server.Close(); err != nil {
+		log.Error("closing web ui server", "err", err)
+	}
 	m.Done()
 }
 
-func NewWebUIServer(tuiMode bool) *WebUIServer {
+// rejectCrossSite rejects the requests changing bookmarks that are sent by
+// the pages of other sites, using the Sec-Fetch-Site header or the Origin
+// header of older browsers. Requests of clients which are not browsers have
+// neither and are accepted.
+func rejectCrossSite(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		allowed := true
+		switch site := r.Header.Get("Sec-Fetch-Site"); site {
+		case "same-origin", "none":
+		case "":
+			if origin := r.Header.Get("Origin"); origin != "" {
+				u, err := url.Parse(origin)
+				allowed = err == nil && u.Host == r.Host
+			}
+		default:
+			allowed = false
+		}
+
+		if !allowed {
+			http.Error(w, "cross-site request rejected", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// NewWebUIServer creates the web UI server. The metrics and health endpoints
+// report the state of the units run by mngr.
+func NewWebUIServer(tuiMode bool, mngr *manager.Manager) *WebUIServer {
 
 	router := chi.NewRouter()
 	if !tuiMode {
-		router.Use(middleware.Logger)
+		router.Use(skipProbes(middleware.Logger))
 	}
 	router.Use(middleware.Recoverer)
+	router.Use(rejectCrossSite)
 
 	apiRoute := chi.NewRouter()
 	apiRoute.Get("/bookmarks", api.GetAPIBookmarks)
+	apiRoute.Get("/events", webui.Events)
+	apiRoute.Post("/flags", api.PostFlags)
+	apiRoute.Get("/read-later", api.GetAPIReadLater)
 
 	router.Mount("/api", apiRoute)
 
+	registerCollector(mngr)
+	router.Handle("/metrics", promhttp.Handler())
+	router.Get("/healthz", healthz(mngr))
+	router.Get("/readyz", readyz(mngr))
+
 	router.Get("/greet", greet)
 	router.Get("/bookmarks", webui.ListBookmarks)
 	router.Get("/bookmarks/{tag}", webui.ListBookmarks)
+	router.Post("/bookmarks/flags", webui.ToggleFlag)
+	router.Get("/read-later", webui.ReadLaterView)
+	router.Post("/read-later/read", webui.MarkRead)
+	router.Get("/add", webui.AddView)
+	router.Post("/add", webui.SaveBookmark)
+	router.Get("/feeds/{file}", feeds.AllFeed)
+	router.Get("/feeds/tag/{file}", feeds.TagFeed)
 	router.Get("/kill", func(w http.ResponseWriter, r *http.Request) {
 		panic("quit")
 	})
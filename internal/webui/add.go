@@ -0,0 +1,195 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package webui
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+const (
+	// Module of the bookmarks saved from the capture page
+	Module = "webui"
+
+	maxTagSuggestions = 20
+)
+
+var validHost = regexp.MustCompile(`^[A-Za-z0-9.:\[\]-]+$`)
+
+// The capture page shows data from arbitrary pages and is rendered with
+// html/template
+var addTemplate = template.Must(template.New("base.html").ParseFS(Templates,
+	"templates/base.html",
+	"templates/partials/head.html",
+	"templates/partials/header.html",
+))
+
+// AddContext is the data of the capture page
+type AddContext struct {
+	MarksContext
+
+	URL   string
+	Title string
+	Desc  string
+	Tags  string
+
+	// the url is already bookmarked
+	Exists      bool
+	Saved       bool
+	Error       string
+	Suggestions []db.TagCount
+}
+
+// Bookmarklet returns the javascript: url opening the capture page of the
+// server reached at host with the current page prefilled.
+func Bookmarklet(host string) string {
+	if !validHost.MatchString(host) {
+		host = fmt.Sprintf("localhost:%d", BindPort)
+	}
+	return "javascript:(function(){" +
+		"var d=document,s=String(window.getSelection()),m=d.querySelector('meta[name=description]');" +
+		"window.open('http://" + host + "/add?url='+encodeURIComponent(location.href)" +
+		"+'&title='+encodeURIComponent(d.title)" +
+		"+'&desc='+encodeURIComponent(s||(m?m.content:''))," +
+		"'gosuki','width=720,height=640');})();"
+}
+
+// AddView renders the capture form prefilled from the url, title and desc
+// query params. The fields of an existing bookmark are used when the params
+// are empty.
+func AddView(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	ctx := AddContext{
+		MarksContext: MarksContext{QueryParams: DefaultQueryParams()},
+		URL:          strings.TrimSpace(q.Get("url")),
+		Title:        q.Get("title"),
+		Desc:         q.Get("desc"),
+	}
+
+	if ctx.URL != "" {
+		bk, err := db.LookupCachedBookmark(r.Context(), ctx.URL)
+		switch {
+		case err == nil:
+			ctx.Exists = true
+			if ctx.Title == "" {
+				ctx.Title = bk.Title
+			}
+			if ctx.Desc == "" {
+				ctx.Desc = bk.Desc
+			}
+			ctx.Tags = strings.Join(bk.Tags, ", ")
+		case !errors.Is(err, db.ErrBookmarkNotFound):
+			log.Error("capture lookup", "url", ctx.URL, "err", err)
+		}
+	}
+
+	renderAdd(w, r, http.StatusOK, ctx)
+}
+
+// SaveBookmark creates or updates the bookmark submitted by the capture form.
+// The title, description and tags of an existing bookmark are replaced.
+func SaveBookmark(w http.ResponseWriter, r *http.Request) {
+	ctx := AddContext{
+		MarksContext: MarksContext{QueryParams: DefaultQueryParams()},
+		URL:          strings.TrimSpace(r.FormValue("url")),
+		Title:        r.FormValue("title"),
+		Desc:         r.FormValue("desc"),
+		Tags:         r.FormValue("tags"),
+	}
+	if ctx.URL == "" {
+		ctx.Error = "missing url"
+		renderAdd(w, r, http.StatusBadRequest, ctx)
+		return
+	}
+	if u, err := url.Parse(ctx.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		ctx.Error = "only http and https urls can be bookmarked"
+		renderAdd(w, r, http.StatusBadRequest, ctx)
+		return
+	}
+
+	bk, err := db.LookupCachedBookmark(r.Context(), ctx.URL)
+	if errors.Is(err, db.ErrBookmarkNotFound) {
+		bk, err = &gosuki.Bookmark{URL: ctx.URL, Module: Module}, nil
+	} else if err == nil {
+		ctx.Exists = true
+	}
+	if err == nil {
+		bk.Title = ctx.Title
+		bk.Desc = ctx.Desc
+		bk.Tags = parseTags(ctx.Tags)
+		err = db.SetCachedBookmark(bk)
+	}
+	if err != nil {
+		ctx.Error = err.Error()
+		renderAdd(w, r, http.StatusInternalServerError, ctx)
+		return
+	}
+
+	ctx.Saved = true
+	ctx.Tags = strings.Join(bk.Tags, ", ")
+	renderAdd(w, r, http.StatusOK, ctx)
+}
+
+func renderAdd(w http.ResponseWriter, r *http.Request, status int, ctx AddContext) {
+	v, err := addTemplate.Clone()
+	if err == nil {
+		v, err = v.ParseFS(Views, "views/add.html")
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "parsing template: %s", err)
+		return
+	}
+
+	ctx.Suggestions, err = db.SuggestCachedTags(r.Context(), "", ctx.URL, maxTagSuggestions)
+	if err != nil {
+		log.Error("tag suggestions", "url", ctx.URL, "err", err)
+	}
+
+	w.WriteHeader(status)
+	v.Execute(w, ctx)
+}
+
+// parseTags splits a comma separated list of tags, dropping empty and
+// duplicate entries
+func parseTags(s string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
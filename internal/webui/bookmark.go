@@ -23,17 +23,31 @@
 package webui
 
 import (
+	"context"
+	"fmt"
+	"hash/fnv"
 	"html/template"
 	"strings"
+	"time"
 
 	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
 )
 
 type Bookmarks []*gosuki.Bookmark
 
+// The single bookmarks sent to the pages by the event stream and the flag
+// toggles show data from any module and are rendered with html/template
+var bookmarkTemplates = template.Must(template.ParseFS(Templates,
+	"templates/partials/bookmark.html",
+))
+
 type UIBookmark struct {
 	*gosuki.Bookmark
 	DisplayURL string
+
+	// Favicon of the page, fetched by the enrich module
+	Favicon string
 }
 
 func NewUIBookmark(b *gosuki.Bookmark) *UIBookmark {
@@ -48,10 +62,81 @@ func NewUIBookmark(b *gosuki.Bookmark) *UIBookmark {
 	}
 }
 
+// newPartialBookmark returns the UIBookmark of b rendered with
+// [bookmarkTemplates], which escapes its fields. b is not modified.
+func newPartialBookmark(b *gosuki.Bookmark, favicon bool) *UIBookmark {
+	uiBk := &UIBookmark{
+		Bookmark:   b,
+		DisplayURL: b.URL,
+	}
+	if favicon {
+		uiBk.Favicon = db.Favicons(context.Background(), []string{b.URL})[b.URL]
+	}
+	return uiBk
+}
+
+// DOMID returns the id of the html element of the bookmark, derived from its
+// url so that streamed updates can target it.
+func (b *UIBookmark) DOMID() string {
+	h := fnv.New64a()
+	h.Write([]byte(b.URL))
+	return fmt.Sprintf("bk-%x", h.Sum64())
+}
+
+// Added returns the date the bookmark was first saved
+func (b *UIBookmark) Added() string {
+	if b.Created == 0 {
+		return ""
+	}
+	return time.Unix(int64(b.Created), 0).Format(time.DateOnly)
+}
+
+// UISource is a source of a bookmark as shown in the bookmark list
+type UISource struct {
+	Module string
+
+	// dates the bookmark was added in the source and first synced
+	Title string
+}
+
+// SourceList returns the modules that saved the bookmark. It falls back to the
+// module of the bookmark when the sources were not loaded.
+func (b *UIBookmark) SourceList() []UISource {
+	if len(b.Sources) == 0 {
+		if b.Module == "" {
+			return nil
+		}
+		return []UISource{{Module: b.Module}}
+	}
+
+	res := make([]UISource, 0, len(b.Sources))
+	for _, s := range b.Sources {
+		title := "synced " + time.Unix(int64(s.Seen), 0).Format(time.DateOnly)
+		if s.Created > 0 {
+			title = "added " + time.Unix(int64(s.Created), 0).Format(time.DateOnly) + ", " + title
+		}
+		res = append(res, UISource{Module: s.Module, Title: title})
+	}
+	return res
+}
+
+// setFavicon sets the favicon of the bookmark from the known favicons
+func (b *UIBookmark) setFavicon(favicons map[string]string) {
+	b.Favicon = template.HTMLEscapeString(favicons[b.URL])
+}
+
 func (marks Bookmarks) UIBookmarks() []*UIBookmark {
 	res := []*UIBookmark{}
+	urls := make([]string, 0, len(marks))
+	for _, bk := range marks {
+		urls = append(urls, bk.URL)
+	}
+	favicons := db.Favicons(context.Background(), urls)
+
 	for _, bk := range marks {
-		res = append(res, NewUIBookmark(bk))
+		uiBk := NewUIBookmark(bk)
+		uiBk.setFavicon(favicons)
+		res = append(res, uiBk)
 	}
 
 	return res
@@ -0,0 +1,139 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package webui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/bus"
+	"github.com/blob42/gosuki/pkg/logging"
+)
+
+// interval of the comments sent to keep idle connections open
+const sseKeepAlive = 30 * time.Second
+
+var log = logging.GetLogger("webui")
+
+// Events streams the bookmark changes as server-sent events named after the
+// change type: added, updated or deleted. Events are encoded as JSON unless
+// the `format=html` query parameter is set, in which case they are rendered
+// for the htmx sse extension.
+func Events(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+
+	// the stream outlives the write timeout of the server
+	err := rc.SetWriteDeadline(time.Time{})
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	html := r.URL.Query().Get("format") == "html"
+
+	sub := bus.Bookmarks.Subscribe(bus.Options{
+		Name:   "sse",
+		Buffer: 256,
+		Policy: bus.Coalesce,
+	})
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err = rc.Flush(); err != nil {
+		log.Error("streaming events", "err", err)
+		return
+	}
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			io.WriteString(w, ": keep-alive\n\n")
+		case ev, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			data, err := encodeEvent(ev, html)
+			if err != nil {
+				log.Error("encoding event", "url", ev.Bookmark.URL, "err", err)
+				continue
+			}
+			writeEvent(w, string(ev.Type), data)
+		}
+
+		if err = rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// payload of the json events
+type jsonEvent struct {
+	Type     bus.BookmarkEventType `json:"type"`
+	Bookmark *gosuki.Bookmark      `json:"bookmark"`
+}
+
+// context of the bookmark-event template
+type htmlEvent struct {
+	Type     bus.BookmarkEventType
+	Bookmark *UIBookmark
+}
+
+func encodeEvent(ev bus.BookmarkEvent, html bool) (string, error) {
+	if !html {
+		data, err := json.Marshal(jsonEvent{ev.Type, ev.Bookmark})
+		return string(data), err
+	}
+
+	// the event is streamed to every open page, render it with html/template
+	var buf bytes.Buffer
+	err := bookmarkTemplates.ExecuteTemplate(&buf, "bookmark-event", htmlEvent{
+		Type:     ev.Type,
+		Bookmark: newPartialBookmark(ev.Bookmark, ev.Type != bus.BookmarkDeleted),
+	})
+	return strings.TrimSpace(buf.String()), err
+}
+
+// writeEvent writes a server-sent event. Each line of data is sent as a
+// separate data field.
+func writeEvent(w io.Writer, name, data string) {
+	fmt.Fprintf(w, "event: %s\n", name)
+	for line := range strings.SplitSeq(data, "\n") {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	fmt.Fprint(w, "\n")
+}
@@ -0,0 +1,96 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package webui
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/api"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+// FlagToggle is a button setting or clearing a flag of a bookmark
+type FlagToggle struct {
+	Name  string
+	Label string
+	Icon  string
+	On    bool
+}
+
+// flags that can be toggled from the web UI
+var flagToggles = []FlagToggle{
+	{Name: "pinned", Label: "Pin", Icon: "📌"},
+	{Name: "read-later", Label: "Read later", Icon: "🔖"},
+	{Name: "archived", Label: "Archive", Icon: "🗄"},
+	{Name: "private", Label: "Private", Icon: "🔒"},
+	{Name: "immutable-title", Label: "Lock title", Icon: "✏"},
+}
+
+func (b *UIBookmark) FlagToggles() []FlagToggle {
+	res := make([]FlagToggle, 0, len(flagToggles))
+	for _, t := range flagToggles {
+		flag, _ := gosuki.ParseFlag(t.Name)
+		t.On = db.Flags(b.Flags).Has(flag)
+		res = append(res, t)
+	}
+	return res
+}
+
+func splitFlagNames(s string) []string {
+	var names []string
+	for name := range strings.SplitSeq(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ToggleFlag handles the flag buttons of the bookmark list. It replies with
+// the updated bookmark.
+func ToggleFlag(w http.ResponseWriter, r *http.Request) {
+	bk, err := api.SetFlags(
+		r.FormValue("url"),
+		splitFlagNames(r.FormValue("set")),
+		splitFlagNames(r.FormValue("clear")),
+	)
+	if errors.Is(err, db.ErrBookmarkNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := bookmarkTemplates.ExecuteTemplate(&buf, "bookmark-item", newPartialBookmark(bk, true)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes())
+}
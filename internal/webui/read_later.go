@@ -0,0 +1,89 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package webui
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/blob42/gosuki/internal/api"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+// Queued returns the date the bookmark was added to the read later queue
+func (b *UIBookmark) Queued() string {
+	if b.QueuedAt == 0 {
+		return ""
+	}
+	return time.Unix(int64(b.QueuedAt), 0).Format(time.DateOnly)
+}
+
+// ReadLaterView lists the unread bookmarks of the read later queue
+func ReadLaterView(w http.ResponseWriter, r *http.Request) {
+	v, err := templates.Clone()
+	if err == nil {
+		v, err = v.ParseFS(Views, "views/read_later.html")
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "parsing template: %s", err)
+		return
+	}
+
+	queryParams := DefaultQueryParams()
+	queryParams.PaginationParams = api.GetPaginationParams(r)
+	if r.URL.Query().Get("per_page") == "" {
+		queryParams.Size = -1
+	}
+
+	res, err := db.ReadLater(r.Context(), false, queryParams.PaginationParams)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "getting bookmarks: %s", err)
+		return
+	}
+	db.LoadSources(r.Context(), res.Bookmarks)
+
+	v.Execute(w, MarksContext{
+		Total:       int(res.Total),
+		Bookmarks:   Bookmarks(res.Bookmarks).UIBookmarks(),
+		QueryParams: queryParams,
+		ReadLater:   true,
+	})
+}
+
+// MarkRead removes a bookmark from the read later queue. It replies with an
+// empty body which removes the bookmark from the list.
+func MarkRead(w http.ResponseWriter, r *http.Request) {
+	_, err := api.SetFlags(r.FormValue("url"), nil, []string{"read-later"})
+	if errors.Is(err, db.ErrBookmarkNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -72,6 +72,18 @@ type MarksContext struct {
 	Total     int // total number of results for query (excluding pagination)
 	Pages     int
 	QueryParams
+
+	// the page lists the read later queue
+	ReadLater bool
+
+	// javascript: url of the capture bookmarklet, only set on the index page
+	Bookmarklet string
+}
+
+// Live reports whether new bookmarks should be streamed to the page. Only the
+// unfiltered first page is updated.
+func (ctx MarksContext) Live() bool {
+	return !ctx.ReadLater && ctx.Query == "" && ctx.Tag == "" && ctx.Page <= 1
 }
 
 // order of query param handling is important
@@ -223,6 +235,7 @@ func IndexView(w http.ResponseWriter, r *http.Request) {
 		Pages:       int(math.Ceil(float64(total) / float64(queryParams.Size))),
 		Bookmarks:   uiBookmarks,
 		QueryParams: queryParams,
+		Bookmarklet: Bookmarklet(r.Host),
 	})
 }
 
@@ -0,0 +1,202 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+// Package enrich fetches the pages of the bookmarks with a missing title or
+// description and fills them with the page metadata. The OpenGraph data,
+// canonical url and favicon of the pages are stored in the database and the
+// favicons are shown in the web UI.
+//
+// The module sends a request to each bookmarked site and must be enabled in
+// the config:
+//
+//	[enrich]
+//	enabled = true
+package enrich
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/logging"
+	"github.com/blob42/gosuki/pkg/modules"
+	"github.com/blob42/gosuki/pkg/watch"
+)
+
+const (
+	ModID = "enrich"
+
+	DefaultInterval = 30 * time.Minute
+)
+
+var (
+	Config = NewEnrichConfig()
+	log    = logging.GetLogger(ModID)
+
+	errNotEnabled = errors.New("not enabled in config")
+)
+
+type EnrichConfig struct {
+	Enabled bool `toml:"enabled" mapstructure:"enabled"`
+
+	// Interval at which bookmarks with missing metadata are looked up
+	Interval time.Duration `toml:"interval" mapstructure:"interval"`
+
+	// Maximum number of pages fetched at each interval
+	BatchSize int `toml:"batch-size" mapstructure:"batch-size"`
+
+	// Number of pages fetched concurrently
+	Workers int `toml:"workers" mapstructure:"workers"`
+
+	Timeout time.Duration `toml:"timeout" mapstructure:"timeout"`
+
+	UserAgent string `toml:"user-agent" mapstructure:"user-agent"`
+
+	// Delay before fetching again the pages that could not be fetched
+	RetryFailed time.Duration `toml:"retry-failed" mapstructure:"retry-failed"`
+}
+
+func NewEnrichConfig() *EnrichConfig {
+	return &EnrichConfig{
+		Interval:    DefaultInterval,
+		BatchSize:   50,
+		Workers:     4,
+		Timeout:     15 * time.Second,
+		UserAgent:   "gosuki (+https://gosuki.net)",
+		RetryFailed: 7 * 24 * time.Hour,
+	}
+}
+
+// Enricher is the module fetching the metadata of the bookmarked pages
+type Enricher struct {
+	ctx    context.Context
+	client *http.Client
+}
+
+func (e *Enricher) ModInfo() modules.ModInfo {
+	return modules.ModInfo{
+		ID: modules.ModID(ModID),
+		New: func() modules.Module {
+			return &Enricher{}
+		},
+	}
+}
+
+func (e *Enricher) Init(ctx *modules.Context) error {
+	if !Config.Enabled {
+		return &modules.ErrModDisabled{Err: errNotEnabled}
+	}
+
+	e.ctx = ctx.Context
+	e.client = &http.Client{Timeout: Config.Timeout}
+	return nil
+}
+
+// Fetch enriches the next batch of bookmarks. The bookmarks are updated in
+// place, no new bookmark is returned.
+func (e *Enricher) Fetch() ([]*gosuki.Bookmark, error) {
+	ctx := e.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	failedBefore := time.Now().Add(-Config.RetryFailed).Unix()
+	urls, err := db.PagesToEnrich(ctx, failedBefore, Config.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(urls) == 0 {
+		return nil, nil
+	}
+
+	log.Info("fetching page metadata", "pages", len(urls))
+	Enrich(ctx, e.client, urls)
+	return nil, nil
+}
+
+func (e *Enricher) Interval() time.Duration {
+	return Config.Interval
+}
+
+// Enrich fetches the pages at urls on a pool of workers and stores their
+// metadata.
+func Enrich(ctx context.Context, client *http.Client, urls []string) {
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for range max(Config.Workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for url := range jobs {
+				enrichPage(ctx, client, url)
+			}
+		}()
+	}
+
+	for _, url := range urls {
+		select {
+		case jobs <- url:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func enrichPage(ctx context.Context, client *http.Client, url string) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	meta := &db.PageMetadata{URL: url, Fetched: time.Now().Unix()}
+	page, status, err := FetchPage(ctx, client, url, Config.UserAgent)
+	meta.Status = status
+	if err != nil {
+		log.Debug("fetching page", "url", url, "err", err)
+		meta.Error = err.Error()
+	} else {
+		meta.Title = page.Title
+		meta.Description = page.Description
+		meta.SiteName = page.SiteName
+		meta.Image = page.Image
+		meta.Canonical = page.Canonical
+		meta.Favicon = page.Favicon
+	}
+
+	if err := db.EnrichBookmark(ctx, meta); err != nil {
+		log.Error("storing page metadata", "url", url, "err", err)
+	}
+}
+
+func init() {
+	config.RegisterConfigurator(ModID, config.AsConfigurator(Config))
+	modules.RegisterModule(&Enricher{})
+}
+
+// interface guards
+var _ watch.Poller = (*Enricher)(nil)
+var _ modules.Initializer = (*Enricher)(nil)
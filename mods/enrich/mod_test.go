@@ -0,0 +1,101 @@
+package enrich
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+func TestMain(m *testing.M) {
+	db.RegisterSqliteHooks()
+	os.Exit(m.Run())
+}
+
+func setupCaches(t *testing.T) {
+	db.Clock = &db.LamportClock{}
+	cache, err := db.NewDB("test_enrich", "", db.DBTypeInMemoryDSN).Init()
+	require.NoError(t, err)
+	l2, err := db.NewDB("test_enrich_l2", "", db.DBTypeInMemoryDSN).Init()
+	require.NoError(t, err)
+	for _, c := range []*db.DB{cache, l2} {
+		require.NoError(t, c.InitSchema(context.Background()))
+	}
+	db.Cache.DB, db.L2Cache.DB = cache, l2
+
+	t.Cleanup(func() {
+		cache.Close()
+		l2.Close()
+		db.Cache.DB, db.L2Cache.DB = nil, nil
+	})
+}
+
+func getCached(t *testing.T, url string) db.RawBookmark {
+	var bk db.RawBookmark
+	require.NoError(t, db.Cache.Handle.Get(&bk,
+		`SELECT * FROM gskbookmarks WHERE URL = ?`, url))
+	return bk
+}
+
+func TestEnrich(t *testing.T) {
+	ctx := context.Background()
+	setupCaches(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.Write([]byte(`<head>
+			<title>Title of ` + r.URL.Path + `</title>
+			<meta name="description" content="about">
+			<link rel="icon" href="/icon.png">
+			</head>`))
+	}))
+	defer srv.Close()
+
+	for _, bk := range []*db.Bookmark{
+		{URL: srv.URL + "/a", Module: "qute"},
+		{URL: srv.URL + "/b", Title: srv.URL + "/b"},
+		{URL: srv.URL + "/gone"},
+	} {
+		require.NoError(t, db.SetCachedBookmark(bk))
+	}
+
+	e := &Enricher{ctx: ctx, client: srv.Client()}
+	marks, err := e.Fetch()
+	require.NoError(t, err)
+	assert.Empty(t, marks)
+
+	bk := getCached(t, srv.URL+"/a")
+	assert.Equal(t, "Title of /a", bk.Metadata)
+	assert.Equal(t, "about", bk.Desc)
+	assert.Equal(t, "qute", bk.Module)
+
+	bk = getCached(t, srv.URL+"/b")
+	assert.Equal(t, "Title of /b", bk.Metadata)
+
+	favicons := db.Favicons(ctx, []string{srv.URL + "/a", srv.URL + "/gone"})
+	assert.Equal(t, map[string]string{srv.URL + "/a": srv.URL + "/icon.png"}, favicons)
+
+	meta, err := db.L2Cache.GetPageMetadata(ctx, srv.URL+"/gone")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusGone, meta.Status)
+	assert.NotEmpty(t, meta.Error)
+
+	// all the pages were looked up
+	urls, err := db.PagesToEnrich(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, urls)
+}
+
+func TestInitDisabled(t *testing.T) {
+	err := (&Enricher{}).Init(nil)
+	assert.ErrorContains(t, err, "not enabled")
+}
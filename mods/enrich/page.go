@@ -0,0 +1,198 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// Maximum size of a page read when looking for its metadata
+const maxPageSize = 1 << 20
+
+var ErrNotHTML = errors.New("not an html page")
+
+// Page is the metadata found in the head of an html page
+type Page struct {
+	Title       string
+	Description string
+	SiteName    string
+	Image       string
+	Canonical   string
+	Favicon     string
+}
+
+// FetchPage downloads the page at pageURL and extracts its metadata. It returns
+// the http status of the response.
+func FetchPage(ctx context.Context, client *http.Client, pageURL, userAgent string) (*Page, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, resp.StatusCode, fmt.Errorf("get %s: %s", pageURL, resp.Status)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); contentType != "" &&
+		(err != nil || !strings.Contains(mediaType, "html")) {
+		return nil, resp.StatusCode, fmt.Errorf("%w: %s", ErrNotHTML, contentType)
+	}
+
+	body, err := charset.NewReader(io.LimitReader(resp.Body, maxPageSize), contentType)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+
+	// relative urls are resolved against the url of the page after redirects
+	page, err := ParsePage(body, resp.Request.URL)
+	return page, resp.StatusCode, err
+}
+
+// ParsePage extracts the metadata from the head of an html document. The
+// `<title>` and meta description are preferred over the OpenGraph and Twitter
+// card properties. Relative urls are resolved against base. The favicon
+// defaults to /favicon.ico.
+func ParsePage(r io.Reader, base *url.URL) (*Page, error) {
+	var title string
+	meta := make(map[string]string)
+	var canonical, icon, touchIcon string
+
+	z := html.NewTokenizer(r)
+loop:
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if errors.Is(z.Err(), io.EOF) {
+				break loop
+			}
+			return nil, z.Err()
+
+		case html.EndTagToken:
+			if name, _ := z.TagName(); string(name) == "head" {
+				break loop
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			attrs := make(map[string]string)
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				attrs[strings.ToLower(string(key))] = string(val)
+			}
+
+			switch string(name) {
+			case "body":
+				break loop
+			case "title":
+				if title == "" && z.Next() == html.TextToken {
+					title = string(z.Text())
+				}
+			case "meta":
+				key := attrs["property"]
+				if key == "" {
+					key = attrs["name"]
+				}
+				key = strings.ToLower(key)
+				if _, ok := meta[key]; key != "" && !ok {
+					meta[key] = attrs["content"]
+				}
+			case "link":
+				rels := strings.Fields(strings.ToLower(attrs["rel"]))
+				for _, rel := range rels {
+					switch {
+					case rel == "canonical" && canonical == "":
+						canonical = attrs["href"]
+					case rel == "icon" && icon == "":
+						icon = attrs["href"]
+					case strings.HasPrefix(rel, "apple-touch-icon") && touchIcon == "":
+						touchIcon = attrs["href"]
+					}
+				}
+			}
+		}
+	}
+
+	page := &Page{
+		Title:       clean(first(title, meta["og:title"], meta["twitter:title"])),
+		Description: clean(first(meta["description"], meta["og:description"], meta["twitter:description"])),
+		SiteName:    clean(meta["og:site_name"]),
+		Image:       resolve(base, first(meta["og:image"], meta["twitter:image"])),
+		Canonical:   resolve(base, first(canonical, meta["og:url"])),
+		Favicon:     resolve(base, first(icon, touchIcon, "/favicon.ico")),
+	}
+	return page, nil
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// clean collapses the whitespace of text
+func clean(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func resolve(base *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
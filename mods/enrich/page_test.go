@@ -0,0 +1,119 @@
+package enrich
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>
+    Gosuki &amp; friends
+  </title>
+  <meta name="description" content="Multi-browser bookmark manager">
+  <meta property="og:title" content="OG title">
+  <meta property="og:description" content="OG description">
+  <meta property="og:site_name" content="Gosuki">
+  <meta property="og:image" content="/img/card.png">
+  <meta name="twitter:image" content="https://cdn.example.com/twitter.png">
+  <link rel="canonical" href="https://gosuki.net/">
+  <link rel="apple-touch-icon" href="/touch.png">
+  <link rel="shortcut icon" href="static/icon.ico">
+</head>
+<body>
+  <title>not the title</title>
+  <link rel="icon" href="/ignored.png">
+</body>
+</html>`
+
+func TestParsePage(t *testing.T) {
+	base, _ := url.Parse("https://example.com/docs/index.html")
+
+	page, err := ParsePage(strings.NewReader(testPage), base)
+	require.NoError(t, err)
+	assert.Equal(t, &Page{
+		Title:       "Gosuki & friends",
+		Description: "Multi-browser bookmark manager",
+		SiteName:    "Gosuki",
+		Image:       "https://example.com/img/card.png",
+		Canonical:   "https://gosuki.net/",
+		Favicon:     "https://example.com/docs/static/icon.ico",
+	}, page)
+}
+
+func TestParsePageFallbacks(t *testing.T) {
+	base, _ := url.Parse("https://example.com/a")
+
+	page, err := ParsePage(strings.NewReader(`<html><head>
+		<meta name="twitter:title" content="Card title">
+		<meta name="twitter:description" content="Card description">
+		<meta name="twitter:image" content="https://cdn.example.com/card.png">
+		<meta property="og:url" content="https://example.com/canonical">
+		<link rel="apple-touch-icon-precomposed" href="/touch.png">
+		</head></html>`), base)
+	require.NoError(t, err)
+	assert.Equal(t, "Card title", page.Title)
+	assert.Equal(t, "Card description", page.Description)
+	assert.Equal(t, "https://cdn.example.com/card.png", page.Image)
+	assert.Equal(t, "https://example.com/canonical", page.Canonical)
+	assert.Equal(t, "https://example.com/touch.png", page.Favicon)
+
+	page, err = ParsePage(strings.NewReader(`<p>no head`), base)
+	require.NoError(t, err)
+	assert.Empty(t, page.Title)
+	assert.Equal(t, "https://example.com/favicon.ico", page.Favicon)
+
+	page, err = ParsePage(strings.NewReader(`<link rel="icon" href="data:image/png;base64,AAAA">`), base)
+	require.NoError(t, err)
+	assert.Empty(t, page.Favicon, "only http urls are kept")
+}
+
+func TestFetchPage(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-agent", r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+		w.Write([]byte("<title>Caf\xe9</title><link rel=icon href=icon.png>"))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/sub/page", http.StatusFound)
+	})
+	mux.HandleFunc("/sub/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html><head><link rel=icon href=icon.png></head></html>"))
+	})
+	mux.HandleFunc("/file.pdf", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte("%PDF"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client := srv.Client()
+
+	page, status, err := FetchPage(ctx, client, srv.URL+"/page", "test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "Café", page.Title)
+	assert.Equal(t, srv.URL+"/icon.png", page.Favicon)
+
+	page, _, err = FetchPage(ctx, client, srv.URL+"/redirect", "test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, srv.URL+"/sub/icon.png", page.Favicon, "resolved against the final url")
+
+	_, status, err = FetchPage(ctx, client, srv.URL+"/missing", "test-agent")
+	require.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	_, _, err = FetchPage(ctx, client, srv.URL+"/file.pdf", "test-agent")
+	assert.ErrorIs(t, err, ErrNotHTML)
+}
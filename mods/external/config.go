@@ -0,0 +1,140 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/modules"
+)
+
+// ModuleConfig configures an external module
+type ModuleConfig struct {
+	// Module id, used in the logs, the bookmark sources and
+	// `disabled-modules`
+	Name string `toml:"name" mapstructure:"name"`
+
+	// Executable and its arguments
+	Command []string `toml:"command" mapstructure:"command"`
+
+	// Extra environment variables as KEY=VALUE
+	Env []string `toml:"env" mapstructure:"env"`
+
+	// Interval at which bookmarks are fetched. Defaults to the interval
+	// returned by the module or [DefaultInterval].
+	Interval time.Duration `toml:"interval" mapstructure:"interval"`
+
+	// Maximum duration of a fetch
+	Timeout time.Duration `toml:"timeout" mapstructure:"timeout"`
+
+	// Options sent to the module in the initialize request
+	Options map[string]any `toml:"options" mapstructure:"options"`
+}
+
+type ExternalConfig struct {
+	Modules []ModuleConfig `toml:"modules" mapstructure:"modules"`
+}
+
+var Config = &ExternalConfig{
+	Modules: []ModuleConfig{},
+}
+
+// registered external modules by name
+var registered = map[string]*Module{}
+
+func (c *ModuleConfig) validate() error {
+	if c.Name == "" {
+		return errors.New("missing name")
+	}
+	if len(c.Command) == 0 || c.Command[0] == "" {
+		return errors.New("missing command")
+	}
+	return nil
+}
+
+// moduleConfig returns the current config of the module called name
+func moduleConfig(name string) *ModuleConfig {
+	for i := range Config.Modules {
+		if Config.Modules[i].Name == name {
+			return &Config.Modules[i]
+		}
+	}
+	return nil
+}
+
+// registerModules registers a module for each configured external module.
+// Modules added to the config after startup need a restart of the daemon.
+func registerModules(_ context.Context, _ *cli.Command) error {
+	for _, mc := range Config.Modules {
+		if err := mc.validate(); err != nil {
+			log.Warn("ignoring external module", "name", mc.Name, "err", err)
+			continue
+		}
+		if _, ok := registered[mc.Name]; ok {
+			continue
+		}
+		if isRegistered(mc.Name) {
+			log.Warn("ignoring external module", "name", mc.Name,
+				"err", fmt.Errorf("module <%s> already exists", mc.Name))
+			continue
+		}
+
+		mod := newModule(mc.Name)
+		registered[mc.Name] = mod
+		modules.RegisterModule(mod)
+
+		if slices.Contains(config.GlobalConfig.DisabledModules, mc.Name) {
+			modules.Disable(modules.ModID(mc.Name))
+		}
+	}
+	return nil
+}
+
+func isRegistered(name string) bool {
+	for _, mod := range modules.GetAllModules() {
+		if string(mod.ModInfo().ID) == name {
+			return true
+		}
+	}
+	for _, mod := range modules.GetBrowserModules() {
+		if string(mod.ModInfo().ID) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	config.RegisterConfigurator(ModID, config.AsConfigurator(Config))
+	config.RegisterResetHook(func() {
+		Config.Modules = []ModuleConfig{}
+	})
+	config.RegisterConfReadyHooks(registerModules)
+}
@@ -0,0 +1,522 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+// Package external runs third-party bookmark sources as separate processes.
+// An external module is an executable speaking newline-delimited JSON-RPC 2.0
+// over its stdin and stdout. It can be written in any language and is
+// configured with:
+//
+//	[[external.modules]]
+//	name = "mastodon"
+//	command = ["gosuki-mastodon", "--verbose"]
+//	env = ["MASTODON_TOKEN=secret"]
+//	interval = "30m"
+//	options = { account = "me@example.com" }
+//
+// gosuki sends the following requests:
+//
+//   - initialize: sent once the process is started with the name of the
+//     module, the gosuki version and the options of the config. The module
+//     returns its name, version and optional poll interval in seconds.
+//   - fetch: returns the bookmarks of the source as {"bookmarks": [...]}.
+//     Each bookmark has an url and optional title, tags, desc and created_at
+//     (unix time).
+//   - shutdown: sent before closing the stdin of the module.
+//
+// The module may send the notifications:
+//
+//   - bookmarks: pushes a batch of bookmarks, same params as the fetch result.
+//   - log: {"level": "info", "message": "..."} written to the gosuki log.
+//
+// The stderr of the module is also logged. A module exiting unexpectedly is
+// restarted with an increasing delay.
+package external
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/build"
+	"github.com/blob42/gosuki/pkg/logging"
+	"github.com/blob42/gosuki/pkg/modules"
+	"github.com/blob42/gosuki/pkg/watch"
+)
+
+const (
+	ModID = "external"
+
+	DefaultInterval = time.Hour
+	DefaultTimeout  = time.Minute
+
+	handshakeTimeout = 10 * time.Second
+	shutdownTimeout  = 5 * time.Second
+
+	maxRestartDelay = 5 * time.Minute
+
+	// a module running longer than this is restarted without delay
+	restartResetAfter = 10 * time.Minute
+)
+
+var (
+	log = logging.GetLogger(ModID)
+
+	// delay before the first restart of a crashed module
+	restartDelay = time.Second
+
+	// stores the bookmarks pushed by the modules
+	loadBookmarks = func(load func() ([]*gosuki.Bookmark, error), modName string) error {
+		return database.LoadBookmarks(load, modName)
+	}
+
+	errNotConfigured = errors.New("not configured")
+	errNotRunning    = errors.New("module is not running")
+)
+
+// Bookmark is a bookmark sent by an external module
+type Bookmark struct {
+	URL     string   `json:"url"`
+	Title   string   `json:"title,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Desc    string   `json:"desc,omitempty"`
+	Created uint64   `json:"created_at,omitempty"`
+}
+
+type initializeParams struct {
+	Name    string         `json:"name"`
+	Version string         `json:"version"`
+	Options map[string]any `json:"options,omitempty"`
+}
+
+// Info is returned by the module in the initialize request
+type Info struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+
+	// poll interval in seconds
+	Interval int `json:"interval,omitempty"`
+}
+
+type bookmarksParams struct {
+	Bookmarks []Bookmark `json:"bookmarks"`
+}
+
+type logParams struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// Module runs an external module and polls its bookmarks. There is one
+// instance and one process per configured module.
+type Module struct {
+	name string
+	log  *logging.Logger
+
+	mu      sync.Mutex
+	cfg     ModuleConfig
+	info    Info
+	proc    *process
+	stop    chan struct{}
+	stopped bool
+
+	// closed when the supervisor returns
+	done chan struct{}
+
+	batches chan []*gosuki.Bookmark
+}
+
+// process is a running instance of the module executable
+type process struct {
+	cmd     *exec.Cmd
+	conn    *conn
+	started time.Time
+
+	// closed when the process exited
+	exited chan struct{}
+	err    error
+}
+
+func newModule(name string) *Module {
+	return &Module{
+		name: name,
+		log:  log.With("module", name),
+	}
+}
+
+func (m *Module) ModInfo() modules.ModInfo {
+	return modules.ModInfo{
+		ID: modules.ModID(m.name),
+		New: func() modules.Module {
+			return m
+		},
+	}
+}
+
+// Init starts the module process and waits for the handshake
+func (m *Module) Init(_ *modules.Context) error {
+	cfg := moduleConfig(m.name)
+	if cfg == nil {
+		return &modules.ErrModDisabled{Err: errNotConfigured}
+	}
+
+	m.mu.Lock()
+	m.cfg = *cfg
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+	m.batches = make(chan []*gosuki.Bookmark, 16)
+	m.stopped = false
+	m.mu.Unlock()
+
+	p, err := m.start()
+	if err != nil {
+		close(m.done)
+		return err
+	}
+	m.setProcess(p)
+
+	go m.supervise(p)
+	go m.loadBatches()
+	return nil
+}
+
+// start spawns the module and sends the initialize request
+func (m *Module) start() (*process, error) {
+	m.mu.Lock()
+	cfg := m.cfg
+	m.mu.Unlock()
+
+	cmd := exec.Command(cfg.Command[0], cfg.Command[1:]...)
+	cmd.Env = append(os.Environ(), cfg.Env...)
+	cmd.Env = append(cmd.Env, "GOSUKI_MODULE="+m.name)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting %s: %w", cfg.Command[0], err)
+	}
+	m.log.Debug("started", "pid", cmd.Process.Pid)
+
+	p := &process{
+		cmd:     cmd,
+		conn:    newConn(stdout, stdin, m.notify),
+		started: time.Now(),
+		exited:  make(chan struct{}),
+	}
+
+	stderrDone := make(chan struct{})
+	go func() {
+		defer close(stderrDone)
+		m.logStderr(stderr)
+	}()
+
+	// the pipes must be read entirely before waiting for the process
+	go func() {
+		<-p.conn.closed
+		<-stderrDone
+		p.err = cmd.Wait()
+		close(p.exited)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer cancel()
+
+	var info Info
+	params := initializeParams{
+		Name:    m.name,
+		Version: build.Version(),
+		Options: cfg.Options,
+	}
+	if err := p.conn.call(ctx, "initialize", params, &info); err != nil {
+		p.kill()
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.info = info
+	m.mu.Unlock()
+
+	m.log.Info("initialized", "name", info.Name, "version", info.Version)
+	return p, nil
+}
+
+// setProcess sets the running process. It returns false if the module was
+// stopped in the meantime.
+func (m *Module) setProcess(p *process) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopped {
+		return false
+	}
+	m.proc = p
+	return true
+}
+
+func (m *Module) current() *process {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.proc
+}
+
+// supervise restarts the module when its process exits
+func (m *Module) supervise(p *process) {
+	defer close(m.done)
+
+	delay := restartDelay
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-p.exited:
+		}
+
+		m.mu.Lock()
+		m.proc = nil
+		m.mu.Unlock()
+		m.log.Error("module exited", "err", p.exitError())
+
+		if time.Since(p.started) > restartResetAfter {
+			delay = restartDelay
+		}
+
+		for {
+			m.log.Info("restarting module", "delay", delay)
+			select {
+			case <-m.stop:
+				return
+			case <-time.After(delay):
+			}
+			delay = min(delay*2, maxRestartDelay)
+
+			next, err := m.start()
+			if err != nil {
+				m.log.Error("restarting module", "err", err)
+				continue
+			}
+			if !m.setProcess(next) {
+				next.shutdown()
+				return
+			}
+			p = next
+			break
+		}
+	}
+}
+
+// loadBatches stores the bookmarks pushed by the module
+func (m *Module) loadBatches() {
+	for {
+		select {
+		case <-m.stop:
+			return
+		case batch := <-m.batches:
+			err := loadBookmarks(func() ([]*gosuki.Bookmark, error) {
+				return batch, nil
+			}, m.name)
+			if err != nil {
+				m.log.Error("loading pushed bookmarks", "err", err)
+			}
+		}
+	}
+}
+
+// notify handles the notifications sent by the module
+func (m *Module) notify(method string, params json.RawMessage) {
+	switch method {
+	case "bookmarks":
+		var batch bookmarksParams
+		if err := json.Unmarshal(params, &batch); err != nil {
+			m.log.Warn("invalid bookmarks notification", "err", err)
+			return
+		}
+		m.log.Debug("received bookmarks", "count", len(batch.Bookmarks))
+
+		select {
+		case m.batches <- m.bookmarks(batch.Bookmarks):
+		case <-m.stop:
+		}
+	case "log":
+		var msg logParams
+		if err := json.Unmarshal(params, &msg); err != nil {
+			m.log.Warn("invalid log notification", "err", err)
+			return
+		}
+		switch strings.ToLower(msg.Level) {
+		case "trace":
+			m.log.Trace(msg.Message)
+		case "debug":
+			m.log.Debug(msg.Message)
+		case "warn", "warning":
+			m.log.Warn(msg.Message)
+		case "error":
+			m.log.Error(msg.Message)
+		default:
+			m.log.Info(msg.Message)
+		}
+	default:
+		m.log.Debug("unknown notification", "method", method)
+	}
+}
+
+func (m *Module) logStderr(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		m.log.Info(scanner.Text(), "stream", "stderr")
+	}
+}
+
+// bookmarks converts the bookmarks sent by the module
+func (m *Module) bookmarks(marks []Bookmark) []*gosuki.Bookmark {
+	result := make([]*gosuki.Bookmark, 0, len(marks))
+	for _, mark := range marks {
+		if mark.URL == "" {
+			m.log.Warn("ignoring bookmark without url")
+			continue
+		}
+		result = append(result, &gosuki.Bookmark{
+			URL:     mark.URL,
+			Title:   mark.Title,
+			Tags:    mark.Tags,
+			Desc:    mark.Desc,
+			Module:  m.name,
+			Created: mark.Created,
+		})
+	}
+	return result
+}
+
+// Fetch calls the fetch method of the module
+func (m *Module) Fetch() ([]*gosuki.Bookmark, error) {
+	p := m.current()
+	if p == nil {
+		return nil, errNotRunning
+	}
+
+	m.mu.Lock()
+	timeout := m.cfg.Timeout
+	m.mu.Unlock()
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var result bookmarksParams
+	if err := p.conn.call(ctx, "fetch", nil, &result); err != nil {
+		return nil, err
+	}
+	return m.bookmarks(result.Bookmarks), nil
+}
+
+// Interval returns the configured interval, else the interval returned by
+// the module in the handshake.
+func (m *Module) Interval() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cfg.Interval > 0 {
+		return m.cfg.Interval
+	}
+	if m.info.Interval > 0 {
+		return time.Duration(m.info.Interval) * time.Second
+	}
+	return DefaultInterval
+}
+
+// Shutdown stops the module process
+func (m *Module) Shutdown() error {
+	m.mu.Lock()
+	if m.stopped || m.stop == nil {
+		m.mu.Unlock()
+		return nil
+	}
+	m.stopped = true
+	close(m.stop)
+	p := m.proc
+	m.proc = nil
+	m.mu.Unlock()
+
+	if p != nil {
+		p.shutdown()
+	}
+	<-m.done
+	return nil
+}
+
+// shutdown asks the process to exit and kills it after [shutdownTimeout]
+func (p *process) shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := p.conn.call(ctx, "shutdown", nil, nil); err != nil {
+		log.Debug("shutdown request", "err", err)
+	}
+	p.conn.Close()
+
+	select {
+	case <-p.exited:
+	case <-ctx.Done():
+		p.kill()
+	}
+}
+
+func (p *process) kill() {
+	if err := p.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		log.Error("killing module", "err", err)
+	}
+	<-p.exited
+}
+
+func (p *process) exitError() error {
+	if p.err != nil {
+		return p.err
+	}
+	return errors.New("exited")
+}
+
+// interface guards
+var (
+	_ modules.Initializer = (*Module)(nil)
+	_ modules.Shutdowner  = (*Module)(nil)
+	_ watch.Poller        = (*Module)(nil)
+)
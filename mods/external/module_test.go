@@ -0,0 +1,186 @@
+package external
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/modules"
+)
+
+// the test binary is used as external module when this variable is set
+const fakeModuleEnv = "GOSUKI_EXTERNAL_TEST"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(fakeModuleEnv); mode != "" {
+		fakeModule(mode)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeModule implements the module side of the protocol. In crash mode the
+// process exits on its first fetch, using the file FAKE_CRASHED to only
+// crash once.
+func fakeModule(mode string) {
+	out := json.NewEncoder(os.Stdout)
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req rpcMessage
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			os.Exit(1)
+		}
+
+		resp := rpcMessage{JSONRPC: "2.0", ID: req.ID}
+		switch req.Method {
+		case "initialize":
+			var params initializeParams
+			json.Unmarshal(req.Params, &params)
+			resp.Result, _ = json.Marshal(Info{
+				Name:     "fake-" + params.Name,
+				Version:  fmt.Sprint(params.Options["version"]),
+				Interval: 42,
+			})
+			out.Encode(resp)
+			fmt.Fprintln(os.Stderr, "initialized")
+
+			if mode == "push" {
+				params, _ := json.Marshal(bookmarksParams{Bookmarks: []Bookmark{
+					{URL: "https://pushed.example.com", Tags: []string{"push"}},
+				}})
+				out.Encode(rpcMessage{JSONRPC: "2.0", Method: "log",
+					Params: json.RawMessage(`{"level":"warn","message":"pushing"}`)})
+				out.Encode(rpcMessage{JSONRPC: "2.0", Method: "bookmarks", Params: params})
+			}
+			continue
+		case "fetch":
+			switch mode {
+			case "error":
+				resp.Error = &RPCError{Code: 1, Message: "no token"}
+			case "crash":
+				if _, err := os.Stat(os.Getenv("FAKE_CRASHED")); err != nil {
+					os.WriteFile(os.Getenv("FAKE_CRASHED"), nil, 0o644)
+					os.Exit(3)
+				}
+				fallthrough
+			default:
+				resp.Result, _ = json.Marshal(bookmarksParams{Bookmarks: []Bookmark{
+					{URL: "https://example.com", Title: "Example", Tags: []string{"a", "b"}, Created: 1700000000},
+					{Title: "no url"},
+				}})
+			}
+		case "shutdown":
+			out.Encode(resp)
+			os.Exit(0)
+		default:
+			resp.Error = &RPCError{Code: codeMethodNotFound, Message: "unknown"}
+		}
+		out.Encode(resp)
+	}
+}
+
+func setupModule(t *testing.T, mode string, cfg ModuleConfig) *Module {
+	t.Helper()
+
+	cfg.Name = "fake"
+	cfg.Command = []string{os.Args[0]}
+	cfg.Env = append(cfg.Env, fakeModuleEnv+"="+mode)
+	cfg.Options = map[string]any{"version": "1.2.3"}
+	Config.Modules = []ModuleConfig{cfg}
+	t.Cleanup(func() { Config.Modules = []ModuleConfig{} })
+
+	mod := newModule("fake")
+	require.NoError(t, mod.Init(&modules.Context{Context: t.Context()}))
+	t.Cleanup(func() { mod.Shutdown() })
+	return mod
+}
+
+func TestFetch(t *testing.T) {
+	mod := setupModule(t, "ok", ModuleConfig{})
+
+	assert.Equal(t, "fake-fake", mod.info.Name)
+	assert.Equal(t, "1.2.3", mod.info.Version)
+	assert.Equal(t, 42*time.Second, mod.Interval())
+
+	marks, err := mod.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, []*gosuki.Bookmark{{
+		URL:     "https://example.com",
+		Title:   "Example",
+		Tags:    []string{"a", "b"},
+		Module:  "fake",
+		Created: 1700000000,
+	}}, marks)
+
+	require.NoError(t, mod.Shutdown())
+	_, err = mod.Fetch()
+	assert.ErrorIs(t, err, errNotRunning)
+}
+
+func TestConfigInterval(t *testing.T) {
+	mod := setupModule(t, "ok", ModuleConfig{Interval: 5 * time.Minute})
+	assert.Equal(t, 5*time.Minute, mod.Interval())
+}
+
+func TestFetchError(t *testing.T) {
+	mod := setupModule(t, "error", ModuleConfig{})
+
+	_, err := mod.Fetch()
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, "no token", rpcErr.Message)
+}
+
+func TestPushedBookmarks(t *testing.T) {
+	loaded := make(chan []*gosuki.Bookmark, 1)
+	load := loadBookmarks
+	loadBookmarks = func(load func() ([]*gosuki.Bookmark, error), modName string) error {
+		assert.Equal(t, "fake", modName)
+		marks, err := load()
+		loaded <- marks
+		return err
+	}
+	t.Cleanup(func() { loadBookmarks = load })
+
+	setupModule(t, "push", ModuleConfig{})
+
+	select {
+	case marks := <-loaded:
+		require.Len(t, marks, 1)
+		assert.Equal(t, "https://pushed.example.com", marks[0].URL)
+		assert.Equal(t, []string{"push"}, marks[0].Tags)
+		assert.Equal(t, "fake", marks[0].Module)
+	case <-time.After(5 * time.Second):
+		t.Fatal("pushed bookmarks not loaded")
+	}
+}
+
+func TestRestart(t *testing.T) {
+	restartDelay = 10 * time.Millisecond
+	t.Cleanup(func() { restartDelay = time.Second })
+
+	crashed := filepath.Join(t.TempDir(), "crashed")
+	mod := setupModule(t, "crash", ModuleConfig{Env: []string{"FAKE_CRASHED=" + crashed}})
+
+	_, err := mod.Fetch()
+	require.Error(t, err)
+
+	require.Eventually(t, func() bool {
+		marks, err := mod.Fetch()
+		return err == nil && len(marks) == 1
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestNotConfigured(t *testing.T) {
+	err := newModule("missing").Init(&modules.Context{Context: t.Context()})
+	var disabled *modules.ErrModDisabled
+	assert.ErrorAs(t, err, &disabled)
+}
@@ -0,0 +1,219 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package external
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Messages are JSON-RPC 2.0 objects, one per line.
+//
+// gosuki sends the `initialize`, `fetch` and `shutdown` requests. The module
+// answers them and may send the `bookmarks` and `log` notifications at any
+// time. See the package documentation.
+
+const (
+	jsonRPCVersion = "2.0"
+
+	// maximum size of a message, a batch of bookmarks fits in a line
+	maxMessageSize = 64 << 20
+
+	codeMethodNotFound = -32601
+)
+
+var errConnClosed = errors.New("module closed its stdout")
+
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *uint64         `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is an error returned by an external module
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// conn is a JSON-RPC connection to the stdio of a module
+type conn struct {
+	w   io.WriteCloser
+	wmu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan *rpcMessage
+	err     error
+
+	// called for each notification sent by the module
+	notify func(method string, params json.RawMessage)
+
+	// closed when the module closes its stdout
+	closed chan struct{}
+}
+
+func newConn(r io.Reader, w io.WriteCloser, notify func(string, json.RawMessage)) *conn {
+	c := &conn{
+		w:       w,
+		pending: make(map[uint64]chan *rpcMessage),
+		notify:  notify,
+		closed:  make(chan struct{}),
+	}
+	go c.read(r)
+	return c
+}
+
+func (c *conn) read(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var msg rpcMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			log.Warn("invalid message from module", "err", err, "line", string(line))
+			continue
+		}
+
+		switch {
+		case msg.Method == "" && msg.ID != nil:
+			c.mu.Lock()
+			ch, ok := c.pending[*msg.ID]
+			delete(c.pending, *msg.ID)
+			c.mu.Unlock()
+			if ok {
+				ch <- &msg
+			}
+		case msg.ID == nil:
+			c.notify(msg.Method, msg.Params)
+		default:
+			// modules do not call gosuki
+			c.send(&rpcMessage{
+				ID:    msg.ID,
+				Error: &RPCError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method},
+			})
+		}
+	}
+
+	err := scanner.Err()
+	if err == nil {
+		err = errConnClosed
+	}
+
+	c.mu.Lock()
+	c.err = err
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+	c.mu.Unlock()
+	close(c.closed)
+}
+
+func (c *conn) send(msg *rpcMessage) error {
+	msg.JSONRPC = jsonRPCVersion
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	_, err = c.w.Write(append(data, '\n'))
+	return err
+}
+
+// call sends a request and decodes its result into result if not nil
+func (c *conn) call(ctx context.Context, method string, params, result any) error {
+	var raw json.RawMessage
+	if params != nil {
+		var err error
+		if raw, err = json.Marshal(params); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return c.err
+	}
+	c.nextID++
+	id := c.nextID
+	ch := make(chan *rpcMessage, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	if err := c.send(&rpcMessage{ID: &id, Method: method, Params: raw}); err != nil {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return fmt.Errorf("%s: %w", method, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return fmt.Errorf("%s: %w", method, ctx.Err())
+	case resp, ok := <-ch:
+		if !ok {
+			c.mu.Lock()
+			err := c.err
+			c.mu.Unlock()
+			return fmt.Errorf("%s: %w", method, err)
+		}
+		if resp.Error != nil {
+			return fmt.Errorf("%s: %w", method, resp.Error)
+		}
+		if result != nil && len(resp.Result) > 0 {
+			if err := json.Unmarshal(resp.Result, result); err != nil {
+				return fmt.Errorf("%s: decoding result: %w", method, err)
+			}
+		}
+		return nil
+	}
+}
+
+// Close closes the stdin of the module
+func (c *conn) Close() error {
+	return c.w.Close()
+}
@@ -0,0 +1,79 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package forges
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// number of repositories per page, the default maximum of Gitea
+const giteaPageSize = 50
+
+// gitea is the API of Gitea and Forgejo
+type gitea struct {
+	client *http.Client
+	base   string
+	token  string
+}
+
+type giteaRepo struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	HTMLURL     string   `json:"html_url"`
+	Description string   `json:"description"`
+	Language    string   `json:"language"`
+	Topics      []string `json:"topics"`
+}
+
+func (g *gitea) starred(ctx context.Context, page int, etag string) (*starPage, error) {
+	url := fmt.Sprintf("%s/api/v1/user/starred?page=%d&limit=%d", g.base, page, giteaPageSize)
+	header := http.Header{"Authorization": {"token " + g.token}}
+
+	var repos []giteaRepo
+	resp, err := getJSON(ctx, g.client, url, header, etag, &repos)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		return &starPage{notModified: true}, nil
+	}
+
+	result := &starPage{
+		etag: resp.Header.Get("ETag"),
+		next: strings.Contains(resp.Header.Get("Link"), `rel="next"`),
+	}
+	for _, r := range repos {
+		result.repos = append(result.repos, repo{
+			URL:      r.HTMLURL,
+			Name:     r.Name,
+			Desc:     r.Description,
+			Language: r.Language,
+			Topics:   r.Topics,
+			id:       r.ID,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,105 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package forges
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const gitlabPageSize = 100
+
+// gitlab is the API of GitLab
+type gitlab struct {
+	client *http.Client
+	base   string
+	token  string
+}
+
+type gitlabProject struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	WebURL      string   `json:"web_url"`
+	Description string   `json:"description"`
+	Topics      []string `json:"topics"`
+
+	// replaced by topics in GitLab 14.0
+	TagList []string `json:"tag_list"`
+}
+
+func (g *gitlab) header() http.Header {
+	return http.Header{"Private-Token": {g.token}}
+}
+
+func (g *gitlab) starred(ctx context.Context, page int, etag string) (*starPage, error) {
+	url := fmt.Sprintf("%s/api/v4/projects?starred=true&page=%d&per_page=%d", g.base, page, gitlabPageSize)
+
+	var projects []gitlabProject
+	resp, err := getJSON(ctx, g.client, url, g.header(), etag, &projects)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		return &starPage{notModified: true}, nil
+	}
+
+	result := &starPage{
+		etag: resp.Header.Get("ETag"),
+		next: resp.Header.Get("X-Next-Page") != "",
+	}
+	for _, p := range projects {
+		topics := p.Topics
+		if topics == nil {
+			topics = p.TagList
+		}
+		result.repos = append(result.repos, repo{
+			URL:    p.WebURL,
+			Name:   p.Name,
+			Desc:   p.Description,
+			Topics: topics,
+			id:     p.ID,
+		})
+	}
+	return result, nil
+}
+
+// language returns the main language of a project, GitLab only lists the
+// languages of a project with their percentage in a separate endpoint
+func (g *gitlab) language(ctx context.Context, r repo) (string, error) {
+	url := fmt.Sprintf("%s/api/v4/projects/%d/languages", g.base, r.id)
+
+	var languages map[string]float64
+	if _, err := getJSON(ctx, g.client, url, g.header(), "", &languages); err != nil {
+		return "", err
+	}
+
+	var lang string
+	var best float64
+	for name, percent := range languages {
+		if percent > best || (percent == best && name < lang) {
+			lang, best = name, percent
+		}
+	}
+	return lang, nil
+}
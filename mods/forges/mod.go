@@ -0,0 +1,327 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+// Package forges bookmarks the repositories starred on Gitea, Forgejo and
+// GitLab instances, the same way the github module does for GitHub. Several
+// accounts can be configured, on any instance:
+//
+//	[[forges.accounts]]
+//	type = "forgejo"
+//	url = "https://codeberg.org"
+//	token = "..."
+//
+// Pages are fetched with their ETag, pages that did not change since the last
+// poll are not downloaded again and only newly starred repositories are
+// returned.
+package forges
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/logging"
+	"github.com/blob42/gosuki/pkg/modules"
+	"github.com/blob42/gosuki/pkg/watch"
+)
+
+const (
+	ModID = "forges"
+
+	DefaultSyncInterval = 6 * time.Hour
+
+	// stop paging on misbehaving servers
+	maxPages = 1000
+
+	requestTimeout = 30 * time.Second
+)
+
+var (
+	Config = NewForgesConfig()
+	log    = logging.GetLogger(ModID)
+
+	errNoAccounts = errors.New("no accounts configured")
+)
+
+// Account is a user account on a forge instance
+type Account struct {
+	// gitea, forgejo or gitlab
+	Type string `toml:"type" mapstructure:"type"`
+
+	// Base url of the instance
+	URL string `toml:"url" mapstructure:"url"`
+
+	// API token allowed to read the starred repositories of the user
+	Token string `toml:"token" mapstructure:"token"`
+}
+
+type ForgesConfig struct {
+	SyncInterval time.Duration `toml:"sync-interval" mapstructure:"sync-interval"`
+
+	Accounts []Account `toml:"accounts" mapstructure:"accounts"`
+}
+
+func NewForgesConfig() *ForgesConfig {
+	return &ForgesConfig{
+		SyncInterval: DefaultSyncInterval,
+		Accounts:     []Account{},
+	}
+}
+
+// repo is a starred repository
+type repo struct {
+	URL      string
+	Name     string
+	Desc     string
+	Language string
+	Topics   []string
+
+	// id of the repository on the instance
+	id int64
+}
+
+// bookmark returns the bookmark of the repo, tagged with its topics and
+// language
+func (r repo) bookmark() *gosuki.Bookmark {
+	tags := slices.Clone(r.Topics)
+	if lang := strings.ToLower(r.Language); lang != "" && !slices.Contains(tags, lang) {
+		tags = append(tags, lang)
+	}
+	return &gosuki.Bookmark{
+		URL:    r.URL,
+		Title:  r.Name,
+		Desc:   r.Desc,
+		Tags:   tags,
+		Module: ModID,
+	}
+}
+
+// starPage is a page of starred repositories
+type starPage struct {
+	repos []repo
+	etag  string
+	next  bool
+
+	// the page did not change since the ETag sent with the request
+	notModified bool
+}
+
+// forge is the API of a forge
+type forge interface {
+	// starred fetches a page of the starred repositories, starting at 1.
+	// etag is sent with the request when not empty.
+	starred(ctx context.Context, page int, etag string) (*starPage, error)
+}
+
+// languageFetcher is implemented by the forges that do not return the
+// language of the repositories in the list of stars
+type languageFetcher interface {
+	language(ctx context.Context, r repo) (string, error)
+}
+
+func newForge(client *http.Client, acc Account) (forge, error) {
+	base := strings.TrimRight(acc.URL, "/")
+	if base == "" {
+		return nil, errors.New("missing url")
+	}
+	if acc.Token == "" {
+		return nil, modules.ErrMissingCredentials
+	}
+
+	switch strings.ToLower(acc.Type) {
+	case "gitea", "forgejo":
+		return &gitea{client: client, base: base, token: acc.Token}, nil
+	case "gitlab":
+		return &gitlab{client: client, base: base, token: acc.Token}, nil
+	default:
+		return nil, fmt.Errorf("unknown forge type %q", acc.Type)
+	}
+}
+
+// pageState is what is known of a page fetched in a previous poll
+type pageState struct {
+	etag string
+	next bool
+}
+
+// account is the polling state of a configured account
+type account struct {
+	Account
+	forge forge
+
+	pages map[int]pageState
+
+	// urls of the repositories already returned
+	seen map[string]bool
+}
+
+// fetch returns the repositories starred since the last poll
+func (acc *account) fetch(ctx context.Context) ([]*gosuki.Bookmark, error) {
+	var bookmarks []*gosuki.Bookmark
+	lf, _ := acc.forge.(languageFetcher)
+
+	page := 1
+	for ; page <= maxPages; page++ {
+		known := acc.pages[page]
+		result, err := acc.forge.starred(ctx, page, known.etag)
+		if err != nil {
+			return bookmarks, err
+		}
+
+		if result.notModified {
+			log.Debug("page not modified", "url", acc.URL, "page", page)
+			if !known.next {
+				break
+			}
+			continue
+		}
+
+		for _, r := range result.repos {
+			if acc.seen[r.URL] || r.URL == "" {
+				continue
+			}
+			if lf != nil && r.Language == "" {
+				if r.Language, err = lf.language(ctx, r); err != nil {
+					log.Warn("fetching language", "repo", r.URL, "err", err)
+				}
+			}
+			acc.seen[r.URL] = true
+			bookmarks = append(bookmarks, r.bookmark())
+		}
+
+		acc.pages[page] = pageState{etag: result.etag, next: result.next}
+		if !result.next || len(result.repos) == 0 {
+			break
+		}
+	}
+
+	// forget the pages past the last one
+	for p := range acc.pages {
+		if p > page {
+			delete(acc.pages, p)
+		}
+	}
+
+	return bookmarks, nil
+}
+
+// Forges is the module polling the stars of the configured accounts
+type Forges struct {
+	ctx    context.Context
+	client *http.Client
+
+	// polling state of the accounts, by type, url and token
+	accounts map[string]*account
+}
+
+func (f *Forges) ModInfo() modules.ModInfo {
+	return modules.ModInfo{
+		ID: modules.ModID(ModID),
+		New: func() modules.Module {
+			return &Forges{}
+		},
+	}
+}
+
+func (f *Forges) Init(ctx *modules.Context) error {
+	if len(Config.Accounts) == 0 {
+		return &modules.ErrModDisabled{Err: errNoAccounts}
+	}
+
+	f.ctx = ctx.Context
+	f.client = &http.Client{Timeout: requestTimeout}
+	f.accounts = map[string]*account{}
+	return nil
+}
+
+// account returns the polling state of acc, the state is kept when the
+// config is reloaded
+func (f *Forges) account(acc Account) (*account, error) {
+	key := strings.Join([]string{strings.ToLower(acc.Type), acc.URL, acc.Token}, "\x00")
+	if state, ok := f.accounts[key]; ok {
+		return state, nil
+	}
+
+	fg, err := newForge(f.client, acc)
+	if err != nil {
+		return nil, err
+	}
+	state := &account{
+		Account: acc,
+		forge:   fg,
+		pages:   map[int]pageState{},
+		seen:    map[string]bool{},
+	}
+	f.accounts[key] = state
+	return state, nil
+}
+
+// Fetch returns the repositories starred on all the accounts since the last
+// poll. An account failing does not prevent fetching the others.
+func (f *Forges) Fetch() ([]*gosuki.Bookmark, error) {
+	ctx := f.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var bookmarks []*gosuki.Bookmark
+	var errs []error
+	for _, acc := range Config.Accounts {
+		state, err := f.account(acc)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s account %s: %w", acc.Type, acc.URL, err))
+			continue
+		}
+
+		log.Info("fetching stars", "type", acc.Type, "url", acc.URL)
+		marks, err := state.fetch(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s account %s: %w", acc.Type, acc.URL, err))
+		}
+		bookmarks = append(bookmarks, marks...)
+	}
+
+	return bookmarks, errors.Join(errs...)
+}
+
+// Interval at which the module should be run
+func (f *Forges) Interval() time.Duration {
+	return Config.SyncInterval
+}
+
+func init() {
+	config.RegisterConfigurator(ModID, config.AsConfigurator(Config))
+	config.RegisterResetHook(func() {
+		Config.Accounts = []Account{}
+	})
+	modules.RegisterModule(&Forges{})
+}
+
+// interface guards
+var _ watch.Poller = (*Forges)(nil)
+var _ modules.Initializer = (*Forges)(nil)
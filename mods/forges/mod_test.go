@@ -0,0 +1,214 @@
+package forges
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+// fakeForge serves pages of starred repositories, one per page
+type fakeForge struct {
+	mu    sync.Mutex
+	pages []any
+
+	// requests answered with a body
+	downloads int
+	etags     bool
+}
+
+func (f *fakeForge) page(w http.ResponseWriter, r *http.Request, next func(w http.ResponseWriter, page int)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 || page > len(f.pages) {
+		w.Write([]byte("[]"))
+		return
+	}
+
+	body, _ := json.Marshal(f.pages[page-1])
+	if f.etags {
+		etag := fmt.Sprintf(`W/"%x"`, sha1.Sum(body))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+	}
+	if page < len(f.pages) {
+		next(w, page+1)
+	}
+	f.downloads++
+	w.Write(body)
+}
+
+func (f *fakeForge) setPages(pages ...any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pages = pages
+}
+
+func newForges(t *testing.T, accounts ...Account) *Forges {
+	old := Config.Accounts
+	Config.Accounts = accounts
+	t.Cleanup(func() { Config.Accounts = old })
+
+	return &Forges{
+		ctx:      context.Background(),
+		client:   http.DefaultClient,
+		accounts: map[string]*account{},
+	}
+}
+
+func byURL(marks []*gosuki.Bookmark) map[string]*gosuki.Bookmark {
+	result := map[string]*gosuki.Bookmark{}
+	for _, bk := range marks {
+		result[bk.URL] = bk
+	}
+	return result
+}
+
+func TestGitea(t *testing.T) {
+	forge := &fakeForge{etags: true}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/user/starred", r.URL.Path)
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		forge.page(w, r, func(w http.ResponseWriter, page int) {
+			w.Header().Set("Link", fmt.Sprintf(`<%s?page=%d>; rel="next"`, r.URL.Path, page))
+		})
+	}))
+	defer srv.Close()
+
+	gosukiRepo := map[string]any{
+		"id": 1, "name": "gosuki", "html_url": srv.URL + "/blob42/gosuki",
+		"clone_url": srv.URL + "/blob42/gosuki.git", "description": "bookmark manager",
+		"language": "Go", "topics": []string{"bookmarks", "go"},
+	}
+	dotfiles := map[string]any{
+		"id": 2, "name": "dotfiles", "html_url": srv.URL + "/alice/dotfiles", "language": "Shell",
+	}
+	forge.setPages([]any{gosukiRepo}, []any{dotfiles})
+
+	f := newForges(t, Account{Type: "forgejo", URL: srv.URL + "/", Token: "secret"})
+
+	marks, err := f.Fetch()
+	require.NoError(t, err)
+	require.Len(t, marks, 2)
+	assert.Equal(t, 2, forge.downloads)
+
+	bks := byURL(marks)
+	bk := bks[srv.URL+"/blob42/gosuki"]
+	require.NotNil(t, bk, "the html url is bookmarked")
+	assert.Equal(t, "gosuki", bk.Title)
+	assert.Equal(t, "bookmark manager", bk.Desc)
+	assert.Equal(t, []string{"bookmarks", "go"}, bk.Tags, "the language is not repeated")
+	assert.Equal(t, ModID, bk.Module)
+	assert.Equal(t, []string{"shell"}, bks[srv.URL+"/alice/dotfiles"].Tags)
+
+	t.Run("unchanged pages are not downloaded", func(t *testing.T) {
+		marks, err := f.Fetch()
+		require.NoError(t, err)
+		assert.Empty(t, marks)
+		assert.Equal(t, 2, forge.downloads)
+	})
+
+	t.Run("new stars", func(t *testing.T) {
+		newRepo := map[string]any{"id": 3, "name": "new", "html_url": srv.URL + "/bob/new"}
+		forge.setPages([]any{gosukiRepo}, []any{dotfiles, newRepo})
+
+		marks, err := f.Fetch()
+		require.NoError(t, err)
+		require.Len(t, marks, 1)
+		assert.Equal(t, srv.URL+"/bob/new", marks[0].URL)
+		assert.Equal(t, 3, forge.downloads, "only the changed page is downloaded")
+	})
+}
+
+func TestGitlab(t *testing.T) {
+	forge := &fakeForge{}
+	var languageRequests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "glpat", r.Header.Get("Private-Token"))
+		switch r.URL.Path {
+		case "/api/v4/projects":
+			assert.Equal(t, "true", r.URL.Query().Get("starred"))
+			forge.page(w, r, func(w http.ResponseWriter, page int) {
+				w.Header().Set("X-Next-Page", strconv.Itoa(page))
+			})
+		case "/api/v4/projects/10/languages":
+			languageRequests++
+			w.Write([]byte(`{"Rust": 80.5, "Shell": 19.5}`))
+		case "/api/v4/projects/11/languages":
+			languageRequests++
+			w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	forge.setPages(
+		[]any{map[string]any{
+			"id": 10, "name": "ripgrep", "web_url": srv.URL + "/burntsushi/ripgrep",
+			"http_url_to_repo": srv.URL + "/burntsushi/ripgrep.git", "topics": []string{"search"},
+		}},
+		[]any{map[string]any{
+			"id": 11, "name": "legacy", "web_url": srv.URL + "/old/legacy", "tag_list": []string{"old"},
+		}},
+	)
+
+	f := newForges(t, Account{Type: "gitlab", URL: srv.URL, Token: "glpat"})
+
+	marks, err := f.Fetch()
+	require.NoError(t, err)
+	require.Len(t, marks, 2)
+
+	bks := byURL(marks)
+	assert.Equal(t, []string{"search", "rust"}, bks[srv.URL+"/burntsushi/ripgrep"].Tags)
+	assert.Equal(t, []string{"old"}, bks[srv.URL+"/old/legacy"].Tags)
+
+	// without ETags the pages are downloaded again but the stars are not
+	// returned twice and their language is not looked up again
+	marks, err = f.Fetch()
+	require.NoError(t, err)
+	assert.Empty(t, marks)
+	assert.Equal(t, 4, forge.downloads)
+	assert.Equal(t, 2, languageRequests)
+}
+
+func TestFetchErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad token", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id": 1, "name": "a", "html_url": "https://example.com/a"}]`))
+	}))
+	defer ok.Close()
+
+	f := newForges(t,
+		Account{Type: "gitea", URL: srv.URL, Token: "bad"},
+		Account{Type: "sourcehut", URL: srv.URL, Token: "x"},
+		Account{Type: "gitea", URL: ok.URL},
+		Account{Type: "gitea", URL: ok.URL, Token: "good"},
+	)
+
+	marks, err := f.Fetch()
+	require.Len(t, marks, 1, "failing accounts do not prevent fetching the others")
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "401 Unauthorized")
+	assert.ErrorContains(t, err, `unknown forge type "sourcehut"`)
+	assert.ErrorContains(t, err, "missing credentials")
+}
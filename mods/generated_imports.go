@@ -1,6 +1,12 @@
 package mods
 
 import (
+	_ "github.com/blob42/gosuki/mods/enrich"
+	_ "github.com/blob42/gosuki/mods/external"
+	_ "github.com/blob42/gosuki/mods/forges"
 	_ "github.com/blob42/gosuki/mods/github"
+	_ "github.com/blob42/gosuki/mods/hackernews"
 	_ "github.com/blob42/gosuki/mods/importer"
+	_ "github.com/blob42/gosuki/mods/lobsters"
+	_ "github.com/blob42/gosuki/mods/reddit"
 )
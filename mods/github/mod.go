@@ -11,6 +11,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -62,6 +64,9 @@ type StarFetcherModel struct {
 
 	token string
 	gh    *github.Client
+
+	// most recent star of the last fetch, older stars are not fetched again
+	lastStar time.Time
 }
 
 // This is the module struct. Used to implement module interface
@@ -100,7 +105,10 @@ func (sf *StarFetcher) GetStarredRepos(done *sync.WaitGroup) {
 	ctx := sfModel.ctx
 	gh := sfModel.gh
 
+	// most recent stars first, paging stops at the stars already fetched
 	opts := &github.ActivityListStarredOptions{
+		Sort:        "created",
+		Direction:   "desc",
 		ListOptions: github.ListOptions{PerPage: pageSize},
 	}
 
@@ -124,6 +132,13 @@ func (sf *StarFetcher) GetStarredRepos(done *sync.WaitGroup) {
 
 		log.Info("github ratelimit", "limit", resp.Rate.Limit, "remainging", resp.Rate.Remaining)
 
+		seen := slices.IndexFunc(repos, func(repo *github.StarredRepository) bool {
+			return repo.StarredAt != nil && !repo.StarredAt.After(sfModel.lastStar)
+		})
+		if seen >= 0 {
+			repos = repos[:seen]
+		}
+
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
@@ -134,7 +149,7 @@ func (sf *StarFetcher) GetStarredRepos(done *sync.WaitGroup) {
 
 		total += len(repos)
 
-		if resp.NextPage == 0 {
+		if resp.NextPage == 0 || seen >= 0 {
 			break
 		}
 
@@ -176,6 +191,7 @@ func (sf *StarFetcher) Fetch() ([]*gosuki.Bookmark, error) {
 	go sf.GetStarredRepos(waitStars)
 
 	count := 0
+	lastStar := sfModel.lastStar
 
 	for repo := range repoChan {
 		//DEBUG:
@@ -183,8 +199,8 @@ func (sf *StarFetcher) Fetch() ([]*gosuki.Bookmark, error) {
 
 		bk := gosuki.Bookmark{Module: string(sf.ModInfo().ID)}
 
-		if repo.Repository.CloneURL != nil {
-			bk.URL = *repo.Repository.CloneURL
+		if repo.Repository.HTMLURL != nil {
+			bk.URL = *repo.Repository.HTMLURL
 		}
 
 		if repo.Repository.Description != nil {
@@ -196,18 +212,29 @@ func (sf *StarFetcher) Fetch() ([]*gosuki.Bookmark, error) {
 		}
 
 		bk.Tags = repo.Repository.Topics
+		if repo.Repository.Language != nil {
+			lang := strings.ToLower(*repo.Repository.Language)
+			if lang != "" && !slices.Contains(bk.Tags, lang) {
+				bk.Tags = append(bk.Tags, lang)
+			}
+		}
+
+		if repo.StarredAt != nil {
+			bk.Created = uint64(repo.StarredAt.Unix())
+			if repo.StarredAt.After(lastStar) {
+				lastStar = repo.StarredAt.Time
+			}
+		}
 
 		bookmarks = append(bookmarks, &bk)
 		count++
 
-		go func() {
-			events.TUIBus <- events.ProgressUpdateMsg{
-				ID:           ModID,
-				Instance:     nil,
-				CurrentCount: uint(count),
-				Total:        uint(len(bookmarks)),
-			}
-		}()
+		events.TUI.Publish(events.ProgressUpdateMsg{
+			ID:           ModID,
+			Instance:     nil,
+			CurrentCount: uint(count),
+			Total:        uint(len(bookmarks)),
+		})
 	}
 
 	if err = <-errChan; err != nil {
@@ -215,6 +242,7 @@ func (sf *StarFetcher) Fetch() ([]*gosuki.Bookmark, error) {
 	}
 
 	waitStars.Wait()
+	sfModel.lastStar = lastStar
 
 	// bookmarks will be handled by the module runner
 	return bookmarks, nil
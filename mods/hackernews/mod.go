@@ -0,0 +1,245 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+// Package hackernews bookmarks the stories favorited on Hacker News. The
+// favorites of a user are public and only listed on the HTML pages of the
+// site, no token is needed:
+//
+//	[hackernews]
+//	username = "pg"
+//
+// The link of the discussion is kept in the description of the bookmarks.
+package hackernews
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/time/rate"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/logging"
+	"github.com/blob42/gosuki/pkg/modules"
+	"github.com/blob42/gosuki/pkg/watch"
+)
+
+const (
+	ModID = "hackernews"
+
+	DefaultSyncInterval = time.Hour
+	DefaultBaseURL      = "https://news.ycombinator.com"
+
+	// stop paging on misbehaving servers
+	maxPages = 100
+
+	requestTimeout = 30 * time.Second
+)
+
+var (
+	Config = NewHackerNewsConfig()
+	log    = logging.GetLogger(ModID)
+
+	errNoUsername = errors.New("no username configured")
+)
+
+type HackerNewsConfig struct {
+	// Hacker News user whose favorites are bookmarked
+	Username string `toml:"username" mapstructure:"username"`
+
+	SyncInterval time.Duration `toml:"sync-interval" mapstructure:"sync-interval"`
+
+	// Minimum delay between two requests to the site
+	RequestDelay time.Duration `toml:"request-delay" mapstructure:"request-delay"`
+}
+
+func NewHackerNewsConfig() *HackerNewsConfig {
+	return &HackerNewsConfig{
+		SyncInterval: DefaultSyncInterval,
+		RequestDelay: 2 * time.Second,
+	}
+}
+
+// Favorites is the module polling the favorites of the configured user
+type Favorites struct {
+	ctx     context.Context
+	client  *http.Client
+	limiter *rate.Limiter
+	baseURL string
+
+	// ids of the stories already returned
+	seen map[string]bool
+}
+
+func (f *Favorites) ModInfo() modules.ModInfo {
+	return modules.ModInfo{
+		ID: modules.ModID(ModID),
+		New: func() modules.Module {
+			return &Favorites{}
+		},
+	}
+}
+
+func (f *Favorites) Init(ctx *modules.Context) error {
+	if Config.Username == "" {
+		return &modules.ErrModDisabled{Err: errNoUsername}
+	}
+
+	f.ctx = ctx.Context
+	f.client = &http.Client{Timeout: requestTimeout}
+	f.limiter = rate.NewLimiter(rate.Every(Config.RequestDelay), 1)
+	f.baseURL = DefaultBaseURL
+	f.seen = map[string]bool{}
+	return nil
+}
+
+// story is a story of a favorites page
+type story struct {
+	id    string
+	url   string
+	title string
+}
+
+func (s story) bookmark(baseURL string) *gosuki.Bookmark {
+	bk := &gosuki.Bookmark{
+		URL:    s.url,
+		Title:  s.title,
+		Module: ModID,
+	}
+	// Ask HN and text stories link to their discussion
+	if discussion := discussionURL(baseURL, s.id); discussion != s.url {
+		bk.Desc = "Discussion: " + discussion
+	}
+	return bk
+}
+
+func discussionURL(baseURL, id string) string {
+	return fmt.Sprintf("%s/item?id=%s", baseURL, id)
+}
+
+// Fetch returns the stories favorited since the last poll. The favorites are
+// listed most recent first, paging stops at the first page with a story
+// already returned.
+func (f *Favorites) Fetch() ([]*gosuki.Bookmark, error) {
+	ctx := f.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	log.Info("fetching favorites", "user", Config.Username)
+
+	var bookmarks []*gosuki.Bookmark
+	for page := 1; page <= maxPages; page++ {
+		if err := f.limiter.Wait(ctx); err != nil {
+			return bookmarks, err
+		}
+
+		stories, more, err := f.favorites(ctx, page)
+		if err != nil {
+			return bookmarks, err
+		}
+
+		known := false
+		for _, s := range stories {
+			if f.seen[s.id] {
+				known = true
+				continue
+			}
+			f.seen[s.id] = true
+			bookmarks = append(bookmarks, s.bookmark(f.baseURL))
+		}
+
+		if !more || known {
+			break
+		}
+	}
+
+	return bookmarks, nil
+}
+
+// favorites fetches a page of the favorite stories of the user. more is true
+// when there is a next page.
+func (f *Favorites) favorites(ctx context.Context, page int) (stories []story, more bool, err error) {
+	pageURL := fmt.Sprintf("%s/favorites?id=%s&p=%d", f.baseURL, url.QueryEscape(Config.Username), page)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("User-Agent", "gosuki")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("get favorites page %d: %s", page, resp.Status)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("parsing favorites page %d: %w", page, err)
+	}
+
+	base, _ := url.Parse(f.baseURL + "/")
+	doc.Find("tr.athing").Each(func(_ int, tr *goquery.Selection) {
+		id, _ := tr.Attr("id")
+		a := tr.Find(".titleline > a").First()
+		href, ok := a.Attr("href")
+		if id == "" || !ok {
+			return
+		}
+
+		// text stories link to item?id=
+		link, err := base.Parse(href)
+		if err != nil {
+			return
+		}
+		stories = append(stories, story{
+			id:    id,
+			url:   link.String(),
+			title: strings.TrimSpace(a.Text()),
+		})
+	})
+
+	return stories, doc.Find("a.morelink").Length() > 0, nil
+}
+
+// Interval at which the module should be run
+func (f *Favorites) Interval() time.Duration {
+	return Config.SyncInterval
+}
+
+func init() {
+	config.RegisterConfigurator(ModID, config.AsConfigurator(Config))
+	modules.RegisterModule(&Favorites{})
+}
+
+// interface guards
+var _ watch.Poller = (*Favorites)(nil)
+var _ modules.Initializer = (*Favorites)(nil)
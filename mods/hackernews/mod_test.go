@@ -0,0 +1,74 @@
+package hackernews
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+const storyRow = `<tr class="athing submission" id="%s">
+  <td align="right" valign="top" class="title"><span class="rank">1.</span></td>
+  <td class="title"><span class="titleline"><a href="%s">%s</a><span class="sitebit comhead"> (<a href="from?site=example.com"><span class="sitestr">example.com</span></a>)</span></span></td>
+</tr>
+<tr><td colspan="2"></td><td class="subtext"><span class="score">42 points</span></td></tr>`
+
+const moreLink = `<tr><td colspan="2"></td><td class="title"><a href="favorites?id=alice&amp;p=2" class="morelink" rel="next">More</a></td></tr>`
+
+func TestFetch(t *testing.T) {
+	pages := map[string]string{
+		"1": fmt.Sprintf(storyRow, "3", "https://example.com/new", "New story") +
+			fmt.Sprintf(storyRow, "2", "item?id=2", "Ask HN: How do you read papers?") + moreLink,
+		"2": fmt.Sprintf(storyRow, "1", "https://example.com/old", "Old &amp; gold"),
+	}
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/favorites", r.URL.Path)
+		assert.Equal(t, "alice", r.URL.Query().Get("id"))
+		requests++
+		fmt.Fprintf(w, "<html><body><table>%s</table></body></html>", pages[r.URL.Query().Get("p")])
+	}))
+	defer srv.Close()
+
+	old := Config.Username
+	Config.Username = "alice"
+	t.Cleanup(func() { Config.Username = old })
+
+	f := &Favorites{
+		ctx:     context.Background(),
+		client:  srv.Client(),
+		limiter: rate.NewLimiter(rate.Inf, 1),
+		baseURL: srv.URL,
+		seen:    map[string]bool{},
+	}
+
+	marks, err := f.Fetch()
+	require.NoError(t, err)
+	require.Len(t, marks, 3)
+	assert.Equal(t, 2, requests)
+
+	assert.Equal(t, "https://example.com/new", marks[0].URL)
+	assert.Equal(t, "New story", marks[0].Title)
+	assert.Equal(t, "Discussion: "+srv.URL+"/item?id=3", marks[0].Desc)
+	assert.Equal(t, ModID, marks[0].Module)
+
+	// text stories are bookmarked with their discussion
+	assert.Equal(t, srv.URL+"/item?id=2", marks[1].URL)
+	assert.Empty(t, marks[1].Desc)
+	assert.Equal(t, "Old & gold", marks[2].Title)
+
+	t.Run("only new favorites", func(t *testing.T) {
+		pages["1"] = fmt.Sprintf(storyRow, "4", "https://example.com/newer", "Newer") + pages["1"]
+
+		marks, err := f.Fetch()
+		require.NoError(t, err)
+		require.Len(t, marks, 1)
+		assert.Equal(t, "https://example.com/newer", marks[0].URL)
+		assert.Equal(t, 3, requests, "paging stops at the known stories")
+	})
+}
@@ -28,6 +28,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -122,14 +123,12 @@ func (im *BookmarksImporter) PreLoad() ([]*gosuki.Bookmark, error) {
 
 	}
 
-	go func() {
-		events.TUIBus <- events.ProgressUpdateMsg{
-			ID:           ImporterID,
-			Instance:     nil,
-			CurrentCount: uint(len(result)),
-			Total:        uint(len(result)),
-		}
-	}()
+	events.TUI.Publish(events.ProgressUpdateMsg{
+		ID:           ImporterID,
+		Instance:     nil,
+		CurrentCount: uint(len(result)),
+		Total:        uint(len(result)),
+	})
 	return result, nil
 }
 
@@ -228,6 +227,11 @@ func loadBookmarksFromHTML(filePath string) ([]*gosuki.Bookmark, error) {
 			Title: title,
 			Tags:  tags,
 		}
+
+		// netscape bookmark files store the creation time in ADD_DATE
+		if added, err := strconv.ParseInt(a.AttrOr("add_date", ""), 10, 64); err == nil && added > 0 {
+			bookmark.Created = uint64(added)
+		}
 		// fmt.Printf("%#v\n", bookmark.URL)
 
 		bookmarks = append(bookmarks, bookmark)
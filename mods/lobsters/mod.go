@@ -0,0 +1,239 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+// Package lobsters bookmarks the stories saved on Lobsters or on another
+// site running its software. Saved stories are private and fetched with the
+// session cookie of a logged in browser, the value of the `lobster_trap`
+// cookie:
+//
+//	[lobsters]
+//	session-cookie = "..."
+//
+// Stories are tagged with their tags and the link of the discussion is kept
+// in their description.
+package lobsters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/logging"
+	"github.com/blob42/gosuki/pkg/modules"
+	"github.com/blob42/gosuki/pkg/watch"
+)
+
+const (
+	ModID = "lobsters"
+
+	DefaultSyncInterval = time.Hour
+	DefaultURL          = "https://lobste.rs"
+
+	sessionCookie = "lobster_trap"
+
+	// stop paging on misbehaving servers
+	maxPages = 100
+
+	requestTimeout = 30 * time.Second
+)
+
+var (
+	Config = NewLobstersConfig()
+	log    = logging.GetLogger(ModID)
+)
+
+type LobstersConfig struct {
+	// Base url of the site
+	URL string `toml:"url" mapstructure:"url"`
+
+	// Value of the session cookie of a logged in browser
+	SessionCookie string `toml:"session-cookie" mapstructure:"session-cookie"`
+
+	SyncInterval time.Duration `toml:"sync-interval" mapstructure:"sync-interval"`
+
+	// Minimum delay between two requests to the site
+	RequestDelay time.Duration `toml:"request-delay" mapstructure:"request-delay"`
+}
+
+func NewLobstersConfig() *LobstersConfig {
+	return &LobstersConfig{
+		URL:          DefaultURL,
+		SyncInterval: DefaultSyncInterval,
+		RequestDelay: time.Second,
+	}
+}
+
+// Saved is the module polling the saved stories
+type Saved struct {
+	ctx     context.Context
+	client  *http.Client
+	limiter *rate.Limiter
+
+	// short ids of the stories already returned
+	seen map[string]bool
+}
+
+func (s *Saved) ModInfo() modules.ModInfo {
+	return modules.ModInfo{
+		ID: modules.ModID(ModID),
+		New: func() modules.Module {
+			return &Saved{}
+		},
+	}
+}
+
+func (s *Saved) Init(ctx *modules.Context) error {
+	if Config.SessionCookie == "" {
+		return &modules.ErrModDisabled{Err: modules.ErrMissingCredentials}
+	}
+
+	s.ctx = ctx.Context
+	s.client = &http.Client{
+		Timeout: requestTimeout,
+		// an expired session redirects to the login page
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	s.limiter = rate.NewLimiter(rate.Every(Config.RequestDelay), 1)
+	s.seen = map[string]bool{}
+	return nil
+}
+
+// story is a story of the JSON API
+type story struct {
+	ShortID     string   `json:"short_id"`
+	Title       string   `json:"title"`
+	URL         string   `json:"url"`
+	CommentsURL string   `json:"comments_url"`
+	Tags        []string `json:"tags"`
+}
+
+func (st story) bookmark() *gosuki.Bookmark {
+	bk := &gosuki.Bookmark{
+		URL:    st.URL,
+		Title:  st.Title,
+		Tags:   slices.Clone(st.Tags),
+		Module: ModID,
+	}
+	// text stories have no url
+	if bk.URL == "" {
+		bk.URL = st.CommentsURL
+	} else if st.CommentsURL != "" {
+		bk.Desc = "Discussion: " + st.CommentsURL
+	}
+	return bk
+}
+
+// Fetch returns the stories saved since the last poll. Saved stories are
+// listed most recent first, paging stops at the first page with a story
+// already returned.
+func (s *Saved) Fetch() ([]*gosuki.Bookmark, error) {
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	log.Info("fetching saved stories", "url", Config.URL)
+
+	var bookmarks []*gosuki.Bookmark
+	for page := 1; page <= maxPages; page++ {
+		if err := s.limiter.Wait(ctx); err != nil {
+			return bookmarks, err
+		}
+
+		stories, err := s.saved(ctx, page)
+		if err != nil {
+			return bookmarks, err
+		}
+
+		known := false
+		for _, st := range stories {
+			if s.seen[st.ShortID] {
+				known = true
+				continue
+			}
+			s.seen[st.ShortID] = true
+			bookmarks = append(bookmarks, st.bookmark())
+		}
+
+		if len(stories) == 0 || known {
+			break
+		}
+	}
+
+	return bookmarks, nil
+}
+
+// saved fetches a page of the saved stories
+func (s *Saved) saved(ctx context.Context, page int) ([]story, error) {
+	url := fmt.Sprintf("%s/saved/page/%d.json", strings.TrimRight(Config.URL, "/"), page)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "gosuki")
+	req.AddCookie(&http.Cookie{Name: sessionCookie, Value: Config.SessionCookie})
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		return nil, errors.New("not logged in, the session cookie expired")
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("get saved stories page %d: %s", page, resp.Status)
+	}
+
+	var stories []story
+	if err = json.NewDecoder(resp.Body).Decode(&stories); err != nil {
+		return nil, fmt.Errorf("decoding saved stories: %w", err)
+	}
+	return stories, nil
+}
+
+// Interval at which the module should be run
+func (s *Saved) Interval() time.Duration {
+	return Config.SyncInterval
+}
+
+func init() {
+	config.RegisterConfigurator(ModID, config.AsConfigurator(Config))
+	modules.RegisterModule(&Saved{})
+}
+
+// interface guards
+var _ watch.Poller = (*Saved)(nil)
+var _ modules.Initializer = (*Saved)(nil)
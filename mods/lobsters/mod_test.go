@@ -0,0 +1,84 @@
+package lobsters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func newSaved(t *testing.T, url, cookie string) *Saved {
+	old := *Config
+	Config.URL, Config.SessionCookie = url, cookie
+	t.Cleanup(func() { *Config = old })
+
+	return &Saved{
+		ctx: context.Background(),
+		client: &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}},
+		limiter: rate.NewLimiter(rate.Inf, 1),
+		seen:    map[string]bool{},
+	}
+}
+
+func TestFetch(t *testing.T) {
+	pages := map[string]string{
+		"/saved/page/1.json": `[
+			{"short_id": "abc", "title": "A story", "url": "https://example.com/a",
+			 "comments_url": "https://lobste.rs/s/abc/a_story", "tags": ["go", "databases"]},
+			{"short_id": "def", "title": "Ask: what are you reading?", "url": "",
+			 "comments_url": "https://lobste.rs/s/def/ask", "tags": ["ask"]}
+		]`,
+		"/saved/page/2.json": `[]`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(sessionCookie)
+		if err != nil || cookie.Value != "session" {
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	s := newSaved(t, srv.URL+"/", "session")
+	marks, err := s.Fetch()
+	require.NoError(t, err)
+	require.Len(t, marks, 2)
+
+	assert.Equal(t, "https://example.com/a", marks[0].URL)
+	assert.Equal(t, "A story", marks[0].Title)
+	assert.Equal(t, []string{"go", "databases"}, marks[0].Tags)
+	assert.Equal(t, "Discussion: https://lobste.rs/s/abc/a_story", marks[0].Desc)
+	assert.Equal(t, ModID, marks[0].Module)
+
+	assert.Equal(t, "https://lobste.rs/s/def/ask", marks[1].URL, "text stories link to their discussion")
+	assert.Empty(t, marks[1].Desc)
+
+	t.Run("only new stories", func(t *testing.T) {
+		pages["/saved/page/1.json"] = `[
+			{"short_id": "ghi", "title": "New", "url": "https://example.com/new", "tags": []},
+			{"short_id": "abc", "title": "A story", "url": "https://example.com/a"}
+		]`
+		marks, err := s.Fetch()
+		require.NoError(t, err)
+		require.Len(t, marks, 1)
+		assert.Equal(t, "https://example.com/new", marks[0].URL)
+	})
+
+	t.Run("expired session", func(t *testing.T) {
+		s := newSaved(t, srv.URL, "expired")
+		_, err := s.Fetch()
+		assert.ErrorContains(t, err, "session cookie expired")
+	})
+}
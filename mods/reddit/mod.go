@@ -0,0 +1,294 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+// Package reddit bookmarks the posts saved on Reddit. It authenticates with an
+// OAuth token of an app created at https://www.reddit.com/prefs/apps, with the
+// "history" and "identity" scopes. A refresh token is exchanged for short
+// lived access tokens with the id and secret of the app:
+//
+//	[reddit]
+//	client-id = "..."
+//	client-secret = "..."
+//	refresh-token = "..."
+//
+// An access token is used as is:
+//
+//	[reddit]
+//	access-token = "..."
+//
+// Posts are tagged with their subreddit and the link of the discussion is kept
+// in their description. Saved comments are ignored.
+package reddit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	goreddit "github.com/vartanbeno/go-reddit/v2/reddit"
+	"golang.org/x/oauth2"
+	"golang.org/x/time/rate"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/logging"
+	"github.com/blob42/gosuki/pkg/modules"
+	"github.com/blob42/gosuki/pkg/watch"
+)
+
+const (
+	ModID = "reddit"
+
+	DefaultSyncInterval = time.Hour
+
+	// permalinks of the API are relative to the site
+	siteURL = "https://www.reddit.com"
+
+	apiURL   = "https://oauth.reddit.com"
+	tokenURL = "https://www.reddit.com/api/v1/access_token"
+
+	pageSize = 100
+
+	// stop paging on misbehaving servers
+	maxPages = 100
+
+	// key of the cursor in the module state
+	cursorKey = "saved-cursor"
+
+	// number of the most recent posts kept in the cursor. Paging stops at
+	// the first of them still saved.
+	cursorSize = 10
+)
+
+var (
+	Config = NewRedditConfig()
+	log    = logging.GetLogger(ModID)
+
+	errNoClientID = errors.New("no client-id configured for the refresh token")
+)
+
+type RedditConfig struct {
+	ClientID     string `toml:"client-id" mapstructure:"client-id"`
+	ClientSecret string `toml:"client-secret" mapstructure:"client-secret"`
+	RefreshToken string `toml:"refresh-token" mapstructure:"refresh-token"`
+
+	// Bearer token used when no refresh token is set
+	AccessToken string `toml:"access-token" mapstructure:"access-token"`
+
+	SyncInterval time.Duration `toml:"sync-interval" mapstructure:"sync-interval"`
+
+	// Minimum delay between two requests to the API
+	RequestDelay time.Duration `toml:"request-delay" mapstructure:"request-delay"`
+}
+
+func NewRedditConfig() *RedditConfig {
+	return &RedditConfig{
+		SyncInterval: DefaultSyncInterval,
+		RequestDelay: time.Second,
+	}
+}
+
+// Saved is the module polling the saved posts
+type Saved struct {
+	ctx     context.Context
+	client  *goreddit.Client
+	limiter *rate.Limiter
+}
+
+func (s *Saved) ModInfo() modules.ModInfo {
+	return modules.ModInfo{
+		ID: modules.ModID(ModID),
+		New: func() modules.Module {
+			return &Saved{}
+		},
+	}
+}
+
+func (s *Saved) Init(ctx *modules.Context) error {
+	if Config.RefreshToken == "" && Config.AccessToken == "" {
+		return &modules.ErrModDisabled{Err: modules.ErrMissingCredentials}
+	}
+	if Config.RefreshToken != "" && Config.ClientID == "" {
+		return &modules.ErrModDisabled{Err: errNoClientID}
+	}
+
+	client, err := newClient(ctx.Context, tokenURL, goreddit.WithBaseURL(apiURL))
+	if err != nil {
+		return err
+	}
+
+	s.ctx = ctx.Context
+	s.client = client
+	s.limiter = rate.NewLimiter(rate.Every(Config.RequestDelay), 1)
+	return nil
+}
+
+// newClient returns a client authenticated with the configured token. A
+// refresh token is exchanged at tokenURL.
+func newClient(ctx context.Context, tokenURL string, opts ...goreddit.Opt) (*goreddit.Client, error) {
+	var tokens oauth2.TokenSource
+	if Config.RefreshToken != "" {
+		conf := &oauth2.Config{
+			ClientID:     Config.ClientID,
+			ClientSecret: Config.ClientSecret,
+			Endpoint: oauth2.Endpoint{
+				TokenURL:  tokenURL,
+				AuthStyle: oauth2.AuthStyleInHeader,
+			},
+		}
+		tokens = conf.TokenSource(ctx, &oauth2.Token{RefreshToken: Config.RefreshToken})
+	} else {
+		tokens = oauth2.StaticTokenSource(&oauth2.Token{
+			AccessToken: Config.AccessToken,
+			TokenType:   "bearer",
+		})
+	}
+
+	// format asked by the API rules
+	userAgent := fmt.Sprintf("%s:gosuki", runtime.GOOS)
+	opts = append([]goreddit.Opt{
+		goreddit.WithUserAgent(userAgent),
+		goreddit.WithHTTPClient(oauth2.NewClient(ctx, tokens)),
+	}, opts...)
+
+	// the read-only client does not log in, the token is sent by the http
+	// client on the authenticated API
+	return goreddit.NewReadonlyClient(opts...)
+}
+
+func bookmark(post *goreddit.Post) *gosuki.Bookmark {
+	discussion := siteURL + post.Permalink
+	bk := &gosuki.Bookmark{
+		URL:    post.URL,
+		Title:  post.Title,
+		Module: ModID,
+	}
+	if post.SubredditName != "" {
+		bk.Tags = []string{strings.ToLower(post.SubredditName)}
+	}
+	// self posts link to their discussion
+	if bk.URL == "" || post.IsSelfPost {
+		bk.URL = discussion
+	} else {
+		bk.Desc = "Discussion: " + discussion
+	}
+	return bk
+}
+
+// Fetch returns the posts saved since the last poll. Saved posts are listed
+// most recent first, paging stops at the first post of the cursor kept in the
+// L2 cache.
+func (s *Saved) Fetch() ([]*gosuki.Bookmark, error) {
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// saved posts are listed under the name of the user owning the token
+	if s.client.Username == "" {
+		if err := s.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		me, _, err := s.client.Account.Info(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("fetching account: %w", err)
+		}
+		s.client.Username = me.Name
+	}
+
+	cursor, err := db.ModuleState(ctx, ModID, cursorKey)
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	if cursor != "" {
+		for _, id := range strings.Split(cursor, ",") {
+			known[id] = true
+		}
+	}
+
+	log.Info("fetching saved posts", "user", s.client.Username)
+
+	var bookmarks []*gosuki.Bookmark
+	var recent []string // full ids of the new posts
+	opts := &goreddit.ListUserOverviewOptions{
+		ListOptions: goreddit.ListOptions{Limit: pageSize},
+	}
+	for page := 1; page <= maxPages; page++ {
+		if err := s.limiter.Wait(ctx); err != nil {
+			return bookmarks, err
+		}
+
+		posts, _, resp, err := s.client.User.Saved(ctx, opts)
+		if err != nil {
+			return bookmarks, fmt.Errorf("fetching saved posts: %w", err)
+		}
+
+		reached := false
+		for _, post := range posts {
+			if known[post.FullID] {
+				reached = true
+				break
+			}
+			recent = append(recent, post.FullID)
+			bookmarks = append(bookmarks, bookmark(post))
+		}
+
+		if resp.After == "" || reached {
+			break
+		}
+		opts.After = resp.After
+	}
+
+	// the previous cursor is kept behind the new posts in case some of the
+	// new posts are unsaved before the next poll
+	if len(recent) > 0 {
+		if cursor != "" {
+			recent = append(recent, strings.Split(cursor, ",")...)
+		}
+		recent = recent[:min(len(recent), cursorSize)]
+		err = db.SetModuleState(ctx, ModID, cursorKey, strings.Join(recent, ","))
+		if err != nil {
+			return bookmarks, err
+		}
+	}
+
+	return bookmarks, nil
+}
+
+// Interval at which the module should be run
+func (s *Saved) Interval() time.Duration {
+	return Config.SyncInterval
+}
+
+func init() {
+	config.RegisterConfigurator(ModID, config.AsConfigurator(Config))
+	modules.RegisterModule(&Saved{})
+}
+
+// interface guards
+var _ watch.Poller = (*Saved)(nil)
+var _ modules.Initializer = (*Saved)(nil)
@@ -0,0 +1,178 @@
+package reddit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goreddit "github.com/vartanbeno/go-reddit/v2/reddit"
+	"golang.org/x/time/rate"
+
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+const listing = `{"kind": "Listing", "data": {"after": %q, "children": [%s]}}`
+
+const linkPost = `{"kind": "t3", "data": {"name": "t3_link", "title": "Go 1.24 is released",
+	"url": "https://go.dev/blog/go1.24", "permalink": "/r/golang/comments/link/go_124/",
+	"subreddit": "golang"}}`
+
+const selfPost = `{"kind": "t3", "data": {"name": "t3_self", "title": "What are you working on?",
+	"url": "https://www.reddit.com/r/rust/comments/self/what/", "is_self": true,
+	"permalink": "/r/rust/comments/self/what/", "subreddit": "rust"}}`
+
+const savedComment = `{"kind": "t1", "data": {"name": "t1_comment", "body": "nice",
+	"permalink": "/r/golang/comments/link/go_124/comment/"}}`
+
+func TestMain(m *testing.M) {
+	db.RegisterSqliteHooks()
+	os.Exit(m.Run())
+}
+
+func setupCache(t *testing.T) {
+	l2, err := db.NewDB("test_reddit", "", db.DBTypeInMemoryDSN).Init()
+	require.NoError(t, err)
+	require.NoError(t, l2.InitSchema(context.Background()))
+	db.L2Cache.DB = l2
+
+	t.Cleanup(func() {
+		l2.Close()
+		db.L2Cache.DB = nil
+	})
+}
+
+func TestFetch(t *testing.T) {
+	setupCache(t)
+
+	pages := map[string]string{
+		"":        fmt.Sprintf(listing, "t3_self", linkPost+","+savedComment),
+		"t3_self": fmt.Sprintf(listing, "", selfPost),
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/access_token":
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "refresh", r.PostForm.Get("refresh_token"))
+			id, secret, _ := r.BasicAuth()
+			assert.Equal(t, "id", id)
+			assert.Equal(t, "secret", secret)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token": "token", "token_type": "bearer", "expires_in": 3600}`))
+		case "/api/v1/me":
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			w.Write([]byte(`{"name": "alice"}`))
+		case "/user/alice/saved":
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			w.Write([]byte(pages[r.URL.Query().Get("after")]))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	old := *Config
+	Config.ClientID, Config.ClientSecret = "id", "secret"
+	Config.RefreshToken = "refresh"
+	t.Cleanup(func() { *Config = old })
+
+	newSaved := func() *Saved {
+		client, err := newClient(context.Background(),
+			srv.URL+"/api/v1/access_token",
+			goreddit.WithBaseURL(srv.URL),
+		)
+		require.NoError(t, err)
+
+		return &Saved{
+			ctx:     context.Background(),
+			client:  client,
+			limiter: rate.NewLimiter(rate.Inf, 1),
+		}
+	}
+	s := newSaved()
+
+	marks, err := s.Fetch()
+	require.NoError(t, err)
+	require.Len(t, marks, 2, "saved comments are ignored")
+
+	assert.Equal(t, "https://go.dev/blog/go1.24", marks[0].URL)
+	assert.Equal(t, "Go 1.24 is released", marks[0].Title)
+	assert.Equal(t, []string{"golang"}, marks[0].Tags)
+	assert.Equal(t, "Discussion: https://www.reddit.com/r/golang/comments/link/go_124/", marks[0].Desc)
+	assert.Equal(t, ModID, marks[0].Module)
+
+	assert.Equal(t, "https://www.reddit.com/r/rust/comments/self/what/", marks[1].URL)
+	assert.Empty(t, marks[1].Desc)
+
+	cursor, err := db.ModuleState(context.Background(), ModID, cursorKey)
+	require.NoError(t, err)
+	assert.Equal(t, "t3_link,t3_self", cursor)
+
+	t.Run("only new posts", func(t *testing.T) {
+		newPost := `{"kind": "t3", "data": {"name": "t3_new", "title": "New", "url": "https://example.com/",
+			"permalink": "/r/programming/comments/new/", "subreddit": "programming"}}`
+		pages[""] = fmt.Sprintf(listing, "t3_link", newPost+","+linkPost)
+
+		marks, err := s.Fetch()
+		require.NoError(t, err)
+		require.Len(t, marks, 1)
+		assert.Equal(t, "https://example.com/", marks[0].URL)
+
+		cursor, err := db.ModuleState(context.Background(), ModID, cursorKey)
+		require.NoError(t, err)
+		assert.Equal(t, "t3_new,t3_link,t3_self", cursor)
+	})
+
+	t.Run("cursor kept across restarts", func(t *testing.T) {
+		marks, err := newSaved().Fetch()
+		require.NoError(t, err)
+		assert.Empty(t, marks)
+	})
+
+	t.Run("unsaved post", func(t *testing.T) {
+		pages[""] = fmt.Sprintf(listing, "", linkPost+","+selfPost)
+
+		marks, err := newSaved().Fetch()
+		require.NoError(t, err)
+		assert.Empty(t, marks, "paging stops at the older posts of the cursor")
+	})
+}
+
+func TestAccessToken(t *testing.T) {
+	setupCache(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer static", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/v1/me":
+			w.Write([]byte(`{"name": "bob"}`))
+		case "/user/bob/saved":
+			w.Write([]byte(fmt.Sprintf(listing, "", linkPost)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	old := *Config
+	Config.AccessToken = "static"
+	t.Cleanup(func() { *Config = old })
+
+	client, err := newClient(context.Background(), "", goreddit.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+	s := &Saved{
+		ctx:     context.Background(),
+		client:  client,
+		limiter: rate.NewLimiter(rate.Inf, 1),
+	}
+
+	marks, err := s.Fetch()
+	require.NoError(t, err)
+	require.Len(t, marks, 1)
+	assert.Equal(t, "https://go.dev/blog/go1.24", marks[0].URL)
+}
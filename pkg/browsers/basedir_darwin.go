@@ -25,6 +25,8 @@
 package browsers
 
 import (
+	"path/filepath"
+
 	"github.com/blob42/gosuki/internal/utils"
 	"github.com/blob42/gosuki/pkg/logging"
 )
@@ -40,3 +42,9 @@ func (b BrowserDef) BaseDir() string {
 func (b BrowserDef) ExpandBaseDir() (string, error) {
 	return utils.ExpandPath(b.baseDir)
 }
+
+// directory of the native messaging host manifests of a mozilla browser with
+// its profiles in dir
+func mozillaHostsDir(dir string) string {
+	return filepath.Join(filepath.Dir(dir), "Mozilla", "NativeMessagingHosts")
+}
@@ -25,6 +25,8 @@
 package browsers
 
 import (
+	"path/filepath"
+
 	"github.com/blob42/gosuki/internal/utils"
 	"github.com/blob42/gosuki/pkg/logging"
 )
@@ -83,3 +85,12 @@ func isValidDir(dir string, pt string) bool {
 	}
 	return ok
 }
+
+// directory of the native messaging host manifests of a mozilla browser with
+// its profiles in dir. Firefox profiles are in a subdirectory of ~/.mozilla.
+func mozillaHostsDir(dir string) string {
+	if filepath.Base(dir) == "firefox" {
+		dir = filepath.Dir(dir)
+	}
+	return filepath.Join(dir, "native-messaging-hosts")
+}
@@ -0,0 +1,227 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package mozilla
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pierrec/lz4/v4"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/browsers"
+)
+
+const (
+	// BackupsDir is the directory of the profile where Firefox keeps daily
+	// backups of the bookmarks
+	BackupsDir = "bookmarkbackups"
+
+	// root folder of the tags in the backups, its children are not bookmarks
+	tagsRoot = "tagsFolder"
+
+	annoDescription = "bookmarkProperties/description"
+
+	// an lz4 block expands at most about 255 times
+	mozLz4MaxRatio = 255
+	// hard limit of the decompressed size of a mozlz4 file
+	mozLz4MaxSize = 256 << 20
+)
+
+var (
+	mozLz4Magic = []byte("mozLz40\x00")
+
+	// bookmarks-2024-05-01_1234_<hash>.jsonlz4, older versions have neither
+	// the count nor the hash and are not compressed
+	reBackupName = regexp.MustCompile(`^bookmarks-(\d{4}-\d{2}-\d{2})(?:_(\d+))?(?:_[^.]*)?\.(jsonlz4|json)$`)
+
+	ErrNotMozLz4      = errors.New("not a mozlz4 file")
+	ErrMozLz4TooLarge = errors.New("mozlz4 decompressed size too large")
+)
+
+// ReadMozLz4 decompresses a mozlz4 file, as used by Firefox for the bookmark
+// backups and the session store. It is a single lz4 block prefixed with a
+// magic number and the size of the decompressed data.
+func ReadMozLz4(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	header := len(mozLz4Magic) + 4
+	if len(data) < header || !bytes.Equal(data[:len(mozLz4Magic)], mozLz4Magic) {
+		return nil, ErrNotMozLz4
+	}
+
+	// the size comes from the file, do not allocate more than the block can
+	// decompress to
+	size := uint64(binary.LittleEndian.Uint32(data[len(mozLz4Magic):header]))
+	if size > uint64(len(data)-header)*mozLz4MaxRatio || size > mozLz4MaxSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrMozLz4TooLarge, size)
+	}
+	out := make([]byte, size)
+	n, err := lz4.UncompressBlock(data[header:], out)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing mozlz4: %w", err)
+	}
+	return out[:n], nil
+}
+
+// ListBackups returns the bookmark backups found in the bookmarkbackups
+// directory of the profile, most recent first
+func ListBackups(profileDir string) ([]browsers.Backup, error) {
+	dir := filepath.Join(profileDir, BackupsDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []browsers.Backup
+	for _, entry := range entries {
+		m := reBackupName.FindStringSubmatch(entry.Name())
+		if m == nil || entry.IsDir() {
+			continue
+		}
+
+		date, err := time.ParseInLocation(time.DateOnly, m[1], time.Local)
+		if err != nil {
+			continue
+		}
+		// the name only has the day of the backup
+		if info, err := entry.Info(); err == nil && info.ModTime().Format(time.DateOnly) == m[1] {
+			date = info.ModTime()
+		}
+		count, _ := strconv.Atoi(m[2])
+
+		backups = append(backups, browsers.Backup{
+			Path:  filepath.Join(dir, entry.Name()),
+			Time:  date,
+			Count: count,
+		})
+	}
+
+	slices.SortFunc(backups, func(a, b browsers.Backup) int {
+		return b.Time.Compare(a.Time)
+	})
+	return backups, nil
+}
+
+// backupNode is a node of the JSON bookmark backups
+type backupNode struct {
+	Title     string `json:"title"`
+	URI       string `json:"uri"`
+	Tags      string `json:"tags"`
+	Root      string `json:"root"`
+	DateAdded int64  `json:"dateAdded"`
+	Annos     []struct {
+		Name  string `json:"name"`
+		Value any    `json:"value"`
+	} `json:"annos"`
+	Children []*backupNode `json:"children"`
+}
+
+// LoadBackup reads the bookmarks of a compressed or plain JSON backup
+func LoadBackup(path string) ([]*gosuki.Bookmark, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var data []byte
+	if strings.HasSuffix(path, ".jsonlz4") {
+		data, err = ReadMozLz4(f)
+	} else {
+		data, err = io.ReadAll(f)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseBackup(data)
+}
+
+// ParseBackup returns the bookmarks of a JSON backup. The names of the
+// folders, except the root folders, are added to the tags of their bookmarks.
+func ParseBackup(data []byte) ([]*gosuki.Bookmark, error) {
+	var root backupNode
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing bookmark backup: %w", err)
+	}
+
+	var bookmarks []*gosuki.Bookmark
+	var walk func(node *backupNode, folders []string)
+	walk = func(node *backupNode, folders []string) {
+		if node.Root == tagsRoot {
+			return
+		}
+
+		if node.URI != "" {
+			if strings.HasPrefix(node.URI, "place:") {
+				return
+			}
+			bk := &gosuki.Bookmark{
+				URL:   node.URI,
+				Title: node.Title,
+				Tags:  slices.Clone(folders),
+			}
+			for tag := range strings.SplitSeq(node.Tags, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(bk.Tags, tag) {
+					bk.Tags = append(bk.Tags, tag)
+				}
+			}
+			for _, anno := range node.Annos {
+				if desc, ok := anno.Value.(string); ok && anno.Name == annoDescription {
+					bk.Desc = desc
+				}
+			}
+			// microseconds since the epoch
+			if node.DateAdded > 0 {
+				bk.Created = uint64(node.DateAdded / 1_000_000)
+			}
+			bookmarks = append(bookmarks, bk)
+			return
+		}
+
+		if node.Root == "" && node.Title != "" {
+			folders = append(folders, node.Title)
+		}
+		for _, child := range node.Children {
+			walk(child, folders)
+		}
+	}
+	walk(&root, nil)
+
+	return bookmarks, nil
+}
@@ -0,0 +1,117 @@
+package mozilla
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pierrec/lz4/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBackup = `{
+  "guid": "root________", "title": "", "root": "placesRoot", "typeCode": 2,
+  "children": [
+    {
+      "guid": "menu________", "title": "menu", "root": "bookmarksMenuFolder", "typeCode": 2,
+      "children": [
+        {
+          "title": "dev", "typeCode": 2,
+          "children": [
+            {"title": "Go", "uri": "https://go.dev", "tags": "golang,lang", "dateAdded": 1700000000000000, "typeCode": 1,
+             "annos": [{"name": "bookmarkProperties/description", "value": "the go site"}]},
+            {"title": "Recent", "uri": "place:sort=8&maxResults=10", "typeCode": 1}
+          ]
+        },
+        {"title": "Example", "uri": "https://example.com", "typeCode": 1}
+      ]
+    },
+    {
+      "guid": "tags________", "title": "tags", "root": "tagsFolder", "typeCode": 2,
+      "children": [{"title": "golang", "typeCode": 2, "children": [{"uri": "https://go.dev", "typeCode": 1}]}]
+    }
+  ]
+}`
+
+func mozLz4(t *testing.T, data []byte) []byte {
+	t.Helper()
+	block := make([]byte, lz4.CompressBlockBound(len(data)))
+	n, err := lz4.CompressBlock(data, block, nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	buf.Write(mozLz4Magic)
+	binary.Write(&buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(block[:n])
+	return buf.Bytes()
+}
+
+func TestReadMozLz4(t *testing.T) {
+	data, err := ReadMozLz4(bytes.NewReader(mozLz4(t, []byte(testBackup))))
+	require.NoError(t, err)
+	assert.Equal(t, testBackup, string(data))
+
+	_, err = ReadMozLz4(bytes.NewReader([]byte(testBackup)))
+	assert.ErrorIs(t, err, ErrNotMozLz4)
+
+	// highly compressible data is still accepted
+	zeros := make([]byte, 1<<20)
+	data, err = ReadMozLz4(bytes.NewReader(mozLz4(t, zeros)))
+	require.NoError(t, err)
+	assert.Equal(t, zeros, data)
+
+	// the header size is bounded by the compressed size and a hard limit
+	for _, size := range []uint32{1 << 20, mozLz4MaxSize + 1, 1<<32 - 1} {
+		forged := mozLz4(t, []byte(testBackup))
+		binary.LittleEndian.PutUint32(forged[len(mozLz4Magic):], size)
+		_, err = ReadMozLz4(bytes.NewReader(forged))
+		assert.ErrorIs(t, err, ErrMozLz4TooLarge, size)
+	}
+}
+
+func TestParseBackup(t *testing.T) {
+	bookmarks, err := ParseBackup([]byte(testBackup))
+	require.NoError(t, err)
+	require.Len(t, bookmarks, 2)
+
+	assert.Equal(t, "https://go.dev", bookmarks[0].URL)
+	assert.Equal(t, "Go", bookmarks[0].Title)
+	assert.Equal(t, []string{"dev", "golang", "lang"}, bookmarks[0].Tags)
+	assert.Equal(t, "the go site", bookmarks[0].Desc)
+	assert.Equal(t, uint64(1700000000), bookmarks[0].Created)
+
+	assert.Equal(t, "https://example.com", bookmarks[1].URL)
+	assert.Empty(t, bookmarks[1].Tags)
+}
+
+func TestListBackups(t *testing.T) {
+	profile := t.TempDir()
+	dir := filepath.Join(profile, BackupsDir)
+	require.NoError(t, os.Mkdir(dir, 0o755))
+
+	for name, data := range map[string][]byte{
+		"bookmarks-2024-05-01_2_bZ4QmZMy5Q5X3lQ9w==.jsonlz4":  mozLz4(t, []byte(testBackup)),
+		"bookmarks-2024-05-03_2_KHdT0UFzSgQnN8cx1A==.jsonlz4": mozLz4(t, []byte(testBackup)),
+		"bookmarks-2019-01-10.json":                           []byte(testBackup),
+		"unrelated.txt":                                       nil,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o644))
+	}
+
+	backups, err := ListBackups(profile)
+	require.NoError(t, err)
+	require.Len(t, backups, 3)
+	assert.Equal(t, "2024-05-03", backups[0].Time.Format("2006-01-02"))
+	assert.Equal(t, 2, backups[0].Count)
+	assert.Equal(t, "2019-01-10", backups[2].Time.Format("2006-01-02"))
+	assert.Zero(t, backups[2].Count)
+
+	for _, b := range backups {
+		bookmarks, err := LoadBackup(b.Path)
+		require.NoError(t, err, b.Path)
+		assert.Len(t, bookmarks, 2)
+	}
+}
@@ -77,7 +77,7 @@ type MozFolder struct {
 
 // Columns of the table moz_bookmarks in this order:
 //
-//	placeId  title  parentFolderId  folders url plDesc lastModified
+//	placeId  title  parentFolderId  folders url plDesc lastModified dateAdded
 //
 // This is the typed used when scanning from the query located in `recursive-all-bookmarks.sql`
 type MozBookmark struct {
@@ -90,6 +90,9 @@ type MozBookmark struct {
 	URL            string
 	PlDesc         string `db:"plDesc"`
 	BkLastModified Sqlid  `db:"lastModified"`
+
+	// time the url was first bookmarked in microseconds
+	DateAdded Sqlid `db:"dateAdded"`
 }
 
 // Type is used for scanning from `merged-places-bookmarks.sql`
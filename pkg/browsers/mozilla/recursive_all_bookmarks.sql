@@ -118,7 +118,8 @@ SELECT
  group_concat(folders) as folders,
  url,
  ifnull(plDesc, "") as plDesc,
- (SELECT max(moz_bookmarks.lastModified) FROM moz_bookmarks WHERE fk=placeId ) as lastModified
+ (SELECT max(moz_bookmarks.lastModified) FROM moz_bookmarks WHERE fk=placeId ) as lastModified,
+ (SELECT min(moz_bookmarks.dateAdded) FROM moz_bookmarks WHERE fk=placeId ) as dateAdded
  FROM all_bookmarks
 GROUP BY placeId
 ORDER BY lastModified
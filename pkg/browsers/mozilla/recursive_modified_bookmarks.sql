@@ -116,6 +116,7 @@ parentFolderId,
  folders,
  url,
  ifnull(plDesc, "") as plDesc,
- (SELECT max(moz_bookmarks.lastModified) FROM moz_bookmarks WHERE fk=placeId ) as lastModified
+ (SELECT max(moz_bookmarks.lastModified) FROM moz_bookmarks WHERE fk=placeId ) as lastModified,
+ (SELECT min(moz_bookmarks.dateAdded) FROM moz_bookmarks WHERE fk=placeId ) as dateAdded
  FROM all_bookmarks
 ORDER BY lastModified
@@ -0,0 +1,130 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package browsers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/blob42/gosuki/internal/utils"
+)
+
+const (
+	// Name of the gosuki native messaging host
+	NativeHostName = "net.gosuki.native_host"
+
+	// ID of the gosuki Firefox extension
+	FirefoxExtensionID = "extension@gosuki.net"
+)
+
+// NativeHostManifest registers a native messaging host with a browser. See
+// https://developer.mozilla.org/en-US/docs/Mozilla/Add-ons/WebExtensions/Native_manifests
+type NativeHostManifest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// Absolute path of the host executable
+	Path string `json:"path"`
+	Type string `json:"type"`
+
+	// Firefox extension ids allowed to use the host
+	AllowedExtensions []string `json:"allowed_extensions,omitempty"`
+
+	// Chromium extension origins allowed to use the host
+	AllowedOrigins []string `json:"allowed_origins,omitempty"`
+}
+
+// NativeHostManifest returns the manifest of the native host at path for the
+// browser. extensionIDs are the extensions allowed to talk to the host, the
+// gosuki extension by default for Mozilla browsers.
+func (b BrowserDef) NativeHostManifest(path string, extensionIDs ...string) (*NativeHostManifest, error) {
+	if !filepath.IsAbs(path) {
+		return nil, fmt.Errorf("native host path must be absolute: %s", path)
+	}
+
+	manifest := &NativeHostManifest{
+		Name:        NativeHostName,
+		Description: "gosuki bookmark manager",
+		Path:        path,
+		Type:        "stdio",
+	}
+
+	switch b.Family {
+	case Mozilla:
+		if len(extensionIDs) == 0 {
+			extensionIDs = []string{FirefoxExtensionID}
+		}
+		manifest.AllowedExtensions = extensionIDs
+	case ChromeBased:
+		if len(extensionIDs) == 0 {
+			return nil, fmt.Errorf("<%s>: the extension id is required", b.Flavour)
+		}
+		for _, id := range extensionIDs {
+			manifest.AllowedOrigins = append(manifest.AllowedOrigins,
+				"chrome-extension://"+id+"/")
+		}
+	default:
+		return nil, fmt.Errorf("<%s> does not support native messaging", b.Flavour)
+	}
+
+	return manifest, nil
+}
+
+// NativeHostsDir returns the directory where the browser looks for native
+// messaging host manifests
+func (b BrowserDef) NativeHostsDir() (string, error) {
+	dir, err := utils.ExpandOnly(b.BaseDir())
+	if err != nil {
+		return "", err
+	}
+
+	switch b.Family {
+	case Mozilla:
+		return mozillaHostsDir(dir), nil
+	case ChromeBased:
+		return filepath.Join(dir, "NativeMessagingHosts"), nil
+	}
+	return "", fmt.Errorf("<%s> does not support native messaging", b.Flavour)
+}
+
+// InstallNativeHost writes the manifest to the native messaging hosts
+// directory of the browser and returns the path of the manifest file
+func (b BrowserDef) InstallNativeHost(manifest *NativeHostManifest) (string, error) {
+	dir, err := b.NativeHostsDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, manifest.Name+".json")
+	return path, os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,128 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+// Package netscape parses the Netscape bookmark files exported by most
+// browsers, such as Safari, Opera, Edge, Chrome and Firefox, into a bookmark
+// tree. Folders are kept in the tree and become tags of their bookmarks.
+package netscape
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/blob42/gosuki/pkg/tree"
+)
+
+const RootNodeName = "ROOT"
+
+// attributes of the folders used by browsers for their root folders, such as
+// the bookmarks toolbar. They are not kept as folders in the tree.
+var containerAttrs = []string{"personal_toolbar_folder", "unfiled_bookmarks_folder"}
+
+// Parse reads a Netscape bookmark file and returns the root of its bookmark
+// tree. Nested folders are kept, the root folders of the browsers are
+// skipped and their children added to the root.
+func Parse(r io.Reader) (*tree.Node, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	root := &tree.Node{
+		Title: RootNodeName,
+		Type:  tree.RootNode,
+	}
+	walk(doc.Find("body"), root)
+	return root, nil
+}
+
+// walk adds the entries found under sel to parent. The unclosed DL and P
+// elements of the bookmark files are traversed transparently.
+func walk(sel *goquery.Selection, parent *tree.Node) {
+	sel.Children().Each(func(_ int, child *goquery.Selection) {
+		switch goquery.NodeName(child) {
+		case "dt":
+			addEntry(child, parent)
+		case "dl", "p":
+			walk(child, parent)
+		}
+	})
+}
+
+// addEntry adds the bookmark or folder of a DT element to parent
+func addEntry(dt *goquery.Selection, parent *tree.Node) {
+	if a := dt.ChildrenFiltered("a").First(); a.Length() > 0 {
+		url := strings.TrimSpace(a.AttrOr("href", ""))
+		if url == "" || strings.HasPrefix(url, "place:") {
+			return
+		}
+
+		node := &tree.Node{
+			Title: strings.TrimSpace(a.Text()),
+			Type:  tree.URLNode,
+			URL:   url,
+		}
+		for tag := range strings.SplitSeq(a.AttrOr("tags", ""), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				node.Tags = append(node.Tags, tag)
+			}
+		}
+		if added, err := strconv.ParseInt(a.AttrOr("add_date", ""), 10, 64); err == nil && added > 0 {
+			node.Created = uint64(added)
+		}
+		// the description is in the DD element following the link
+		if dd := dt.Next(); goquery.NodeName(dd) == "dd" {
+			node.Desc = strings.TrimSpace(dd.Text())
+		}
+
+		tree.AddChild(parent, node)
+		return
+	}
+
+	h3 := dt.ChildrenFiltered("h3").First()
+	if h3.Length() == 0 {
+		return
+	}
+
+	folder := parent
+	if !isContainer(h3) {
+		folder = &tree.Node{
+			Title: strings.TrimSpace(h3.Text()),
+			Type:  tree.FolderNode,
+		}
+		tree.AddChild(parent, folder)
+	}
+	walk(dt, folder)
+}
+
+func isContainer(h3 *goquery.Selection) bool {
+	for _, attr := range containerAttrs {
+		if strings.EqualFold(h3.AttrOr(attr, ""), "true") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package netscape
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/tree"
+)
+
+func parseFile(t *testing.T, path string) *tree.Node {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	root, err := Parse(f)
+	require.NoError(t, err)
+	return root
+}
+
+func bookmarks(root *tree.Node) map[string]*gosuki.Bookmark {
+	result := map[string]*gosuki.Bookmark{}
+	tree.MapNodeFunc(root, tree.URLNode, func(node *tree.Node) {
+		result[node.URL] = node.GetBookmark()
+	})
+	return result
+}
+
+func TestParse(t *testing.T) {
+	t.Run("edge", func(t *testing.T) {
+		root := parseFile(t, "testdata/edge.html")
+		assert.Equal(t, tree.RootNode, root.Type)
+
+		// the favorites bar is not kept as a folder
+		var folders []string
+		tree.MapNodeFunc(root, tree.FolderNode, func(node *tree.Node) {
+			folders = append(folders, node.Title)
+		})
+		assert.Equal(t, []string{"dev", "databases", "news"}, folders)
+
+		bks := bookmarks(root)
+		require.Len(t, bks, 4)
+
+		goDev := bks["https://go.dev/"]
+		assert.Equal(t, "The Go Programming Language", goDev.Title)
+		assert.Empty(t, goDev.Tags)
+		assert.Equal(t, uint64(1715000100), goDev.Created)
+
+		assert.ElementsMatch(t, []string{"dev"}, bks["https://pkg.go.dev/"].Tags)
+		assert.ElementsMatch(t, []string{"dev", "databases"}, bks["https://sqlite.org/"].Tags)
+		assert.ElementsMatch(t, []string{"news"}, bks["https://lwn.net/"].Tags)
+	})
+
+	t.Run("opera", func(t *testing.T) {
+		bks := bookmarks(parseFile(t, "testdata/opera.html"))
+		require.Len(t, bks, 2, "place: queries are skipped")
+
+		opera := bks["https://www.opera.com/"]
+		assert.Equal(t, "Opera web browser", opera.Desc)
+		assert.Empty(t, opera.Tags)
+
+		bread := bks["https://example.org/bread"]
+		assert.Equal(t, "Sourdough bread", bread.Title)
+		assert.ElementsMatch(t, []string{"baking", "bread", "recipes"}, bread.Tags)
+	})
+}
@@ -0,0 +1,231 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+// Package safari parses the bookmarks of Safari, either from the
+// Bookmarks.plist file of a macOS profile or from an HTML export. It does not
+// need macOS and can be used on bookmark files copied from another machine.
+package safari
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"howett.net/plist"
+
+	"github.com/blob42/gosuki/pkg/browsers/netscape"
+	"github.com/blob42/gosuki/pkg/tree"
+)
+
+const (
+	// BookmarksFile is the bookmarks file found in ~/Library/Safari
+	BookmarksFile = "Bookmarks.plist"
+
+	RootNodeName = "ROOT"
+
+	typeList  = "WebBookmarkTypeList"
+	typeLeaf  = "WebBookmarkTypeLeaf"
+	typeProxy = "WebBookmarkTypeProxy"
+
+	readingListTitle = "com.apple.ReadingList"
+)
+
+// root folders of Safari, their bookmarks are not tagged with their names
+var containers = []string{
+	"BookmarksBar", "BookmarksMenu",
+	// names used in the HTML exports
+	"Favorites", "Bookmarks Menu",
+}
+
+// Bookmarks holds the bookmarks and the Reading List of Safari
+type Bookmarks struct {
+	// Tree of the bookmarks. Folders are kept as FolderNode and become tags
+	// of their bookmarks.
+	Tree *tree.Node
+
+	ReadingList []ReadingItem
+}
+
+// ReadingItem is a page saved to the Reading List
+type ReadingItem struct {
+	URL   string
+	Title string
+
+	// Preview is the excerpt of the page shown in the Reading List
+	Preview string
+	Added   time.Time
+
+	// Viewed is zero when the item was not read yet
+	Viewed time.Time
+}
+
+// plistNode is a node of Bookmarks.plist
+type plistNode struct {
+	Type          string            `plist:"WebBookmarkType"`
+	Title         string            `plist:"Title"`
+	URL           string            `plist:"URLString"`
+	URIDictionary map[string]string `plist:"URIDictionary"`
+	ReadingList   *struct {
+		DateAdded      time.Time `plist:"DateAdded"`
+		DateLastViewed time.Time `plist:"DateLastViewed"`
+		PreviewText    string    `plist:"PreviewText"`
+	} `plist:"ReadingList"`
+	Children []*plistNode `plist:"Children"`
+}
+
+// ParsePlist parses a binary or XML Bookmarks.plist file
+func ParsePlist(r io.ReadSeeker) (*Bookmarks, error) {
+	var root plistNode
+	if err := plist.NewDecoder(r).Decode(&root); err != nil {
+		return nil, fmt.Errorf("parsing safari bookmarks: %w", err)
+	}
+	if root.Type != typeList {
+		return nil, fmt.Errorf("not a safari bookmarks file")
+	}
+
+	bookmarks := &Bookmarks{
+		Tree: &tree.Node{
+			Title: RootNodeName,
+			Type:  tree.RootNode,
+		},
+	}
+
+	for _, child := range root.Children {
+		switch {
+		case child.Title == readingListTitle:
+			bookmarks.ReadingList = append(bookmarks.ReadingList, readingList(child)...)
+		case child.Type == typeList && isContainer(child.Title):
+			for _, n := range child.Children {
+				addPlistNode(bookmarks.Tree, n)
+			}
+		default:
+			addPlistNode(bookmarks.Tree, child)
+		}
+	}
+
+	return bookmarks, nil
+}
+
+// addPlistNode adds node and its children to the tree under parent
+func addPlistNode(parent *tree.Node, node *plistNode) {
+	switch node.Type {
+	case typeLeaf:
+		if node.URL == "" {
+			return
+		}
+		tree.AddChild(parent, &tree.Node{
+			Title: node.URIDictionary["title"],
+			Type:  tree.URLNode,
+			URL:   node.URL,
+		})
+
+	case typeList:
+		folder := &tree.Node{
+			Title: node.Title,
+			Type:  tree.FolderNode,
+		}
+		tree.AddChild(parent, folder)
+		for _, child := range node.Children {
+			addPlistNode(folder, child)
+		}
+
+	// proxies are the History and Reading List entries of the sidebar
+	case typeProxy:
+	}
+}
+
+func readingList(node *plistNode) []ReadingItem {
+	var items []ReadingItem
+	for _, child := range node.Children {
+		if child.Type != typeLeaf || child.URL == "" {
+			continue
+		}
+
+		item := ReadingItem{
+			URL:   child.URL,
+			Title: child.URIDictionary["title"],
+		}
+		if rl := child.ReadingList; rl != nil {
+			item.Preview = rl.PreviewText
+			item.Added = rl.DateAdded
+			item.Viewed = rl.DateLastViewed
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// ParseHTML parses the HTML file of the "File > Export > Bookmarks" menu of
+// Safari. The Reading List is exported as a folder without preview text.
+func ParseHTML(r io.Reader) (*Bookmarks, error) {
+	root, err := netscape.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarks := &Bookmarks{
+		Tree: &tree.Node{
+			Title: RootNodeName,
+			Type:  tree.RootNode,
+		},
+	}
+
+	for _, child := range root.Children {
+		switch {
+		case child.Type == tree.FolderNode && isReadingList(child.Title):
+			for _, n := range child.Children {
+				if n.Type == tree.URLNode {
+					bookmarks.ReadingList = append(bookmarks.ReadingList, ReadingItem{
+						URL:   n.URL,
+						Title: n.Title,
+						Added: unixTime(n.Created),
+					})
+				}
+			}
+		case child.Type == tree.FolderNode && isContainer(child.Title):
+			for _, n := range child.Children {
+				tree.AddChild(bookmarks.Tree, n)
+			}
+		default:
+			tree.AddChild(bookmarks.Tree, child)
+		}
+	}
+
+	return bookmarks, nil
+}
+
+func isContainer(title string) bool {
+	return slices.Contains(containers, title)
+}
+
+func isReadingList(title string) bool {
+	return title == readingListTitle || strings.EqualFold(title, "Reading List")
+}
+
+func unixTime(sec uint64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(sec), 0)
+}
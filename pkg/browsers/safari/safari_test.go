@@ -0,0 +1,81 @@
+package safari
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/tree"
+)
+
+func bookmarks(root *tree.Node) map[string]*gosuki.Bookmark {
+	result := map[string]*gosuki.Bookmark{}
+	tree.MapNodeFunc(root, tree.URLNode, func(node *tree.Node) {
+		result[node.URL] = node.GetBookmark()
+	})
+	return result
+}
+
+// both the plist and its HTML export have the same bookmarks
+func assertTree(t *testing.T, root *tree.Node) {
+	t.Helper()
+
+	bks := bookmarks(root)
+	require.Len(t, bks, 4)
+
+	assert.Equal(t, "Apple", bks["https://www.apple.com/"].Title)
+	assert.Empty(t, bks["https://www.apple.com/"].Tags)
+	assert.ElementsMatch(t, []string{"dev"}, bks["https://go.dev/"].Tags)
+	assert.ElementsMatch(t, []string{"dev", "swift"}, bks["https://swift.org/"].Tags)
+	assert.ElementsMatch(t, []string{"news"}, bks["https://news.ycombinator.com/"].Tags)
+}
+
+func TestParsePlist(t *testing.T) {
+	f, err := os.Open("testdata/Bookmarks.plist")
+	require.NoError(t, err)
+	defer f.Close()
+
+	bookmarks, err := ParsePlist(f)
+	require.NoError(t, err)
+	assertTree(t, bookmarks.Tree)
+
+	require.Len(t, bookmarks.ReadingList, 2)
+	unread := bookmarks.ReadingList[0]
+	assert.Equal(t, "https://example.com/long-read", unread.URL)
+	assert.Equal(t, "A long read", unread.Title)
+	assert.Equal(t, "An article worth reading later.", unread.Preview)
+	assert.True(t, unread.Added.Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)))
+	assert.True(t, unread.Viewed.IsZero())
+
+	read := bookmarks.ReadingList[1]
+	assert.Equal(t, "Seen on the train.", read.Preview)
+	assert.True(t, read.Viewed.Equal(time.Date(2024, 3, 2, 18, 30, 0, 0, time.UTC)))
+}
+
+func TestParsePlistInvalid(t *testing.T) {
+	f, err := os.Open("testdata/Bookmarks.html")
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = ParsePlist(f)
+	assert.Error(t, err)
+}
+
+func TestParseHTML(t *testing.T) {
+	f, err := os.Open("testdata/Bookmarks.html")
+	require.NoError(t, err)
+	defer f.Close()
+
+	bookmarks, err := ParseHTML(f)
+	require.NoError(t, err)
+	assertTree(t, bookmarks.Tree)
+
+	require.Len(t, bookmarks.ReadingList, 2)
+	assert.Equal(t, "https://example.com/long-read", bookmarks.ReadingList[0].URL)
+	assert.Equal(t, "A long read", bookmarks.ReadingList[0].Title)
+	assert.Empty(t, bookmarks.ReadingList[0].Preview)
+}
@@ -0,0 +1,261 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+// Package bus implements the internal publish/subscribe event bus.
+//
+// Events are published on typed topics. Each subscriber gets its own bounded
+// buffer so that a slow or missing consumer never blocks the publisher. When a
+// buffer is full, events are dropped according to the subscriber [Policy].
+package bus
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/blob42/gosuki/pkg/logging"
+)
+
+// DefaultBufferSize is the buffer size of subscriptions that do not set one
+const DefaultBufferSize = 64
+
+var (
+	log = logging.GetLogger("bus")
+
+	droppedEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gosuki_events_dropped_total",
+		Help: "Number of events dropped because a subscriber buffer was full.",
+	}, []string{"topic", "subscriber"})
+)
+
+// Policy defines what happens when an event is published to a subscriber with
+// a full buffer.
+type Policy int
+
+const (
+	// DropOldest discards the oldest buffered event to make room for the new
+	// one
+	DropOldest Policy = iota
+
+	// DropNewest discards the published event
+	DropNewest
+
+	// Coalesce replaces a buffered event that has the same key as the
+	// published event, see [NewTopic]. Events without a matching key are
+	// handled with DropOldest.
+	Coalesce
+)
+
+// Options of a subscription
+type Options struct {
+	// Name identifies the subscriber in logs and metrics
+	Name string
+
+	// Buffer is the number of events buffered for the subscriber. Defaults
+	// to DefaultBufferSize.
+	Buffer int
+
+	Policy Policy
+}
+
+// KeyFunc returns the key used to coalesce events. Keys must be comparable,
+// events with a nil key are never coalesced.
+type KeyFunc[T any] func(T) any
+
+// Topic is a typed channel of events with any number of subscribers
+type Topic[T any] struct {
+	name string
+	key  KeyFunc[T]
+
+	mu   sync.RWMutex
+	subs map[*Subscription[T]]struct{}
+}
+
+// NewTopic creates a topic. key is used by subscribers with the [Coalesce]
+// policy and can be nil.
+func NewTopic[T any](name string, key KeyFunc[T]) *Topic[T] {
+	return &Topic[T]{
+		name: name,
+		key:  key,
+		subs: map[*Subscription[T]]struct{}{},
+	}
+}
+
+func (t *Topic[T]) Name() string {
+	return t.name
+}
+
+// Publish sends ev to all the current subscribers. It never blocks, events
+// published without subscribers are discarded.
+func (t *Topic[T]) Publish(ev T) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var key any
+	if t.key != nil && len(t.subs) > 0 {
+		key = t.key(ev)
+	}
+
+	for sub := range t.subs {
+		sub.push(ev, key)
+	}
+}
+
+// Subscribe returns a new subscription receiving the events published from
+// now on. It must be closed when no longer used.
+func (t *Topic[T]) Subscribe(opts Options) *Subscription[T] {
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultBufferSize
+	}
+
+	out := make(chan T)
+	sub := &Subscription[T]{
+		C:     out,
+		topic: t,
+		opts:  opts,
+		out:   out,
+		ready: make(chan struct{}, 1),
+		quit:  make(chan struct{}),
+	}
+
+	t.mu.Lock()
+	t.subs[sub] = struct{}{}
+	t.mu.Unlock()
+
+	go sub.deliver()
+	return sub
+}
+
+// Subscribers returns the number of active subscriptions
+func (t *Topic[T]) Subscribers() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.subs)
+}
+
+type pending[T any] struct {
+	ev  T
+	key any
+}
+
+// Subscription receives the events of a topic on C
+type Subscription[T any] struct {
+	// C delivers the events in the order they were published. It is closed
+	// when the subscription is closed.
+	C <-chan T
+
+	topic *Topic[T]
+	opts  Options
+	out   chan T
+
+	mu    sync.Mutex
+	queue []pending[T]
+
+	ready     chan struct{}
+	quit      chan struct{}
+	closeOnce sync.Once
+}
+
+func (s *Subscription[T]) push(ev T, key any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.opts.Policy == Coalesce && key != nil {
+		for i := range s.queue {
+			if s.queue[i].key == key {
+				s.queue[i].ev = ev
+				return
+			}
+		}
+	}
+
+	if len(s.queue) >= s.opts.Buffer {
+		droppedEvents.WithLabelValues(s.topic.name, s.opts.Name).Inc()
+		log.Trace("subscriber buffer full", "topic", s.topic.name, "subscriber", s.opts.Name)
+		if s.opts.Policy == DropNewest {
+			return
+		}
+		s.queue = s.queue[1:]
+	}
+
+	s.queue = append(s.queue, pending[T]{ev, key})
+
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Subscription[T]) pop() (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var zero T
+	if len(s.queue) == 0 {
+		return zero, false
+	}
+	ev := s.queue[0].ev
+	s.queue[0] = pending[T]{}
+	s.queue = s.queue[1:]
+	return ev, true
+}
+
+// deliver forwards the buffered events to C
+func (s *Subscription[T]) deliver() {
+	defer close(s.out)
+	for {
+		ev, ok := s.pop()
+		if !ok {
+			select {
+			case <-s.ready:
+				continue
+			case <-s.quit:
+				return
+			}
+		}
+
+		select {
+		case s.out <- ev:
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// Len returns the number of buffered events
+func (s *Subscription[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queue)
+}
+
+// Close unsubscribes from the topic and closes C. Buffered events are
+// discarded.
+func (s *Subscription[T]) Close() {
+	s.closeOnce.Do(func() {
+		s.topic.mu.Lock()
+		delete(s.topic.subs, s)
+		s.topic.mu.Unlock()
+		close(s.quit)
+	})
+}
@@ -0,0 +1,134 @@
+package bus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type progress struct {
+	id    string
+	count int
+}
+
+func progressKey(p progress) any {
+	if p.id == "" {
+		return nil
+	}
+	return p.id
+}
+
+// queued returns a subscription without the delivery goroutine to inspect its
+// buffer
+func queued[T any](topic *Topic[T], opts Options) *Subscription[T] {
+	return &Subscription[T]{
+		topic: topic,
+		opts:  opts,
+		ready: make(chan struct{}, 1),
+		quit:  make(chan struct{}),
+	}
+}
+
+func events[T any](s *Subscription[T]) []T {
+	var res []T
+	for _, p := range s.queue {
+		res = append(res, p.ev)
+	}
+	return res
+}
+
+func recv[T any](t *testing.T, s *Subscription[T]) T {
+	t.Helper()
+	select {
+	case ev := <-s.C:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+	var zero T
+	return zero
+}
+
+func TestPublishWithoutSubscribers(t *testing.T) {
+	topic := NewTopic[int]("test", nil)
+	done := make(chan struct{})
+	go func() {
+		topic.Publish(1)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publish blocked")
+	}
+}
+
+func TestSubscribers(t *testing.T) {
+	topic := NewTopic[int]("test", nil)
+	a := topic.Subscribe(Options{Name: "a"})
+	b := topic.Subscribe(Options{Name: "b"})
+	assert.Equal(t, 2, topic.Subscribers())
+
+	topic.Publish(1)
+	topic.Publish(2)
+
+	for _, sub := range []*Subscription[int]{a, b} {
+		assert.Equal(t, 1, recv(t, sub))
+		assert.Equal(t, 2, recv(t, sub))
+	}
+
+	a.Close()
+	a.Close()
+	assert.Equal(t, 1, topic.Subscribers())
+	_, ok := <-a.C
+	assert.False(t, ok, "closed subscription should close C")
+
+	topic.Publish(3)
+	assert.Equal(t, 3, recv(t, b))
+	b.Close()
+}
+
+func TestDropPolicies(t *testing.T) {
+	topic := NewTopic[int]("test", nil)
+
+	oldest := queued(topic, Options{Buffer: 2, Policy: DropOldest})
+	newest := queued(topic, Options{Buffer: 2, Policy: DropNewest})
+	for i := 1; i <= 4; i++ {
+		oldest.push(i, nil)
+		newest.push(i, nil)
+	}
+
+	assert.Equal(t, []int{3, 4}, events(oldest))
+	assert.Equal(t, []int{1, 2}, events(newest))
+}
+
+func TestCoalesce(t *testing.T) {
+	topic := NewTopic("progress", progressKey)
+	sub := queued(topic, Options{Buffer: 4, Policy: Coalesce})
+
+	for _, p := range []progress{
+		{"firefox", 1},
+		{"chrome", 1},
+		{"firefox", 2},
+		{"", 1},
+		{"", 2},
+		{"firefox", 3},
+	} {
+		sub.push(p, topic.key(p))
+	}
+
+	assert.Equal(t, []progress{
+		{"firefox", 3},
+		{"chrome", 1},
+		{"", 1},
+		{"", 2},
+	}, events(sub))
+	require.Equal(t, 4, sub.Len())
+
+	// events without key fall back to drop oldest
+	sub.push(progress{"", 3}, nil)
+	assert.Equal(t, []progress{{"chrome", 1}, {"", 1}, {"", 2}, {"", 3}}, events(sub))
+}
@@ -0,0 +1,74 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package bus
+
+import (
+	"time"
+
+	"github.com/blob42/gosuki"
+)
+
+// BookmarkEventType is the kind of change of a bookmark
+type BookmarkEventType string
+
+const (
+	BookmarkAdded   BookmarkEventType = "added"
+	BookmarkUpdated BookmarkEventType = "updated"
+	BookmarkDeleted BookmarkEventType = "deleted"
+)
+
+// BookmarkEvent is published when a bookmark is added, updated or deleted
+type BookmarkEvent struct {
+	Type     BookmarkEventType
+	Bookmark *gosuki.Bookmark
+}
+
+// SyncEvent is published after the caches are written to disk
+type SyncEvent struct {
+	Time     time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// UnitEvent is published when a unit run by the manager changes state. Module
+// units are named after the module id.
+type UnitEvent struct {
+	Name  string
+	State string // see manager.UnitState
+	Err   string // reason of the failure when the unit panicked
+}
+
+var (
+	Bookmarks = NewTopic[BookmarkEvent]("bookmarks", func(ev BookmarkEvent) any {
+		if ev.Bookmark == nil {
+			return nil
+		}
+		return ev.Bookmark.URL
+	})
+
+	Syncs = NewTopic[SyncEvent]("syncs", nil)
+
+	Units = NewTopic[UnitEvent]("units", func(ev UnitEvent) any {
+		return ev.Name
+	})
+)
@@ -51,6 +51,7 @@ var GlobalConfig = struct {
 var (
 	log            = logging.GetLogger("conf")
 	ConfReadyHooks []Hook
+	resetHooks     []func()
 	configs        = make(map[string]Configurator)
 )
 
@@ -207,6 +208,13 @@ func RegisterConfReadyHooks(hooks ...Hook) {
 	ConfReadyHooks = append(ConfReadyHooks, hooks...)
 }
 
+// RegisterResetHook registers a function called before the config file is
+// reloaded. Lists are merged when decoding the config, configurators holding
+// lists use it to drop the entries removed from the file.
+func RegisterResetHook(f func()) {
+	resetHooks = append(resetHooks, f)
+}
+
 // A call to this func will run all registered config hooks
 func RunConfHooks(ctx context.Context, cmd *cli.Command) {
 	log.Debug("running config hooks")
@@ -26,6 +26,7 @@ import (
 	"maps"
 	"os"
 	"path"
+	"reflect"
 	"slices"
 
 	"github.com/BurntSushi/toml"
@@ -130,6 +131,57 @@ func createConfFile(path string) error {
 	return nil
 }
 
+// Reload validates the config file at path then loads it, replacing the
+// current config values. The file is decoded into fresh config values first,
+// if it is not valid the current config is kept.
+func Reload(path string) error {
+	buffer := make(Config)
+	if _, err := toml.DecodeFile(path, &buffer); err != nil {
+		return fmt.Errorf("invalid config file: %w", err)
+	}
+
+	// lists are merged when decoding, start from an empty list so removed
+	// entries are not kept
+	global := GlobalConfig
+	global.DisabledModules = []string{}
+	if err := AsConfigurator(&global).MapFrom(buffer); err != nil {
+		return fmt.Errorf("invalid config file: %w", err)
+	}
+
+	for k, val := range buffer {
+		c, ok := configs[k]
+		if !ok || k == GlobalConfigName {
+			continue
+		}
+		if err := freshConfigurator(c).MapFrom(val); err != nil {
+			return fmt.Errorf("invalid config file: parsing config <%s>: %w", k, err)
+		}
+	}
+
+	GlobalConfig = global
+	for _, f := range resetHooks {
+		f()
+	}
+
+	return loadModuleConfigs(buffer)
+}
+
+// freshConfigurator returns a configurator of the same type as c holding a
+// zero value, used to validate a config without changing c
+func freshConfigurator(c Configurator) Configurator {
+	switch c := c.(type) {
+	case AutoConfigurator:
+		v := reflect.ValueOf(c.c)
+		if v.Kind() != reflect.Pointer {
+			return c
+		}
+		return AutoConfigurator{reflect.New(v.Elem().Type()).Interface()}
+	case Config:
+		return make(Config)
+	}
+	return c
+}
+
 func LoadFromTomlFile(path string) error {
 	buffer := make(Config)
 	_, err := toml.DecodeFile(path, &buffer)
@@ -142,6 +194,12 @@ func LoadFromTomlFile(path string) error {
 		return err
 	}
 
+	return loadModuleConfigs(buffer)
+}
+
+// loadModuleConfigs sends the module sections of buffer to their configurators
+func loadModuleConfigs(buffer Config) error {
+	var err error
 	for k, val := range buffer {
 
 		// only consider module configs
@@ -23,16 +23,30 @@
 package events
 
 import (
+	"github.com/blob42/gosuki/pkg/bus"
 	"github.com/blob42/gosuki/pkg/modules"
 	"github.com/blob42/gosuki/pkg/watch"
 )
 
-// TUIBus is a channel for sending messages to the Text User Interface (TUI).
-// Modules should use this channel to signal changes in their loading status.
-var (
-	//TODO: add debounce to avoid congestion
-	TUIBus = make(chan any)
-)
+// TUI is the topic of the messages for the Text User Interface (TUI).
+// Modules should publish on this topic to signal changes in their loading
+// status. Publishing never blocks, messages are discarded when the TUI is not
+// running.
+var TUI = bus.NewTopic[any]("tui", progressKey)
+
+type progressInstance struct {
+	ID       modules.ModID
+	Instance modules.Module
+}
+
+// progressKey allows coalescing the progress updates of a module instance.
+// Updates for new bookmarks are counted by the TUI and never coalesced.
+func progressKey(msg any) any {
+	if msg, ok := msg.(ProgressUpdateMsg); ok && !msg.NewBk {
+		return progressInstance{msg.ID, msg.Instance}
+	}
+	return nil
+}
 
 // StartedLoadingMsg represents a message indicating that a module has started loading.
 // It contains the ID of the module and the total number of items to be loaded.
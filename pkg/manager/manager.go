@@ -22,6 +22,7 @@
 package manager
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -30,21 +31,39 @@ import (
 	"sync"
 	"time"
 
+	"maps"
 	"slices"
 
 	"github.com/blob42/gosuki/internal/webui"
+	"github.com/blob42/gosuki/pkg/bus"
 	"github.com/blob42/gosuki/pkg/logging"
 )
 
-const MaxRecover = 3
+const (
+	// MaxRecover is the number of consecutive restarts of a recoverable unit
+	// before it is marked as failed
+	MaxRecover = 3
+
+	// a unit running for this long is considered stable again and its
+	// consecutive restarts are reset
+	stableRunTime = 5 * time.Minute
+)
 
 var (
+	// RestartBackoff is the delay before the first restart of a unit. It is
+	// doubled after each consecutive restart up to MaxRestartBackoff.
+	RestartBackoff    = time.Second
+	MaxRestartBackoff = time.Minute
+
 	idGenerator = genID()
 	log         = logging.GetLogger("mngr")
+
+	ErrUnitNotFound = errors.New("unit not found")
 )
 
 // The WorkUnit interface is used to define a unit of work.
-// The Run method will be called in a goroutine.
+// The Run method will be called in a goroutine. A unit can be run again
+// after it stopped, when restarted after a panic or with [Manager.StartUnit].
 type WorkUnit interface {
 	Run(UnitManager)
 }
@@ -61,45 +80,244 @@ type UnitManager interface {
 	RequestShutdown()
 }
 
+type UnitState string
+
+const (
+	UnitRunning    UnitState = "running"
+	UnitRestarting UnitState = "restarting" // waiting to be restarted after a panic
+	UnitFailed     UnitState = "failed"     // panicked and will not be restarted
+	UnitStopped    UnitState = "stopped"
+)
+
+// WorkUnitManager supervises a work unit. Recoverable units are restarted
+// with an exponential backoff when they panic.
 type WorkUnitManager struct {
-	name         string
-	stop         chan bool
-	workerQuit   chan bool
-	unit         WorkUnit
-	panic        chan error
-	isPaniced    bool
-	recover      bool
-	recoverCount int
+	name    string
+	unit    WorkUnit
+	panic   chan error
+	recover bool
+
+	mu            sync.Mutex
+	state         UnitState
+	run           *unitRun // current run of the unit
+	started       time.Time
+	restarts      int // consecutive restarts
+	totalRestarts int
+	lastErr       string
+	lastErrTime   time.Time
+	restartTimer  *time.Timer
+}
+
+// unitRun is the [UnitManager] handed to a single run of a unit. Using a new
+// one for each run ensures a late call from a previous run does not affect the
+// current one.
+type unitRun struct {
+	w        *WorkUnitManager
+	stop     chan bool
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+func (r *unitRun) ShouldStop() <-chan bool {
+	return r.stop
+}
+
+func (r *unitRun) Done() {
+	r.doneOnce.Do(func() { close(r.done) })
+}
+
+func (r *unitRun) Panic(val any) {
+	r.w.handlePanic(r, val)
+}
+
+func (r *unitRun) RequestShutdown() {
+	r.w.panic <- fmt.Errorf("request for shutdown")
+}
+
+// requestStop asks the run to stop without waiting
+func (r *unitRun) requestStop() {
+	select {
+	case r.stop <- true:
+	default:
+	}
+}
+
+func (w *WorkUnitManager) SetRecoverable() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.recover = true
+}
+
+func backoff(restarts int) time.Duration {
+	d := RestartBackoff << restarts
+	if d <= 0 || d > MaxRestartBackoff {
+		return MaxRestartBackoff
+	}
+	return d
+}
+
+func (w *WorkUnitManager) handlePanic(r *unitRun, val any) {
+	err := fmt.Errorf("%v", val)
+
+	w.mu.Lock()
+	if w.run != r || w.state != UnitRunning {
+		// the unit was stopped or already restarted
+		w.mu.Unlock()
+		log.Debug("ignoring panic of stale unit", "unit", w.name, "reason", val)
+		return
+	}
+
+	w.lastErr = err.Error()
+	w.lastErrTime = time.Now()
+
+	// the unit may still be waiting for the stop signal
+	r.requestStop()
+
+	if !w.recover {
+		w.setState(UnitFailed)
+		w.mu.Unlock()
+
+		select {
+		case w.panic <- err:
+		default:
+		}
+		return
+	}
+
+	if time.Since(w.started) > stableRunTime {
+		w.restarts = 0
+	}
+
+	if w.restarts >= MaxRecover {
+		w.setState(UnitFailed)
+		w.mu.Unlock()
+		log.Error("unit failed, giving up", "unit", w.name, "restarts", MaxRecover, "reason", val)
+		return
+	}
+
+	delay := backoff(w.restarts)
+	w.restarts++
+	w.totalRestarts++
+	w.setState(UnitRestarting)
+	w.restartTimer = time.AfterFunc(delay, func() {
+		// the runs of a unit must not overlap, the panicked run may still be
+		// stopping
+		<-r.done
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if w.state == UnitRestarting && w.run == r {
+			w.startLocked()
+		}
+	})
+	w.mu.Unlock()
+
+	log.Error("panic", "unit", w.name, "reason", val)
+	log.Warn("recovering unit", "unit", w.name, "in", delay)
 }
 
-func (w *WorkUnitManager) ShouldStop() <-chan bool {
-	return w.stop
+// setState changes the state of the unit and publishes it on [bus.Units].
+// w.mu must be held.
+func (w *WorkUnitManager) setState(state UnitState) {
+	w.state = state
+
+	ev := bus.UnitEvent{Name: w.name, State: string(state)}
+	if state == UnitFailed || state == UnitRestarting {
+		ev.Err = w.lastErr
+	}
+	bus.Units.Publish(ev)
 }
 
-func (w *WorkUnitManager) Done() {
-	w.workerQuit <- true
+// startLocked starts a new run of the unit. w.mu must be held.
+func (w *WorkUnitManager) startLocked() {
+	r := &unitRun{
+		w:    w,
+		stop: make(chan bool, 1),
+		done: make(chan struct{}),
+	}
+	w.run = r
+	w.setState(UnitRunning)
+	w.started = time.Now()
+	go w.runUnit(r)
+}
+
+func (w *WorkUnitManager) runUnit(r *unitRun) {
+	defer func() {
+		// Handle panics within the unit's goroutine
+		if val := recover(); val != nil {
+			r.Panic(val)
+		}
+		r.Done()
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if w.run == r && w.state == UnitRunning {
+			log.Warn("unit exited", "unit", w.name)
+			w.setState(UnitStopped)
+		}
+	}()
+
+	log.Info("starting", "unit", w.name)
+	w.unit.Run(r)
 }
 
-func (w *WorkUnitManager) Panic(val any) {
-	if w.recover && w.recoverCount <= MaxRecover {
-		log.Error("panic", "unit", w.name, "reason", val)
-		log.Warnf("recovering unit %s", w.name)
-		time.Sleep(time.Second * 2)
-		go runUnit(w, w.name)
-		w.recoverCount += 1
+// stopUnit stops the unit and waits for it to be done
+func (w *WorkUnitManager) stopUnit() {
+	w.mu.Lock()
+	switch w.state {
+	case UnitRunning:
+		r := w.run
+		w.setState(UnitStopped)
+		w.mu.Unlock()
+
+		r.requestStop()
+		<-r.done
 		return
+	case UnitRestarting:
+		w.restartTimer.Stop()
+		w.setState(UnitStopped)
 	}
-	w.panic <- fmt.Errorf("%v", val)
-	w.isPaniced = true
-	w.workerQuit <- true
+	w.mu.Unlock()
 }
 
-func (m *WorkUnitManager) RequestShutdown() {
-	m.panic <- fmt.Errorf("request for shutdown")
+// startUnit starts the unit if it is stopped or failed. It reports whether
+// the unit was started.
+func (w *WorkUnitManager) startUnit() bool {
+	w.mu.Lock()
+	if w.state != UnitStopped && w.state != UnitFailed {
+		w.mu.Unlock()
+		return false
+	}
+
+	// a failed run may still be stopping, wait for it without holding the
+	// lock as it can still call Panic
+	if prev := w.run; prev != nil {
+		w.mu.Unlock()
+		<-prev.done
+		w.mu.Lock()
+		if w.run != prev || (w.state != UnitStopped && w.state != UnitFailed) {
+			w.mu.Unlock()
+			return false
+		}
+	}
+	defer w.mu.Unlock()
+
+	w.restarts = 0
+	w.startLocked()
+	return true
 }
 
-func (wu *WorkUnitManager) SetRecoverable() {
-	wu.recover = true
+func (w *WorkUnitManager) status() UnitStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return UnitStatus{
+		Name:        w.name,
+		State:       w.state,
+		Since:       w.started,
+		Restarts:    w.totalRestarts,
+		LastError:   w.lastErr,
+		LastErrorAt: w.lastErrTime,
+	}
 }
 
 type Manager struct {
@@ -114,17 +332,20 @@ type Manager struct {
 
 func (m *Manager) Shutdown() {
 	<-m.ready
-	// send shutdown event to all worker units
-	for name, w := range m.workers {
-		log.Debugf("stopping %s\n", name)
-		w.stop <- true
-	}
-
-	// Wait for all units to quit
-	for name, w := range m.workers {
-		<-w.workerQuit
-		log.Debugf("%s down", name)
+	workers := m.snapshot()
+
+	// stop all worker units and wait for them to quit
+	var wg sync.WaitGroup
+	for name, w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log.Debugf("stopping %s\n", name)
+			w.stopUnit()
+			log.Debugf("%s down", name)
+		}()
 	}
+	wg.Wait()
 
 	// All workers have shutdown
 	log.Info("all workers down, stopping manager ...")
@@ -159,13 +380,12 @@ func (m *Manager) Start() {
 
 		case p := <-m.panic:
 
-			for name, w := range m.workers {
-				if w.isPaniced {
+			for name, w := range m.snapshot() {
+				if w.status().State == UnitFailed {
 					log.Errorf("<%s> panicked: %s", name, p)
 				} else {
 					log.Debugf("shuting down <%s>\n", name)
-					w.stop <- true
-					<-w.workerQuit
+					w.stopUnit()
 					log.Debugf("<%s> down", name)
 				}
 			}
@@ -199,25 +419,23 @@ func genID() IDGenerator {
 	}
 }
 
-func runUnit(wum *WorkUnitManager, unitName string) {
-	defer func() {
-		// Handle panics within the unit's goroutine
-		if r := recover(); r != nil {
-			wum.panic <- fmt.Errorf("%v", r)
-			wum.isPaniced = true
-		}
-	}()
-	log.Info("starting", "unit", unitName)
-	wum.unit.Run(wum)
+func (m *Manager) AddUnit(unit WorkUnit, name string) *WorkUnitManager {
+	return m.addUnit(unit, name, false)
 }
 
-func (m *Manager) AddUnit(unit WorkUnit, name string) *WorkUnitManager {
+// AddRecoverableUnit adds a unit that is restarted with a backoff when it
+// panics. A recoverable unit that keeps failing is marked as failed without
+// shutting down the manager.
+func (m *Manager) AddRecoverableUnit(unit WorkUnit, name string) *WorkUnitManager {
+	return m.addUnit(unit, name, true)
+}
+
+func (m *Manager) addUnit(unit WorkUnit, name string, recoverable bool) *WorkUnitManager {
 	workUnitManager := &WorkUnitManager{
-		name:       name,
-		workerQuit: make(chan bool, 1),
-		stop:       make(chan bool, 1),
-		unit:       unit,
-		panic:      m.panic,
+		name:    name,
+		unit:    unit,
+		panic:   m.panic,
+		recover: recoverable,
 	}
 
 	unitType := reflect.TypeOf(unit)
@@ -232,11 +450,82 @@ func (m *Manager) AddUnit(unit WorkUnit, name string) *WorkUnitManager {
 	m.workers[unitName] = workUnitManager
 
 	// Launch the unit's goroutine *immediatly*
-	go runUnit(workUnitManager, unitName)
+	workUnitManager.mu.Lock()
+	workUnitManager.startLocked()
+	workUnitManager.mu.Unlock()
 
 	return workUnitManager
 }
 
+// RemoveUnit stops all the units added with the given name and removes them
+// from the manager. It returns the number of removed units.
+func (m *Manager) RemoveUnit(name string) int {
+	var removed []*WorkUnitManager
+
+	m.mu.Lock()
+	for key, w := range m.workers {
+		if w.name == name {
+			delete(m.workers, key)
+			removed = append(removed, w)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, w := range removed {
+		log.Debug("stopping", "unit", name)
+		w.stopUnit()
+	}
+
+	return len(removed)
+}
+
+// named returns the units added with the given name
+func (m *Manager) named(name string) []*WorkUnitManager {
+	var res []*WorkUnitManager
+	for _, w := range m.snapshot() {
+		if w.name == name {
+			res = append(res, w)
+		}
+	}
+	return res
+}
+
+// StopUnit stops all the units added with the given name. Unlike
+// [Manager.RemoveUnit], the units are kept and can be started again with
+// [Manager.StartUnit].
+func (m *Manager) StopUnit(name string) error {
+	units := m.named(name)
+	if len(units) == 0 {
+		return fmt.Errorf("%w: %s", ErrUnitNotFound, name)
+	}
+
+	for _, w := range units {
+		log.Info("stopping", "unit", name)
+		w.stopUnit()
+	}
+	return nil
+}
+
+// StartUnit starts all the stopped or failed units added with the given name
+func (m *Manager) StartUnit(name string) error {
+	units := m.named(name)
+	if len(units) == 0 {
+		return fmt.Errorf("%w: %s", ErrUnitNotFound, name)
+	}
+
+	for _, w := range units {
+		w.startUnit()
+	}
+	return nil
+}
+
+// snapshot returns a copy of the workers map
+func (m *Manager) snapshot() map[string]*WorkUnitManager {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return maps.Clone(m.workers)
+}
+
 func NewManager() *Manager {
 	return &Manager{
 		signalIn: make(chan os.Signal, 1),
@@ -257,6 +546,28 @@ func (m *Manager) Units() map[string]WorkUnit {
 	return res
 }
 
+// UnitStatus describes the state of a unit managed by the manager
+type UnitStatus struct {
+	Name        string    // name given to [Manager.AddUnit]
+	State       UnitState // current state of the unit
+	Since       time.Time // last time the unit was started
+	Restarts    int       // number of times the unit was restarted after a panic
+	LastError   string    // reason of the last panic
+	LastErrorAt time.Time
+}
+
+// Status returns the state of all units sorted by name
+func (m *Manager) Status() []UnitStatus {
+	var res []UnitStatus
+	for _, w := range m.snapshot() {
+		res = append(res, w.status())
+	}
+	slices.SortFunc(res, func(a, b UnitStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return res
+}
+
 // Test if signal is in array
 func in(arr []os.Signal, sig os.Signal) bool {
 	return slices.Contains(arr, sig)
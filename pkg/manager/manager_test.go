@@ -1,11 +1,16 @@
 package manager
 
 import (
+	"errors"
+	"fmt"
 	llog "log"
 	"os"
+	"sync/atomic"
 	"syscall"
 	"testing"
 	"time"
+
+	"github.com/blob42/gosuki/pkg/bus"
 )
 
 var WorkerID int
@@ -91,3 +96,279 @@ func TestRunMain(t *testing.T) {
 		<-quit
 	}
 }
+
+type stopWorker struct {
+	stopped chan bool
+}
+
+func (w *stopWorker) Run(um UnitManager) {
+	<-um.ShouldStop()
+	w.stopped <- true
+	um.Done()
+}
+
+func TestRemoveUnit(t *testing.T) {
+	manager := NewManager()
+
+	w1 := &stopWorker{make(chan bool, 1)}
+	w2 := &stopWorker{make(chan bool, 1)}
+	manager.AddUnit(w1, "mod")
+	manager.AddUnit(w2, "other")
+
+	if n := manager.RemoveUnit("mod"); n != 1 {
+		t.Fatalf("expected 1 removed unit, got %d", n)
+	}
+
+	select {
+	case <-w1.stopped:
+	case <-time.After(time.Second):
+		t.Fatal("unit was not stopped")
+	}
+
+	units := manager.Units()
+	if _, ok := units["mod"]; ok {
+		t.Error("removed unit still registered")
+	}
+	if _, ok := units["other"]; !ok {
+		t.Error("other unit should still be registered")
+	}
+
+	if n := manager.RemoveUnit("mod"); n != 0 {
+		t.Errorf("expected 0 removed unit, got %d", n)
+	}
+}
+
+type panicWorker struct{}
+
+func (w *panicWorker) Run(um UnitManager) {
+	um.Panic("boom")
+}
+
+func TestUnitStatus(t *testing.T) {
+	manager := NewManager()
+
+	manager.AddUnit(&stopWorker{make(chan bool, 1)}, "ok")
+	manager.AddUnit(&panicWorker{}, "failing")
+
+	deadline := time.Now().Add(time.Second)
+	var status []UnitStatus
+	for time.Now().Before(deadline) {
+		status = manager.Status()
+		if len(status) == 2 && status[0].State == UnitFailed && status[1].State == UnitRunning {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if len(status) != 2 {
+		t.Fatalf("expected 2 units, got %d", len(status))
+	}
+	if status[0].Name != "failing" || status[0].State != UnitFailed || status[0].LastError != "boom" {
+		t.Errorf("unexpected status for failing unit: %+v", status[0])
+	}
+	if status[1].Name != "ok" || status[1].State != UnitRunning {
+		t.Errorf("unexpected status for running unit: %+v", status[1])
+	}
+}
+
+// flakyWorker panics until it has been run `fails` times
+type flakyWorker struct {
+	fails int
+	runs  int
+}
+
+func (w *flakyWorker) Run(um UnitManager) {
+	w.runs++
+	if w.runs <= w.fails {
+		um.Panic(fmt.Sprintf("run %d failed", w.runs))
+		return
+	}
+	<-um.ShouldStop()
+	um.Done()
+}
+
+func waitState(t *testing.T, m *Manager, name string, state UnitState) UnitStatus {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, s := range m.Status() {
+			if s.Name == name && s.State == state {
+				return s
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("unit %s did not reach state %s: %+v", name, state, m.Status())
+	return UnitStatus{}
+}
+
+func shortBackoff(t *testing.T) {
+	backoff, maxBackoff := RestartBackoff, MaxRestartBackoff
+	RestartBackoff, MaxRestartBackoff = 10*time.Millisecond, 40*time.Millisecond
+	t.Cleanup(func() {
+		RestartBackoff, MaxRestartBackoff = backoff, maxBackoff
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	shortBackoff(t)
+	want := []time.Duration{10, 20, 40, 40, 40}
+	for i, d := range want {
+		if got := backoff(i); got != d*time.Millisecond {
+			t.Errorf("backoff(%d) = %s, want %s", i, got, d*time.Millisecond)
+		}
+	}
+}
+
+func TestRestartRecoverable(t *testing.T) {
+	shortBackoff(t)
+	manager := NewManager()
+
+	w := &flakyWorker{fails: 2}
+	manager.AddRecoverableUnit(w, "flaky")
+
+	status := waitState(t, manager, "flaky", UnitRunning)
+	for status.Restarts != 2 {
+		status = waitState(t, manager, "flaky", UnitRunning)
+	}
+	if status.LastError != "run 2 failed" {
+		t.Errorf("unexpected last error: %q", status.LastError)
+	}
+}
+
+func TestRecoverableGivesUp(t *testing.T) {
+	shortBackoff(t)
+	manager := NewManager()
+
+	manager.AddRecoverableUnit(&flakyWorker{fails: MaxRecover + 10}, "flaky")
+	manager.AddUnit(&stopWorker{make(chan bool, 1)}, "ok")
+
+	status := waitState(t, manager, "flaky", UnitFailed)
+	if status.Restarts != MaxRecover {
+		t.Errorf("expected %d restarts, got %d", MaxRecover, status.Restarts)
+	}
+
+	// a failed recoverable unit must not shut down the manager
+	select {
+	case err := <-manager.panic:
+		t.Fatalf("manager received panic: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	waitState(t, manager, "ok", UnitRunning)
+}
+
+func TestStopStartUnit(t *testing.T) {
+	manager := NewManager()
+
+	w := &stopWorker{make(chan bool, 1)}
+	manager.AddUnit(w, "mod")
+	waitState(t, manager, "mod", UnitRunning)
+
+	if err := manager.StopUnit("mod"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-w.stopped:
+	case <-time.After(time.Second):
+		t.Fatal("unit was not stopped")
+	}
+	waitState(t, manager, "mod", UnitStopped)
+
+	// the unit is kept
+	if _, ok := manager.Units()["mod"]; !ok {
+		t.Error("stopped unit should still be registered")
+	}
+
+	if err := manager.StartUnit("mod"); err != nil {
+		t.Fatal(err)
+	}
+	waitState(t, manager, "mod", UnitRunning)
+
+	if err := manager.StopUnit("missing"); !errors.Is(err, ErrUnitNotFound) {
+		t.Errorf("expected ErrUnitNotFound, got %v", err)
+	}
+}
+
+func TestUnitEvents(t *testing.T) {
+	sub := bus.Units.Subscribe(bus.Options{Name: "test"})
+	defer sub.Close()
+
+	manager := NewManager()
+	manager.AddUnit(&panicWorker{}, "events")
+
+	var states []string
+	timeout := time.After(time.Second)
+	for len(states) < 2 {
+		select {
+		case ev := <-sub.C:
+			if ev.Name != "events" {
+				continue
+			}
+			states = append(states, ev.State)
+			if ev.State == string(UnitFailed) && ev.Err != "boom" {
+				t.Errorf("unexpected error: %q", ev.Err)
+			}
+		case <-timeout:
+			t.Fatalf("missing unit events, got %v", states)
+		}
+	}
+
+	if states[0] != string(UnitRunning) || states[1] != string(UnitFailed) {
+		t.Errorf("unexpected states: %v", states)
+	}
+}
+
+// slowStopWorker panics on its first run and takes longer than the restart
+// backoff to stop
+type slowStopWorker struct {
+	runs    int // not synchronized, the runs must not overlap
+	running atomic.Int32
+	overlap atomic.Bool
+}
+
+func (w *slowStopWorker) Run(um UnitManager) {
+	if w.running.Add(1) > 1 {
+		w.overlap.Store(true)
+	}
+	defer w.running.Add(-1)
+
+	w.runs++
+	if w.runs == 1 {
+		um.Panic("boom")
+		<-um.ShouldStop()
+		time.Sleep(100 * time.Millisecond)
+		w.runs++
+		um.Done()
+		return
+	}
+	<-um.ShouldStop()
+	um.Done()
+}
+
+func TestRestartWaitsForPanickedRun(t *testing.T) {
+	shortBackoff(t)
+	manager := NewManager()
+
+	w := &slowStopWorker{}
+	manager.AddRecoverableUnit(w, "slow")
+
+	status := waitState(t, manager, "slow", UnitRunning)
+	for status.Restarts != 1 {
+		status = waitState(t, manager, "slow", UnitRunning)
+	}
+	if w.overlap.Load() {
+		t.Error("the restarted run started before the panicked run was done")
+	}
+
+	// a failed run is also waited for when the unit is started again
+	w = &slowStopWorker{}
+	manager.AddUnit(w, "failing")
+	waitState(t, manager, "failing", UnitFailed)
+	if err := manager.StartUnit("failing"); err != nil {
+		t.Fatal(err)
+	}
+	waitState(t, manager, "failing", UnitRunning)
+	if w.overlap.Load() {
+		t.Error("the started run overlaps the failed run")
+	}
+}
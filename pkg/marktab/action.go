@@ -0,0 +1,239 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package marktab
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blob42/gosuki"
+)
+
+type ActionType int
+
+// Built-in actions a rule can run
+const (
+	// Run a shell command
+	ActionShell ActionType = iota
+
+	// Add and remove tags: `tag +keep -todo`
+	ActionTag
+
+	// Replace the description
+	ActionDesc
+
+	// Mark the bookmark as archived
+	ActionArchive
+
+	// Send a desktop notification
+	ActionNotify
+
+	// POST the bookmark as JSON to an URL
+	ActionPost
+)
+
+var actionNames = map[string]ActionType{
+	"shell":   ActionShell,
+	"tag":     ActionTag,
+	"desc":    ActionDesc,
+	"archive": ActionArchive,
+	"notify":  ActionNotify,
+	"post":    ActionPost,
+}
+
+func (t ActionType) String() string {
+	for name, typ := range actionNames {
+		if typ == t {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// Action is what a rule does with a matching bookmark.
+type Action struct {
+	Type ActionType
+
+	// Shell command, description, notification message or POST URL depending
+	// on the action type.
+	Arg string
+
+	AddTags    []string
+	RemoveTags []string
+
+	// Timeout of a shell or post action, 0 uses the configured default
+	Timeout time.Duration
+
+	// Number of retries of a failed shell or post action, -1 uses the
+	// configured default
+	Retries int
+}
+
+// Mutates reports whether the action modifies the bookmark itself. These
+// actions are applied with [Action.Apply], the others are side effects run by
+// the marktab hook.
+func (a Action) Mutates() bool {
+	switch a.Type {
+	case ActionTag, ActionDesc, ActionArchive:
+		return true
+	}
+	return false
+}
+
+// Apply runs a mutating action on bk and reports whether bk was changed.
+func (a Action) Apply(bk *gosuki.Bookmark) bool {
+	switch a.Type {
+	case ActionTag:
+		tags := make([]string, 0, len(bk.Tags)+len(a.AddTags))
+		for _, t := range bk.Tags {
+			if !slices.Contains(a.RemoveTags, t) {
+				tags = append(tags, t)
+			}
+		}
+		for _, t := range a.AddTags {
+			if !slices.Contains(tags, t) {
+				tags = append(tags, t)
+			}
+		}
+		if slices.Equal(tags, bk.Tags) {
+			return false
+		}
+		bk.Tags = tags
+		return true
+
+	case ActionDesc:
+		if bk.Desc == a.Arg {
+			return false
+		}
+		bk.Desc = a.Arg
+		return true
+
+	case ActionArchive:
+		if gosuki.Flags(bk.Flags).Has(gosuki.FlagArchived) {
+			return false
+		}
+		bk.Flags |= int(gosuki.FlagArchived)
+		return true
+	}
+
+	return false
+}
+
+// parseAction parses the part of a rule following `->`
+func parseAction(s string) (Action, error) {
+	s = strings.TrimSpace(s)
+	name, arg := s, ""
+	if i := strings.IndexAny(s, " \t"); i != -1 {
+		name, arg = s[:i], strings.TrimSpace(s[i:])
+	}
+
+	name, opts, hasOpts := strings.Cut(name, "[")
+	typ, ok := actionNames[name]
+	if !ok {
+		return Action{}, fmt.Errorf("unknown action `%s'", name)
+	}
+	action := Action{Type: typ, Retries: -1}
+
+	if hasOpts {
+		if typ != ActionShell && typ != ActionPost {
+			return Action{}, fmt.Errorf("%s does not take options", name)
+		}
+		if err := action.parseOptions(opts); err != nil {
+			return Action{}, err
+		}
+	}
+
+	switch typ {
+	case ActionShell:
+		if arg == "" {
+			return Action{}, errors.New("missing shell command")
+		}
+		action.Arg = arg
+
+	case ActionTag:
+		for t := range strings.FieldsSeq(arg) {
+			switch {
+			case strings.HasPrefix(t, "-") && len(t) > 1:
+				action.RemoveTags = append(action.RemoveTags, t[1:])
+			case strings.HasPrefix(t, "+") && len(t) > 1:
+				action.AddTags = append(action.AddTags, t[1:])
+			default:
+				return Action{}, fmt.Errorf("expected +tag or -tag, got `%s'", t)
+			}
+		}
+		if len(action.AddTags)+len(action.RemoveTags) == 0 {
+			return Action{}, errors.New("missing tags")
+		}
+
+	case ActionDesc, ActionNotify:
+		action.Arg = unquote(arg)
+
+	case ActionArchive:
+		if arg != "" {
+			return Action{}, errors.New("archive takes no argument")
+		}
+
+	case ActionPost:
+		u, err := url.Parse(arg)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return Action{}, fmt.Errorf("invalid URL `%s'", arg)
+		}
+		action.Arg = arg
+	}
+
+	return action, nil
+}
+
+// parseOptions parses the `key=value,...]` options following the action name
+func (a *Action) parseOptions(opts string) error {
+	opts, ok := strings.CutSuffix(opts, "]")
+	if !ok {
+		return errors.New("missing `]' after options")
+	}
+
+	for opt := range strings.SplitSeq(opts, ",") {
+		key, val, _ := strings.Cut(strings.TrimSpace(opt), "=")
+		switch key {
+		case "timeout":
+			d, err := time.ParseDuration(val)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("invalid timeout `%s'", val)
+			}
+			a.Timeout = d
+		case "retry":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid retry `%s'", val)
+			}
+			a.Retries = n
+		default:
+			return fmt.Errorf("unknown option `%s'", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,427 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package marktab
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/blob42/gosuki"
+)
+
+// Fields a matcher can be applied to
+const (
+	FieldURL    = "url"
+	FieldTitle  = "title"
+	FieldModule = "module"
+	FieldFolder = "folder"
+	FieldTag    = "tag"
+	FieldDesc   = "desc"
+)
+
+var fields = []string{
+	FieldURL,
+	FieldTitle,
+	FieldModule,
+	FieldFolder,
+	FieldTag,
+	FieldDesc,
+}
+
+// compiled regular expressions shared by all rules
+var reCache sync.Map
+
+// compile returns a cached compiled version of pattern
+func compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := reCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	reCache.Store(pattern, re)
+	return re, nil
+}
+
+// target is the data a rule expression is evaluated against
+type target struct {
+	bk      *gosuki.Bookmark
+	folders []string
+}
+
+type expr interface {
+	eval(t *target) bool
+}
+
+type andExpr struct{ left, right expr }
+
+func (e andExpr) eval(t *target) bool { return e.left.eval(t) && e.right.eval(t) }
+
+type orExpr struct{ left, right expr }
+
+func (e orExpr) eval(t *target) bool { return e.left.eval(t) || e.right.eval(t) }
+
+type notExpr struct{ e expr }
+
+func (e notExpr) eval(t *target) bool { return !e.e.eval(t) }
+
+// matcher tests a single bookmark field. The `=` operator requires an exact
+// match while `~` matches a regular expression. Fields holding several values
+// (tag, folder) match if any of the values does.
+type matcher struct {
+	field string
+	op    byte
+	value string
+	re    *regexp.Regexp
+}
+
+func newMatcher(field string, op byte, value string) (*matcher, error) {
+	m := &matcher{field: field, op: op, value: value}
+	if op == '~' {
+		re, err := compile(value)
+		if err != nil {
+			return nil, err
+		}
+		m.re = re
+	}
+	return m, nil
+}
+
+func (m *matcher) values(t *target) []string {
+	switch m.field {
+	case FieldURL:
+		return []string{t.bk.URL}
+	case FieldTitle:
+		return []string{t.bk.Title}
+	case FieldModule:
+		return []string{t.bk.Module}
+	case FieldDesc:
+		return []string{t.bk.Desc}
+	case FieldTag:
+		return t.bk.Tags
+	case FieldFolder:
+		return t.folders
+	}
+	return nil
+}
+
+func (m *matcher) eval(t *target) bool {
+	return slices.ContainsFunc(m.values(t), func(v string) bool {
+		if m.op == '~' {
+			return m.re.MatchString(v)
+		}
+		return v == m.value
+	})
+}
+
+// legacyExpr builds the expression equivalent to a `trigger pattern command`
+// rule: the trigger must be one of the tags and the pattern must match the URL
+// or the title.
+func legacyExpr(trigger, pattern string) (expr, error) {
+	tag, _ := newMatcher(FieldTag, '=', trigger)
+	if pattern == "" {
+		return tag, nil
+	}
+
+	url, err := newMatcher(FieldURL, '~', pattern)
+	if err != nil {
+		return nil, err
+	}
+	title, _ := newMatcher(FieldTitle, '~', pattern)
+
+	return andExpr{tag, orExpr{url, title}}, nil
+}
+
+// splitArrow splits a rule line on the first standalone `->` token found
+// outside of quotes.
+func splitArrow(line string) (string, string, bool) {
+	inQuote := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '"':
+			inQuote = !inQuote
+		case '-':
+			if inQuote || !strings.HasPrefix(line[i:], "->") {
+				continue
+			}
+			before := i == 0 || isSpace(line[i-1])
+			after := i+2 == len(line) || isSpace(line[i+2])
+			if before && after {
+				return line[:i], line[i+2:], true
+			}
+		}
+	}
+	return line, "", false
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// tokenize splits an expression on whitespace outside of quotes. Parentheses
+// at the start of a word and unbalanced parentheses at its end are returned as
+// separate tokens so regular expressions can still use groups.
+func tokenize(s string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inQuote := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			cur.WriteByte(c)
+			i++
+			cur.WriteByte(s[i])
+			continue
+		case c == '"':
+			inQuote = !inQuote
+		case isSpace(c) && !inQuote:
+			if cur.Len() > 0 {
+				words = append(words, cur.String())
+				cur.Reset()
+			}
+			continue
+		}
+		cur.WriteByte(c)
+	}
+	if inQuote {
+		return nil, errors.New("unterminated quote")
+	}
+	if cur.Len() > 0 {
+		words = append(words, cur.String())
+	}
+
+	var tokens []string
+	for _, w := range words {
+		for strings.HasPrefix(w, "(") {
+			tokens = append(tokens, "(")
+			w = w[1:]
+		}
+
+		closing := 0
+		for strings.HasSuffix(w, ")") && parenBalance(w) < 0 {
+			closing++
+			w = w[:len(w)-1]
+		}
+
+		if w != "" {
+			tokens = append(tokens, w)
+		}
+		for range closing {
+			tokens = append(tokens, ")")
+		}
+	}
+
+	return tokens, nil
+}
+
+// parenBalance counts unescaped parentheses outside of quotes, opening ones
+// count as +1 and closing ones as -1.
+func parenBalance(s string) int {
+	balance := 0
+	inQuote := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			inQuote = !inQuote
+		case '(':
+			if !inQuote {
+				balance++
+			}
+		case ')':
+			if !inQuote {
+				balance--
+			}
+		}
+	}
+	return balance
+}
+
+// unquote removes the double quotes from s and unescapes `\"`. Other escape
+// sequences are kept as is for regular expressions.
+func unquote(s string) string {
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == '"':
+			out.WriteByte('"')
+			i++
+		case s[i] == '"':
+		default:
+			out.WriteByte(s[i])
+		}
+	}
+	return out.String()
+}
+
+// splitMatcher splits a `field op value` token. It returns false if the token
+// does not start with a known field followed by an operator.
+func splitMatcher(tok string) (string, byte, string, bool) {
+	i := strings.IndexAny(tok, "=~")
+	if i <= 0 || !slices.Contains(fields, tok[:i]) {
+		return "", 0, "", false
+	}
+	return tok[:i], tok[i], tok[i+1:], true
+}
+
+// isExpression reports whether the tokens start a field expression rather
+// than a legacy `trigger pattern command` rule.
+func isExpression(tok string) bool {
+	if tok == "(" || tok == "not" {
+		return true
+	}
+	_, _, _, ok := splitMatcher(tok)
+	return ok
+}
+
+// exprParser is a recursive descent parser over the tokens of an expression.
+// Precedence from highest to lowest is `not`, `and`, `or`. Adjacent matchers
+// are joined with an implicit `and`.
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+func parseExpr(tokens []string) (expr, error) {
+	p := &exprParser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != "" {
+		return nil, &MarktabError{
+			ErrorType: ErrBadMatcher,
+			err:       fmt.Errorf("unexpected `%s'", p.peek()),
+		}
+	}
+	return e, nil
+}
+
+func (p *exprParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *exprParser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "or" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek() {
+		case "", "or", ")":
+			return left, nil
+		case "and":
+			p.next()
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+}
+
+func (p *exprParser) parseUnary() (expr, error) {
+	tok := p.next()
+	switch tok {
+	case "not":
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{e}, nil
+	case "(":
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, &MarktabError{
+				ErrorType: ErrBadMatcher,
+				err:       errors.New("missing `)'"),
+			}
+		}
+		return e, nil
+	case "", ")", "and", "or":
+		return nil, &MarktabError{
+			ErrorType: ErrBadMatcher,
+			err:       errors.New("expected a matcher"),
+		}
+	}
+
+	field, op, value, ok := splitMatcher(tok)
+	if !ok {
+		return nil, &MarktabError{
+			ErrorType: ErrBadMatcher,
+			err: fmt.Errorf("`%s' is not of the form field=value or field~regex (fields: %s)",
+				tok, strings.Join(fields, ", ")),
+		}
+	}
+
+	value = unquote(value)
+	m, err := newMatcher(field, op, value)
+	if err != nil {
+		return nil, &MarktabError{
+			ErrorType: ErrBadPattern,
+			Rule:      &Rule{Pattern: value},
+			err:       err,
+		}
+	}
+	return m, nil
+}
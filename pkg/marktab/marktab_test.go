@@ -0,0 +1,189 @@
+package marktab
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+func parse(t *testing.T, src string) *MarkTab {
+	t.Helper()
+	mt := &MarkTab{}
+	require.NoError(t, mt.Parse(strings.NewReader(src)))
+	return mt
+}
+
+func TestParseLegacy(t *testing.T) {
+	mt := parse(t, `
+# comment
+notify   .*       my_notify_script --flag # trailing comment
+`)
+	require.Len(t, mt.Rules, 1)
+	rule := mt.Rules[0]
+	assert.Equal(t, "notify", rule.Trigger)
+	assert.Equal(t, ".*", rule.Pattern)
+	assert.Equal(t, "my_notify_script --flag", rule.Command)
+	assert.Equal(t, ActionShell, rule.Action.Type)
+	assert.Equal(t, 3, rule.Line)
+
+	bk := &gosuki.Bookmark{URL: "https://example.com", Tags: []string{"notify"}}
+	assert.True(t, rule.Match(bk))
+	bk.Tags = []string{"other"}
+	assert.False(t, rule.Match(bk))
+}
+
+func TestMatchExpressions(t *testing.T) {
+	bk := &gosuki.Bookmark{
+		URL:    "https://github.com/blob42/gosuki",
+		Title:  "Gosuki: a bookmark manager",
+		Tags:   []string{"go", "read"},
+		Module: "firefox",
+	}
+	folders := []string{"Dev", "Bookmarks Menu"}
+
+	tests := []struct {
+		expr  string
+		match bool
+	}{
+		{`url~github\.com`, true},
+		{`url~gitlab\.com`, false},
+		{`tag=read url~github`, true},
+		{`tag=read and url~gitlab`, false},
+		{`tag=todo or module=firefox`, true},
+		{`not module=chrome`, true},
+		{`not (module=chrome or tag=go)`, false},
+		{`(tag=todo or tag=go) title~(?i)bookmark`, true},
+		{`url~(gitlab|github)\.com`, true},
+		{`folder=Dev`, true},
+		{`folder="Bookmarks Menu"`, true},
+		{`folder=Other`, false},
+		{`title="Gosuki: a bookmark manager"`, true},
+		{`desc=`, true},
+		{`desc~.`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			mt := parse(t, tt.expr+" -> archive")
+			require.Len(t, mt.Rules, 1)
+			assert.Equal(t, tt.match, mt.Rules[0].Match(bk, folders...))
+		})
+	}
+}
+
+func TestParseActions(t *testing.T) {
+	mt := parse(t, `
+tag=a -> shell echo "$GOSUKI_URL" -> done
+tag=a -> tag +keep -todo
+tag=a -> desc "hello world"
+tag=a -> notify
+tag=a -> post https://example.com/hook#frag
+`)
+	require.Len(t, mt.Rules, 5)
+
+	assert.Equal(t, ActionShell, mt.Rules[0].Action.Type)
+	assert.Equal(t, `echo "$GOSUKI_URL" -> done`, mt.Rules[0].Command)
+
+	assert.Equal(t, []string{"keep"}, mt.Rules[1].Action.AddTags)
+	assert.Equal(t, []string{"todo"}, mt.Rules[1].Action.RemoveTags)
+
+	assert.Equal(t, "hello world", mt.Rules[2].Action.Arg)
+	assert.Equal(t, ActionNotify, mt.Rules[3].Action.Type)
+	assert.Equal(t, "https://example.com/hook#frag", mt.Rules[4].Action.Arg)
+}
+
+func TestApplyActions(t *testing.T) {
+	bk := &gosuki.Bookmark{Tags: []string{"todo", "go"}}
+
+	tag := Action{Type: ActionTag, AddTags: []string{"keep", "go"}, RemoveTags: []string{"todo"}}
+	assert.True(t, tag.Apply(bk))
+	assert.Equal(t, []string{"go", "keep"}, bk.Tags)
+	assert.False(t, tag.Apply(bk))
+
+	archive := Action{Type: ActionArchive}
+	assert.True(t, archive.Apply(bk))
+	assert.Equal(t, int(gosuki.FlagArchived), bk.Flags)
+	assert.Equal(t, []string{"go", "keep"}, bk.Tags)
+	assert.False(t, archive.Apply(bk))
+
+	desc := Action{Type: ActionDesc, Arg: "new"}
+	assert.True(t, desc.Apply(bk))
+	assert.Equal(t, "new", bk.Desc)
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		errType ErrorType
+		line    int
+	}{
+		{"\nurl~( -> archive", ErrBadPattern, 2},
+		{"tag=a -> explode", ErrBadAction, 1},
+		{"tag=a -> tag keep", ErrBadAction, 1},
+		{"tag=a -> post ftp://host", ErrBadAction, 1},
+		{"tag=a archive", ErrBadRule, 1},
+		{"tag=a or -> archive", ErrBadMatcher, 1},
+		{"(tag=a -> archive", ErrBadMatcher, 1},
+		{"tag=a foo -> archive", ErrBadMatcher, 1},
+		{"notify", ErrBadRule, 1},
+		{"\n\nnotify ( cmd", ErrBadPattern, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			mt := &MarkTab{}
+			err := mt.Parse(strings.NewReader(tt.src))
+			require.Error(t, err)
+
+			var mte *MarktabError
+			require.True(t, errors.As(err, &mte))
+			assert.Equal(t, tt.errType, mte.ErrorType)
+			assert.Equal(t, tt.line, mte.Line)
+			assert.True(t, strings.HasPrefix(err.Error(), "marktab:"), err.Error())
+		})
+	}
+}
+
+func TestRegexCache(t *testing.T) {
+	re1, err := compile(`foo\d+`)
+	require.NoError(t, err)
+	re2, err := compile(`foo\d+`)
+	require.NoError(t, err)
+	assert.Same(t, re1, re2)
+}
+
+func TestActionOptions(t *testing.T) {
+	mt := parse(t, `
+tag=a -> shell[timeout=5m,retry=2] archive.sh
+tag=a -> shell archive.sh
+`)
+	require.Len(t, mt.Rules, 2)
+	assert.Equal(t, 5*time.Minute, mt.Rules[0].Action.Timeout)
+	assert.Equal(t, 2, mt.Rules[0].Action.Retries)
+	assert.Equal(t, "archive.sh", mt.Rules[0].Command)
+
+	assert.Zero(t, mt.Rules[1].Action.Timeout)
+	assert.Equal(t, -1, mt.Rules[1].Action.Retries, "defaults to config")
+
+	for _, src := range []string{
+		"tag=a -> shell[timeout=soon] x",
+		"tag=a -> shell[retry=-1] x",
+		"tag=a -> shell[nice=1] x",
+		"tag=a -> tag[retry=1] +x",
+	} {
+		err := (&MarkTab{}).Parse(strings.NewReader(src))
+		assert.Error(t, err, src)
+	}
+}
+
+func TestRuleHash(t *testing.T) {
+	mt := parse(t, "tag=a -> shell x\ntag=a -> shell x\ntag=a -> shell y")
+	assert.Equal(t, mt.Rules[0].Hash(), mt.Rules[1].Hash())
+	assert.NotEqual(t, mt.Rules[0].Hash(), mt.Rules[2].Hash())
+}
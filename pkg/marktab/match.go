@@ -23,27 +23,38 @@
 package marktab
 
 import (
-	"regexp"
+	"crypto/sha256"
+	"encoding/hex"
 
 	"github.com/blob42/gosuki"
 )
 
-// Match checks if a bookmark matches the rule based on its title, URL, and
-// tags. It returns true if both the trigger (part of the tag) or the pattern
-// match is satisfied, otherwise it returns false.
-func (rule Rule) Match(bk *gosuki.Bookmark) bool {
-	var triggerMatch bool
+// Hash identifies the rule by its content. It changes when the rule is edited
+// so that bookmarks already handled by the old rule trigger the new one.
+func (rule Rule) Hash() string {
+	src := rule.Source
+	if src == "" {
+		src = rule.Trigger + " " + rule.Pattern + " " + rule.Command
+	}
+	sum := sha256.Sum256([]byte(src))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Match checks if a bookmark matches the rule expression. folders lists the
+// names of the parent folders of the bookmark when they are known, they are
+// used by the `folder` matcher.
+func (rule Rule) Match(bk *gosuki.Bookmark, folders ...string) bool {
 	if bk == nil {
 		return false
 	}
-	for _, tag := range bk.Tags {
-		if rule.Trigger == tag {
-			triggerMatch = true
+
+	e := rule.expr
+	if e == nil {
+		var err error
+		if e, err = legacyExpr(rule.Trigger, rule.Pattern); err != nil {
+			return false
 		}
 	}
-	patternMatch := rule.Pattern == "" || regexp.MustCompile(rule.Pattern).MatchString(bk.URL) || regexp.MustCompile(rule.Pattern).MatchString(bk.Title)
-	if triggerMatch && patternMatch {
-		return true
-	}
-	return false
+
+	return e.eval(&target{bk: bk, folders: folders})
 }
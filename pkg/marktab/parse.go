@@ -20,13 +20,50 @@
 //  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
 //
 
-// Package marktab handles reading and parsing of marktab files, inspired by the crontab file format. Marktab files are text files that define rules and actions to execute when gosuki detects a bookmark with tags matching the defined rule.
+// Package marktab handles reading and parsing of marktab files, inspired by the crontab file format. Marktab files are text files that define rules and actions to execute when gosuki detects a bookmark matching the defined rule.
 //
 // # Marktab Format:
 //
-// Each line in a marktab file represents a rule.
-// A rule consists of three fields: trigger, pattern, and command.
-// The syntax for each line is as follows:
+// Each line in a marktab file represents a rule. A rule is an expression
+// matched against the bookmark followed by `->` and the action to run:
+//
+//	#  expression                       action
+//	#  |                                |
+//	tag=read url~github\.com          -> tag +github -read
+//	folder=Recipes or title~(?i)recipe -> shell my_recipe_script
+//	module=firefox not tag=seen       -> notify "new firefox bookmark"
+//
+// # Expression:
+//
+// An expression is made of matchers of the form `field=value` (exact match) or
+// `field~regex` (regular expression). The available fields are url, title,
+// module, folder, tag and desc. A tag or folder matcher succeeds if any of
+// the bookmark tags or parent folders match. Values containing spaces can be
+// quoted: `title="my title"`.
+//
+// Matchers are combined with `and`, `or`, `not` and parentheses. Adjacent
+// matchers are joined with an implicit `and`.
+//
+// # Actions:
+//
+//	shell <command>    run the shell command
+//	tag +add -remove   add or remove tags
+//	desc <text>        replace the description
+//	archive            mark the bookmark as archived
+//	notify [message]   send a desktop notification
+//	post <url>         POST the bookmark as JSON to the url
+//
+// Shell commands receive the bookmark in the GOSUKI_URL, GOSUKI_TITLE,
+// GOSUKI_TAGS and GOSUKI_MODULE environment variables.
+//
+// The shell and post actions accept options overriding the defaults of the
+// [marktab] config section:
+//
+//	tag=archive -> shell[timeout=5m,retry=2] archive_page.sh
+//
+// # Legacy format:
+//
+// Lines without `->` use the original three fields format: trigger, pattern, and command.
 //
 //	#  *  *   *
 //	#  |  |   |_____ shell command to execute
@@ -37,27 +74,21 @@
 //
 //	notify		.*		my_notify_script
 //
-// The syntax of each line expects an expression made of three field: trigger, pattern and command.
-//
-// # Trigger:
-//
-// The keyword to detect in the bookmark tags. When gosuki parses bookmark tags and finds this trigger keyword, it evaluates the pattern rule. If the pattern matches, the corresponding command is executed.
-//
-// # Pattern:
-//
-// A regular expression used for matching against a part of the bookmark URL or title. Once a trigger is detected, the pattern is evaluated to determine if there's a match with the bookmark data.
+// The trigger is the keyword to detect in the bookmark tags and the pattern a
+// regular expression matched against the bookmark URL or title. It is
+// equivalent to:
 //
-// # Command:
-//
-// The shell command to execute when both the trigger and pattern are matched in a bookmark tag. This command can be any valid shell command and it allows for flexibility in performing various actions.
+//	tag=notify (url~.* or title~.*) -> shell my_notify_script
 package marktab
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/blob42/gosuki/internal/utils"
 	"github.com/blob42/gosuki/pkg/logging"
@@ -69,10 +100,15 @@ type MarkTab struct {
 }
 
 type Rule struct {
-	Trigger string // keyword to detect in the bookmark tags
-	Pattern string // regular expression used for matching against the bookmark URL or title.
-	Command string // shell command to execute when both the trigger and pattern match the bookmark tags.
+	Trigger string // keyword to detect in the bookmark tags (legacy format)
+	Pattern string // regular expression used for matching against the bookmark URL or title (legacy format)
+	Command string // shell command to execute when the rule matches
+
+	Action Action // action to run when the rule matches
+	Line   int    // line number in the marktab file
+	Source string // rule as written in the marktab file
 
+	expr  expr // compiled match expression
 	empty bool // empty is an unexported field indicating whether the rule is empty.
 }
 
@@ -90,6 +126,10 @@ const (
 	ErrBadTrigger
 
 	ErrBadRule
+
+	ErrBadMatcher
+
+	ErrBadAction
 )
 
 const InvalidFormat = "invalid format"
@@ -97,51 +137,60 @@ const InvalidFormat = "invalid format"
 var (
 	log         = logging.GetLogger("marktab")
 	CachedRules *MarkTab
+
+	// protects CachedRules when reloading the marktab
+	rulesMu sync.RWMutex
 )
 
 type MarktabError struct {
 	ErrorType
 	Rule    *Rule
+	Line    int
 	Context string
 	err     error
 }
 
-func errInvalidFormat(context string, a ...any) error {
-	if len(a) > 0 {
-		return fmt.Errorf("\n %s\n\ninvalid format: %s", context, a[0])
-	}
-
-	return fmt.Errorf("\n %s\n\ninvalid format", context)
-}
-
-func errBadPattern(pat string, context string, err error) error {
-	return MarktabError{
-		ErrorType: ErrBadPattern,
-		Rule:      &Rule{Pattern: pat},
-		Context:   context,
-		err:       err,
-	}.Error()
-}
-
-// TODO:
-func (mte MarktabError) Error() error {
-	var outErr string
+func (mte *MarktabError) Error() string {
+	var msg string
 	switch mte.ErrorType {
 	case ErrBadPattern:
-		outErr = fmt.Sprintf("\n %s\n\ninvalid pattern: `%s'", mte.Context, mte.Rule.Pattern)
+		msg = "invalid pattern"
+		if mte.Rule != nil {
+			msg = fmt.Sprintf("invalid pattern `%s'", mte.Rule.Pattern)
+		}
 	case ErrBadTrigger:
-		outErr = fmt.Sprintf(" %s\ninvalid trigger: `%s'", mte.Context, mte.Rule.Trigger)
-	case ErrBadRule:
-		outErr = fmt.Sprintf(" %s\ninvalid rule", mte.Context)
+		msg = "invalid trigger"
+		if mte.Rule != nil {
+			msg = fmt.Sprintf("invalid trigger `%s'", mte.Rule.Trigger)
+		}
+	case ErrBadMatcher:
+		msg = "invalid expression"
+	case ErrBadAction:
+		msg = "invalid action"
+	default:
+		msg = "invalid rule"
 	}
 	if mte.err != nil {
-		return fmt.Errorf("%s : %w", outErr, mte.err)
+		msg = fmt.Sprintf("%s: %s", msg, mte.err)
+	}
+
+	prefix := "marktab"
+	if mte.Line > 0 {
+		prefix = fmt.Sprintf("marktab:%d", mte.Line)
 	}
+	if mte.Context == "" {
+		return fmt.Sprintf("%s: %s", prefix, msg)
+	}
+	return fmt.Sprintf("%s: %s\n\t%s", prefix, msg, mte.Context)
+}
 
-	return fmt.Errorf("%s", outErr)
+func (mte *MarktabError) Unwrap() error {
+	return mte.err
 }
 
 func PreloadRules() error {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
 	if CachedRules == nil {
 		CachedRules = &MarkTab{}
 		return CachedRules.LoadMarktabs()
@@ -149,6 +198,37 @@ func PreloadRules() error {
 	return nil
 }
 
+// ReloadRules parses the marktab file again and replaces the cached rules. On
+// error, the previous rules are kept.
+func ReloadRules() error {
+	mt := &MarkTab{}
+	if err := mt.LoadMarktabs(); err != nil {
+		return err
+	}
+
+	rulesMu.Lock()
+	CachedRules = mt
+	rulesMu.Unlock()
+
+	log.Info("reloaded marktab", "rules", len(mt.Rules))
+	return nil
+}
+
+// GetRules returns the cached marktab rules
+func GetRules() []Rule {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	if CachedRules == nil {
+		return nil
+	}
+	return CachedRules.Rules
+}
+
+// Path returns the expanded path of the marktab file
+func Path() (string, error) {
+	return utils.ExpandOnly(marktabPath)
+}
+
 func (mt *MarkTab) LoadMarktabs() error {
 	path, err := utils.ExpandPath(marktabPath)
 	if os.IsNotExist(err) {
@@ -164,53 +244,126 @@ func (mt *MarkTab) LoadMarktabs() error {
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	return mt.Parse(file)
+}
+
+// Parse reads rules from r, one per line
+func (mt *MarkTab) Parse(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	lineno := 0
 	for scanner.Scan() {
+		lineno++
 		line := scanner.Text()
 		rule, err := parseLine(line)
 		if err != nil {
+			var mte *MarktabError
+			if errors.As(err, &mte) {
+				mte.Line = lineno
+			}
 			return err
 		}
 		if !rule.empty {
+			rule.Line = lineno
 			mt.Rules = append(mt.Rules, rule)
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return err
-	}
-
-	return nil
+	return scanner.Err()
 }
 
 func parseLine(line string) (Rule, error) {
-	line = skipComments(line)
+	line = strings.TrimSpace(skipComments(line))
 	if len(line) == 0 {
 		return Rule{empty: true}, nil
 	}
 
-	fields := strings.Fields(line)
+	rule, err := parseRule(line)
+	rule.Source = line
+	if err != nil {
+		var mte *MarktabError
+		if !errors.As(err, &mte) {
+			mte = &MarktabError{ErrorType: ErrBadRule, err: err}
+		}
+		mte.Context = line
+		return Rule{}, mte
+	}
+	return rule, nil
+}
 
-	// if len(fields) != 3 {
-	// 	return Rule{}, errInvalidFormat(line, "wrong number of fields")
-	// }
+func parseRule(line string) (Rule, error) {
+	exprPart, actionPart, hasArrow := splitArrow(line)
+	tokens, err := tokenize(exprPart)
+	if err != nil {
+		return Rule{}, err
+	}
+	if len(tokens) == 0 {
+		return Rule{}, &MarktabError{
+			ErrorType: ErrBadMatcher,
+			err:       errors.New("missing expression"),
+		}
+	}
+
+	if !isExpression(tokens[0]) {
+		return parseLegacyRule(line)
+	}
+
+	if !hasArrow {
+		return Rule{}, errors.New("missing `->' between expression and action")
+	}
+
+	e, err := parseExpr(tokens)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	action, err := parseAction(actionPart)
+	if err != nil {
+		return Rule{}, &MarktabError{ErrorType: ErrBadAction, err: err}
+	}
+
+	rule := Rule{Action: action, expr: e}
+	if action.Type == ActionShell {
+		rule.Command = action.Arg
+	}
+	return rule, nil
+}
+
+// parseLegacyRule parses a `trigger pattern command` line
+func parseLegacyRule(line string) (Rule, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return Rule{}, errors.New("expected `trigger pattern command' or `expression -> action'")
+	}
 
 	trigger := fields[0]
 	pattern := fields[1]
 	command := strings.Join(fields[2:], " ")
 
-	// Validate pattern (basic check, can be extended based on requirements)
-	_, err := regexp.Compile(pattern)
+	e, err := legacyExpr(trigger, pattern)
 	if err != nil {
-		return Rule{}, errBadPattern(pattern, line, err)
+		return Rule{}, &MarktabError{
+			ErrorType: ErrBadPattern,
+			Rule:      &Rule{Pattern: pattern},
+			err:       err,
+		}
 	}
 
-	return Rule{Trigger: trigger, Pattern: pattern, Command: command}, nil
+	return Rule{
+		Trigger: trigger,
+		Pattern: pattern,
+		Command: command,
+		Action:  Action{Type: ActionShell, Arg: command, Retries: -1},
+		expr:    e,
+	}, nil
 }
 
+// skipComments strips everything after a `#` found at the start of the line
+// or after a whitespace, so that `#` can still be used in patterns and URLs.
 func skipComments(line string) string {
-	if idx := strings.Index(line, "#"); idx != -1 {
-		return line[:idx]
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || isSpace(line[i-1])) {
+			return line[:i]
+		}
 	}
 	return line
 }
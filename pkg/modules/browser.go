@@ -22,6 +22,7 @@
 package modules
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"time"
@@ -31,6 +32,7 @@ import (
 	"github.com/blob42/gosuki/internal/database"
 	"github.com/blob42/gosuki/internal/index"
 	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/browsers"
 	"github.com/blob42/gosuki/pkg/logging"
 	"github.com/blob42/gosuki/pkg/profiles"
 	"github.com/blob42/gosuki/pkg/tree"
@@ -73,6 +75,17 @@ type Detected struct {
 	BasePath string
 }
 
+// BackupReader is implemented by browsers keeping backup copies of their
+// bookmarks in the profile directory, such as the Firefox bookmarkbackups.
+// The backups can be restored with `gosuki import backup`.
+type BackupReader interface {
+	// Backups lists the bookmark backups of the profile, most recent first
+	Backups(p *profiles.Profile) ([]browsers.Backup, error)
+
+	// LoadBackup reads the bookmarks of a backup returned by Backups
+	LoadBackup(b browsers.Backup) ([]*gosuki.Bookmark, error)
+}
+
 // The profile preferences for modules with builtin profile management.
 type ProfilePrefs struct {
 	// Whether to watch all the profiles for multi-profile modules
@@ -127,6 +140,9 @@ func (b *BrowserConfig) GetWatcher() *watch.WatchDescriptor {
 // were registered. This is usually done within the parsing logic of a browser
 // module, typically in the Run() method. These hooks will be called everytime
 // browser bookmarks are parsed.
+//
+// A node dropped by a hook is marked as [tree.Node.Dropped] and the remaining
+// hooks are skipped. For bookmarks, [hooks.ErrDrop] is returned.
 func (b BrowserConfig) CallHooks(obj any) error {
 
 	switch obj := obj.(type) {
@@ -136,10 +152,17 @@ func (b BrowserConfig) CallHooks(obj any) error {
 			return fmt.Errorf("hook node is nil")
 		}
 
+		node.Dropped = false
 		for _, hook := range b.hooks {
 			if hook, ok := hook.(hooks.Hook[*tree.Node]); ok {
 				log.Tracef("<%s> calling hook <%s> on node <%s>", b.Name, hook.Name(), node.URL)
-				if err := hook.Func(node); err != nil {
+				err := hook.Func(node)
+				if errors.Is(err, hooks.ErrDrop) {
+					log.Debugf("<%s> hook <%s> dropped <%s>", b.Name, hook.Name(), node.URL)
+					node.Dropped = true
+					return nil
+				}
+				if err != nil {
 					return err
 				}
 			}
@@ -262,6 +285,7 @@ func SetupBrowser(browser BrowserModule, c *Context, p *profiles.Profile) error
 		}
 		bConf.AddHooks(hook)
 	}
+	bConf.AddHooks(hooks.ScriptHooks()...)
 
 	// Init browsers' BufferDB
 	buffer, err := database.NewBuffer(bConf.Name)
@@ -39,6 +39,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/urfave/cli/v3"
 
@@ -214,10 +215,19 @@ func GetModules() []Module {
 	return result
 }
 
+// Returns all registered modules, including disabled ones
+func GetAllModules() []Module {
+	return slices.Clone(registeredModules)
+}
+
 func Disable(id ModID) {
 	disabledMods[id] = true
 }
 
+func Enable(id ModID) {
+	delete(disabledMods, id)
+}
+
 func Disabled(id ModID) bool {
 	return disabledMods[id]
 }
@@ -27,6 +27,7 @@ package modules
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/blob42/gosuki/internal/database"
@@ -74,16 +75,18 @@ type Listener struct {
 }
 
 func (lw Listener) Run(m manager.UnitManager) {
+	ctx, cancel := context.WithCancel(lw.Ctx)
 	go func() {
 		defer func() {
 			if err := recover(); err != nil {
 				m.Panic(fmt.Errorf("%v", err))
 			}
 		}()
-		lw.MsgListen(lw.Ctx, lw.Queue)
+		lw.MsgListen(ctx, lw.Queue)
 	}()
 
 	<-m.ShouldStop()
+	cancel()
 	m.Done()
 }
 
@@ -95,23 +98,44 @@ type listener struct {
 // dispatchers messages between modules
 type modMsgDispatcher struct {
 	listeners map[ModID]listener
+	mu        sync.RWMutex
 }
 
 func (mm *modMsgDispatcher) AddListener(id ModID, queue chan<- ModMsg) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
 	mm.listeners[id] = listener{id, queue}
 }
 
+func (mm *modMsgDispatcher) RemoveListener(id ModID) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	delete(mm.listeners, id)
+}
+
+func (mm *modMsgDispatcher) getListener(id ModID) (listener, bool) {
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+	l, ok := mm.listeners[id]
+	return l, ok
+}
+
 func (mm *modMsgDispatcher) Run(m manager.UnitManager) {
 
 	// interval at which we check if we need to trigger a sync
 	checkSyncTicker := time.NewTicker(time.Second * 5)
+	defer checkSyncTicker.Stop()
+
+	quit := make(chan struct{})
 	go func() {
 		log.Debug("dispatching module messages")
 		for {
 			select {
+			case <-quit:
+				return
 			case msg := <-ModMsgBus:
 				log.Debug("dispatching mod message", "msg", msg.Type, "to", msg.To)
-				if dst, ok := mm.listeners[msg.To]; ok {
+				if dst, ok := mm.getListener(msg.To); ok {
 					log.Trace("sending", "msg", msg.Type, "to-mod", msg.To)
 					dst.queue <- msg
 				} else { // discard
@@ -119,7 +143,7 @@ func (mm *modMsgDispatcher) Run(m manager.UnitManager) {
 				}
 			case <-checkSyncTicker.C:
 				trigger := database.SyncTrigger.Load()
-				if dst, ok := mm.listeners["p2p-sync"]; ok && trigger {
+				if dst, ok := mm.getListener("p2p-sync"); ok && trigger {
 					dst.queue <- ModMsg{MsgTriggerSync, "", nil}
 				}
 				database.SyncTrigger.Store(false)
@@ -133,6 +157,7 @@ func (mm *modMsgDispatcher) Run(m manager.UnitManager) {
 
 	// Wait for stop signal
 	<-m.ShouldStop()
+	close(quit)
 	m.Done()
 }
 
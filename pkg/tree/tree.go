@@ -51,7 +51,10 @@ type Node struct {
 	Tags       []string
 	Desc       string
 	Module     string
+	Flags      int    // user flags, see gosuki.Bookmark
+	Created    uint64 // unix time the bookmark was added in the browser
 	HasChanged bool
+	Dropped    bool   // dropped by a hook, the bookmark is not saved
 	NameHash   uint64 // hash of the metadata
 	Parent     *Node
 	Children   []*Node
@@ -286,10 +289,12 @@ func (node *Node) GetBookmark() *gosuki.Bookmark {
 	}
 
 	return &gosuki.Bookmark{
-		URL:    node.URL,
-		Title:  node.Title,
-		Desc:   node.Desc,
-		Tags:   node.getTags(),
-		Module: node.Module,
+		URL:     node.URL,
+		Title:   node.Title,
+		Desc:    node.Desc,
+		Tags:    node.getTags(),
+		Module:  node.Module,
+		Flags:   node.Flags,
+		Created: node.Created,
 	}
 }
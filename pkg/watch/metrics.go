@@ -0,0 +1,55 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package watch
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	watcherEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gosuki_watcher_events_total",
+		Help: "Number of file system events received by watchers.",
+	}, []string{"watcher"})
+
+	watcherReducedEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gosuki_watcher_reduced_events_total",
+		Help: "Number of watcher events merged by the reducer into a single run.",
+	}, []string{"watcher"})
+
+	watcherRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gosuki_watcher_runs_total",
+		Help: "Number of bookmark loads triggered by watchers.",
+	}, []string{"watcher"})
+
+	pollerRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gosuki_poller_runs_total",
+		Help: "Number of fetches done by pollers.",
+	}, []string{"module"})
+
+	pollerErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gosuki_poller_errors_total",
+		Help: "Number of failed fetches by pollers.",
+	}, []string{"module"})
+)
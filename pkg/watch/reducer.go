@@ -29,7 +29,9 @@ func ReduceEvents(interval time.Duration,
 	w WatchRunner) {
 	watch := w.Watch()
 	log.Debugf("starting reducer service for %s", watch.ID)
+	watch.reduceInterval.Store(int64(interval))
 
+	done, _ := watch.current()
 	eventsIn := w.Watch().eventsChan
 	timer := time.NewTimer(interval)
 	beat := time.NewTicker(1 * time.Second).C
@@ -45,9 +47,15 @@ func ReduceEvents(interval time.Duration,
 		case <-beat:
 			// log.Debugf("reducer beat %s", watch.ID)
 
+		case <-done:
+			log.Debugf("stopping reducer service for %s", watch.ID)
+			return
+
 		case <-timer.C:
 			if len(events) > 0 {
 				log.Debug("<reduce>: calling Run()")
+				watcherRuns.WithLabelValues(watch.ID).Inc()
+				watcherReducedEvents.WithLabelValues(watch.ID).Add(float64(len(events) - 1))
 				w.Run()
 
 				// Empty events queue
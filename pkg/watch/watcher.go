@@ -25,6 +25,8 @@ package watch
 import (
 	"fmt"
 	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/blob42/gosuki"
@@ -115,9 +117,21 @@ type WatchDescriptor struct {
 	// eventsChan is a channel used for communicating events related to the watches. It's buffered and has a size determined by fsnotify.BufferSize().
 	eventsChan chan fsnotify.Event
 
+	// mu guards the state below, which is replaced when the descriptor is
+	// reopened by a restarted unit
+	mu sync.Mutex
+
 	// isWatching is a boolean flag that indicates whether this WatchDescriptor is actively watching any file or directory.
 	isWatching bool
 
+	// done is closed when the watch descriptor is closed
+	done   chan struct{}
+	closed bool
+
+	// interval of the reducer started with ReduceEvents, used to start it
+	// again when the watcher is reopened
+	reduceInterval atomic.Int64
+
 	// List of unique event names that where encountered
 	// Useful to track unique filenames in a watched path
 	TrackEventNames bool
@@ -130,7 +144,70 @@ func (w *WatchDescriptor) AddEventName(name string) {
 	}
 }
 
-func (w WatchDescriptor) hasReducer() bool {
+// Close stops the watch loop and the reducer then closes the underlying
+// fsnotify watcher.
+func (w *WatchDescriptor) Close() error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.done)
+	}
+	fswatcher := w.W
+	w.mu.Unlock()
+	return fswatcher.Close()
+}
+
+// reopen creates a new fsnotify watcher for the watched paths of a closed
+// watch descriptor. It is used when a watch unit is started again.
+func (w *WatchDescriptor) reopen() error {
+	fswatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating fsnotify watcher: %w", err)
+	}
+
+	for _, v := range w.Watches {
+		if err = fswatcher.Add(v.Path); err != nil {
+			fswatcher.Close()
+			return fmt.Errorf("adding watch path: %s", v.Path)
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.W = fswatcher
+	w.done = make(chan struct{})
+	w.closed = false
+	w.isWatching = false
+	return nil
+}
+
+func (w *WatchDescriptor) isClosed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.closed
+}
+
+// current returns the done channel and the fsnotify watcher the descriptor
+// is using
+func (w *WatchDescriptor) current() (<-chan struct{}, *fsnotify.Watcher) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.done, w.W
+}
+
+// startWatching marks the descriptor as watching. It reports whether it was
+// not watching yet.
+func (w *WatchDescriptor) startWatching() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.isWatching {
+		return false
+	}
+	w.isWatching = true
+	return true
+}
+
+func (w *WatchDescriptor) hasReducer() bool {
 	return w.eventsChan != nil
 }
 
@@ -161,6 +238,7 @@ func NewWatcher(name string, watches ...*Watch) (*WatchDescriptor, error) {
 		W:          fswatcher,
 		Watches:    watches,
 		eventsChan: nil,
+		done:       make(chan struct{}),
 	}
 
 	// Add all watched paths
@@ -222,7 +300,22 @@ func (w WatchLoad) Run(m manager.UnitManager) {
 // shared watch running implementation for all units that rely on FS watching
 func watchRun(w Watcher, m manager.UnitManager) {
 	watcher := w.Watch()
-	if !watcher.isWatching {
+
+	// the unit is restarted
+	if watcher.isClosed() {
+		if err := watcher.reopen(); err != nil {
+			m.Panic(err)
+			return
+		}
+
+		if interval := watcher.reduceInterval.Load(); interval > 0 {
+			if ww, ok := w.(WatchWork); ok {
+				go ReduceEvents(time.Duration(interval), ww.WatchRunner)
+			}
+		}
+	}
+
+	if watcher.startWatching() {
 		switch w := w.(type) {
 		case WatchLoad:
 			go WatchLoop(w.WatchLoader)
@@ -230,8 +323,6 @@ func watchRun(w Watcher, m manager.UnitManager) {
 			go WatchLoop(w.WatchRunner)
 		}
 
-		watcher.isWatching = true
-
 		for _, watch := range watcher.Watches {
 			log.Debugf("Watching %s", watch.Path)
 		}
@@ -247,6 +338,11 @@ func watchRun(w Watcher, m manager.UnitManager) {
 			m.Panic(err)
 		}
 	}
+
+	// the watcher could have been reset since the unit started
+	if err := w.Watch().Close(); err != nil {
+		log.Error("closing watcher", "err", err)
+	}
 	m.Done()
 }
 
@@ -258,28 +354,53 @@ type PollWork struct {
 }
 
 func (iw PollWork) Run(m manager.UnitManager) {
-	go poll(iw.Poller, iw.Name)
+	done := make(chan struct{})
+	go poll(iw.Poller, iw.Name, done)
 	// wait for stop signal
 	<-m.ShouldStop()
+	close(done)
+
+	if sht, ok := iw.Poller.(Shutdowner); ok {
+		if err := sht.Shutdown(); err != nil {
+			log.Error("shutting down", "module", iw.Name, "err", err)
+		}
+	}
 	m.Done()
 }
 
 // Main gorouting for polling bookmarks at regular intervals
 // One goroutine spawned per module
-func poll(ir Poller, modName string) {
+func poll(ir Poller, modName string, done <-chan struct{}) {
+	interval := ir.Interval()
+	log.Debug("polling", "module", modName, "interval", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
-	log.Debug("polling", "module", modName, "interval", ir.Interval())
-	beat := time.NewTicker(ir.Interval()).C
+	pollOnce(ir, modName)
+	for {
+		select {
+		case <-done:
+			log.Debug("stopped polling", "module", modName)
+			return
+		case <-ticker.C:
+			pollOnce(ir, modName)
+
+			// the interval can change when the config is reloaded
+			if newInterval := ir.Interval(); newInterval != interval {
+				log.Debug("poll interval changed", "module", modName, "interval", newInterval)
+				interval = newInterval
+				ticker.Reset(interval)
+			}
+		}
+	}
+}
 
+func pollOnce(ir Poller, modName string) {
+	pollerRuns.WithLabelValues(modName).Inc()
 	if err := database.LoadBookmarks(ir.Fetch, modName); err != nil {
+		pollerErrors.WithLabelValues(modName).Inc()
 		log.Error("loading bookmarks", "module", modName, "err", err)
 	}
-	for range beat {
-		if err := database.LoadBookmarks(ir.Fetch, modName); err != nil {
-			log.Error("loading bookmarks", "module", modName, "err", err)
-		}
-	}
-
 }
 
 // Main thread for watching file changes
@@ -293,6 +414,9 @@ func WatchLoop(w any) {
 
 	watch := watcher.Watch()
 	beat := time.NewTicker(1 * time.Second).C
+
+	// the descriptor is reopened in place when the unit is restarted
+	done, fsw := watch.current()
 	log.Debugf("<%s> Started watcher", watch.ID)
 watchloop:
 	for {
@@ -300,7 +424,14 @@ watchloop:
 		select {
 		case <-beat:
 		// log.Debugf("main watch loop beat %s", watcher.ID)
-		case event := <-watch.W.Events:
+		case <-done:
+			log.Debugf("<%s> stopped watcher", watch.ID)
+			return
+		case event, ok := <-fsw.Events:
+			if !ok {
+				// the watcher was closed or reset
+				return
+			}
 			// Very verbose
 			log.Trace("event", "OP", event.Op, "eventName", event.Name)
 
@@ -329,6 +460,8 @@ watchloop:
 						if event.Op&watchedEv == watchedEv &&
 							(watchedName == "*" || event.Name == watchedName) {
 
+							watcherEvents.WithLabelValues(watch.ID).Inc()
+
 							// For watchers who use a reducer forward the event
 							// to the reducer channel
 							if watch.hasReducer() {
@@ -337,6 +470,7 @@ watchloop:
 
 								// the reducer will call Run()
 							} else {
+								watcherRuns.WithLabelValues(watch.ID).Inc()
 								go func() {
 									if counter, ok := w.(parsing.Counter); ok {
 										counter.ResetCount()
@@ -368,7 +502,10 @@ watchloop:
 				}
 			}
 
-		case err := <-watch.W.Errors:
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return
+			}
 			if err != nil {
 				log.Error(err)
 			}
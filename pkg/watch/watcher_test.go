@@ -0,0 +1,74 @@
+package watch
+
+import (
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki/pkg/manager"
+)
+
+type testRunner struct {
+	watcher *WatchDescriptor
+	runs    atomic.Int32
+}
+
+func (r *testRunner) Watch() *WatchDescriptor { return r.watcher }
+
+func (r *testRunner) Run() { r.runs.Add(1) }
+
+// panickingWork panics once after its first run started watching
+type panickingWork struct {
+	WatchWork
+	started atomic.Int32
+}
+
+func (w *panickingWork) Run(m manager.UnitManager) {
+	if w.started.Add(1) == 1 {
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			m.Panic("boom")
+		}()
+	}
+	w.WatchWork.Run(m)
+}
+
+func TestWatchUnitRestart(t *testing.T) {
+	backoff := manager.RestartBackoff
+	manager.RestartBackoff = 10 * time.Millisecond
+	t.Cleanup(func() { manager.RestartBackoff = backoff })
+
+	dir := t.TempDir()
+	watcher, err := NewWatcher("test", &Watch{
+		Path:       dir,
+		EventTypes: []fsnotify.Op{fsnotify.Create},
+		EventNames: []string{"*"},
+	})
+	require.NoError(t, err)
+
+	runner := &testRunner{watcher: watcher}
+	work := &panickingWork{WatchWork: WatchWork{runner}}
+
+	m := manager.NewManager()
+	m.AddRecoverableUnit(work, "watch")
+
+	require.Eventually(t, func() bool {
+		return work.started.Load() == 2 && !watcher.isClosed()
+	}, 5*time.Second, 10*time.Millisecond, "the unit was not restarted")
+
+	// the reopened watcher receives the events
+	require.Eventually(t, func() bool {
+		f, err := os.CreateTemp(dir, "bookmarks")
+		require.NoError(t, err)
+		f.Close()
+		return runner.runs.Load() > 0
+	}, 5*time.Second, 50*time.Millisecond)
+
+	require.NoError(t, m.StopUnit("watch"))
+	assert.True(t, watcher.isClosed())
+}